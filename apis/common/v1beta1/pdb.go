@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// PodDisruptionBudgetSection - PodDisruptionBudget settings of a service
+type PodDisruptionBudgetSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// Enabled - create a PodDisruptionBudget when the service runs more than one replica
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	// MaxUnavailable - number or percentage of pods which may be evicted at the same time. When not
+	// set it is derived from the replicas so that a majority of them keeps running.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// GetMaxUnavailable returns the maxUnavailable of the PodDisruptionBudget for
+// the given replicas, or nil if none should exist
+func (p *PodDisruptionBudgetSection) GetMaxUnavailable(replicas int32) *intstr.IntOrString {
+	if replicas < 2 || (p.Enabled != nil && !*p.Enabled) {
+		return nil
+	}
+	if p.MaxUnavailable != nil {
+		return p.MaxUnavailable
+	}
+	maxUnavailable := intstr.FromInt32(max(1, (replicas-1)/2))
+	return &maxUnavailable
+}
+
+// ValidatePodDisruptionBudget - a maxUnavailable covering all replicas would
+// not protect anything
+func (p *PodDisruptionBudgetSection) ValidatePodDisruptionBudget(
+	basePath *field.Path,
+	replicas int32,
+) field.ErrorList {
+	var allErrs field.ErrorList
+	if p.MaxUnavailable == nil {
+		return allErrs
+	}
+	path := basePath.Child("podDisruptionBudget").Child("maxUnavailable")
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(p.MaxUnavailable, int(replicas), false)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(path, p.MaxUnavailable.String(), err.Error()))
+	} else if maxUnavailable < 0 || (replicas > 1 && maxUnavailable >= int(replicas)) {
+		allErrs = append(allErrs, field.Invalid(path, p.MaxUnavailable.String(),
+			fmt.Sprintf("must be between 0 and the number of replicas (%d) minus one", replicas)))
+	}
+	return allErrs
+}
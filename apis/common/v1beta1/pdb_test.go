@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+func TestGetMaxUnavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		section  PodDisruptionBudgetSection
+		replicas int32
+		want     *intstr.IntOrString
+	}{
+		{
+			name:     "should not protect a single replica",
+			replicas: 1,
+			want:     nil,
+		},
+		{
+			name:     "should allow one eviction for two replicas",
+			replicas: 2,
+			want:     ptr.To(intstr.FromInt32(1)),
+		},
+		{
+			name:     "should keep a majority of five replicas",
+			replicas: 5,
+			want:     ptr.To(intstr.FromInt32(2)),
+		},
+		{
+			name:     "should use the configured value",
+			section:  PodDisruptionBudgetSection{MaxUnavailable: ptr.To(intstr.FromString("50%"))},
+			replicas: 3,
+			want:     ptr.To(intstr.FromString("50%")),
+		},
+		{
+			name:     "should not create one when disabled",
+			section:  PodDisruptionBudgetSection{Enabled: ptr.To(false)},
+			replicas: 3,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.section.GetMaxUnavailable(tt.replicas)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("GetMaxUnavailable() = %v, want nil", got.String())
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Errorf("GetMaxUnavailable() = %v, want %v", got, tt.want.String())
+			}
+		})
+	}
+}
+
+func TestValidatePodDisruptionBudget(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxUnavailable *intstr.IntOrString
+		replicas       int32
+		wantErr        bool
+	}{
+		{
+			name:     "should accept the derived value",
+			replicas: 3,
+		},
+		{
+			name:           "should accept less than the replicas",
+			maxUnavailable: ptr.To(intstr.FromInt32(2)),
+			replicas:       3,
+		},
+		{
+			name:           "should refuse all replicas",
+			maxUnavailable: ptr.To(intstr.FromInt32(3)),
+			replicas:       3,
+			wantErr:        true,
+		},
+		{
+			name:           "should refuse 100%",
+			maxUnavailable: ptr.To(intstr.FromString("100%")),
+			replicas:       3,
+			wantErr:        true,
+		},
+		{
+			name:           "should refuse an invalid percentage",
+			maxUnavailable: ptr.To(intstr.FromString("half")),
+			replicas:       3,
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PodDisruptionBudgetSection{MaxUnavailable: tt.maxUnavailable}
+			errs := p.ValidatePodDisruptionBudget(field.NewPath("spec"), tt.replicas)
+			if (len(errs) != 0) != tt.wantErr {
+				t.Errorf("ValidatePodDisruptionBudget() = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+)
+
+const (
+	// ClientConfigSecretSuffix - suffix of the secret holding the client configuration
+	ClientConfigSecretSuffix = "-client-config"
+	// ClientConfigOsloCacheKey - key of the oslo.cache [cache] section in the client config secret
+	ClientConfigOsloCacheKey = "oslo-cache.conf"
+	// ClientConfigJSONKey - key of the JSON encoded MemcachedClientConfig in the client config secret
+	ClientConfigJSONKey = "memcached.json"
+
+	// BackendDogpileMemcached - python-memcached based backend, understands the inet(6) prefix
+	BackendDogpileMemcached = "dogpile.cache.memcached"
+	// BackendDogpilePymemcache - pymemcache based backend, required for TLS
+	BackendDogpilePymemcache = "dogpile.cache.pymemcache"
+	// BackendMemcachePool - oslo.cache pooled backend, required for SASL authentication
+	BackendMemcachePool = "oslo_cache.memcache_pool"
+)
+
+// MemcachedClientConfig describes how clients connect to a Memcached
+type MemcachedClientConfig struct {
+	// Servers - memcached endpoints to use with Backend
+	Servers []string `json:"servers"`
+
+	// ServersWithInet - memcached endpoints with inet(6) prefix
+	ServersWithInet []string `json:"serversWithInet"`
+
+	// Backend - oslo.cache backend supporting the TLS and authentication settings
+	Backend string `json:"backend"`
+
+	// TLS - TLS settings of the clients
+	TLS MemcachedClientTLSConfig `json:"tls"`
+
+	// Auth - SASL settings of the clients
+	Auth MemcachedClientAuthConfig `json:"auth"`
+}
+
+// MemcachedClientTLSConfig contains the TLS settings of the clients
+type MemcachedClientTLSConfig struct {
+	// Enabled - whether the clients have to connect with TLS
+	Enabled bool `json:"enabled"`
+
+	// CAFile - CA bundle to verify the servers with
+	CAFile string `json:"caFile,omitempty"`
+
+	// CertFile - client certificate when MTLS is enabled
+	CertFile string `json:"certFile,omitempty"`
+
+	// KeyFile - client key when MTLS is enabled
+	KeyFile string `json:"keyFile,omitempty"`
+
+	// MTLSSecret - secret holding the client certificate, see CreateMTLSVolumeMounts
+	MTLSSecret string `json:"mtlsSecret,omitempty"`
+}
+
+// MemcachedClientAuthConfig contains the SASL settings of the clients
+type MemcachedClientAuthConfig struct {
+	// Enabled - whether the clients have to authenticate
+	Enabled bool `json:"enabled"`
+
+	// Secret - secret holding the SASL credentials, see CreateAuthVolumeMounts
+	Secret string `json:"secret,omitempty"`
+}
+
+// GetMemcachedClientConfig - return the client configuration matching the status of the
+// memcached instance
+func (instance *Memcached) GetMemcachedClientConfig() MemcachedClientConfig {
+	config := MemcachedClientConfig{
+		Servers:         instance.Status.ServerListWithInet,
+		ServersWithInet: instance.Status.ServerListWithInet,
+		Backend:         BackendDogpileMemcached,
+	}
+	if instance.GetMemcachedTLSSupport() {
+		config.Servers = instance.Status.ServerList
+		config.Backend = BackendDogpilePymemcache
+		config.TLS = MemcachedClientTLSConfig{
+			Enabled: true,
+			CAFile:  tls.DownstreamTLSCABundlePath,
+		}
+		if mtlsSecret := instance.GetMemcachedMTLSSecret(); mtlsSecret != "" {
+			config.TLS.CertFile = CertMountPath()
+			config.TLS.KeyFile = KeyMountPath()
+			config.TLS.MTLSSecret = mtlsSecret
+		}
+	}
+	if instance.GetMemcachedAuthEnabled() {
+		config.Servers = instance.Status.ServerList
+		config.Backend = BackendMemcachePool
+		config.Auth = MemcachedClientAuthConfig{
+			Enabled: true,
+			Secret:  instance.GetMemcachedAuthSecret(),
+		}
+	}
+	return config
+}
+
+// GetMemcachedClientConfigSecret - return the secret holding the client configuration
+func (instance *Memcached) GetMemcachedClientConfigSecret() string {
+	return instance.Status.ClientConfigSecret
+}
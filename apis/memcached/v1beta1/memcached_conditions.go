@@ -19,4 +19,24 @@ package v1beta1
 const (
 	MTLSInputReadyCondition      = "MTLS Certificate Ready"
 	MTLSInputReadyWaitingMessage = "Waiting for MTLS Certificate"
+	// CertReloadWaitingMessage - the renewed certificate is not loaded by all pods yet
+	CertReloadWaitingMessage = "Waiting for %s to reload the renewed certificate"
+)
+
+// Staged resize conditions
+const (
+	// MemcachedResizeReadyCondition - reports the progress of a staged resize
+	MemcachedResizeReadyCondition = "ResizeReady"
+
+	// MemcachedResizeReadyInitMessage -
+	MemcachedResizeReadyInitMessage = "Resize not started"
+
+	// MemcachedResizeReadyMessage -
+	MemcachedResizeReadyMessage = "No resize in progress"
+
+	// MemcachedResizeWarmingUpMessage -
+	MemcachedResizeWarmingUpMessage = "Resizing from %d to %d replicas, warming up new servers"
+
+	// MemcachedResizeDrainingMessage -
+	MemcachedResizeDrainingMessage = "Resizing from %d to %d replicas, draining removed servers"
 )
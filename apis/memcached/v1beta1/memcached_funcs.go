@@ -46,6 +46,17 @@ const (
 	DefaultCertMountDir = "/var/lib/config-data/mtls/certs"
 	// DefaultKeyMountDir - default path to mount cert keys inside container
 	DefaultKeyMountDir = "/var/lib/config-data/mtls/private"
+
+	// AuthSecretSuffix - suffix of the secret holding the SASL credentials
+	AuthSecretSuffix = "-auth"
+	// AuthUsernameKey - SASL username key in the auth secret
+	AuthUsernameKey = "username"
+	// AuthPasswordKey - SASL password key in the auth secret
+	AuthPasswordKey = "password"
+	// AuthVolumeName - name of the volume holding the SASL credentials
+	AuthVolumeName = "memcached-auth"
+	// DefaultAuthMountDir - default path to mount the SASL credentials inside container
+	DefaultAuthMountDir = "/var/lib/config-data/memcached-auth"
 )
 
 // IsReady - returns true if Memcached is reconciled successfully
@@ -72,7 +83,8 @@ func (instance Memcached) RbacResourceName() string {
 func SetupDefaults() {
 	// Acquire environmental defaults and initialize Memcached defaults with them
 	memcachedDefaults := MemcachedDefaults{
-		ContainerImageURL: util.GetEnvVar("RELATED_IMAGE_INFRA_MEMCACHED_IMAGE_URL_DEFAULT", MemcachedContainerImage),
+		ContainerImageURL:         util.GetEnvVar("RELATED_IMAGE_INFRA_MEMCACHED_IMAGE_URL_DEFAULT", MemcachedContainerImage),
+		ExporterContainerImageURL: util.GetEnvVar("RELATED_IMAGE_INFRA_MEMCACHED_EXPORTER_IMAGE_URL_DEFAULT", MemcachedExporterContainerImage),
 	}
 
 	SetupMemcachedDefaults(memcachedDefaults)
@@ -84,6 +96,58 @@ func (instance *Memcached) GetMemcachedServerListString() string {
 	return strings.Join(instance.Status.ServerList, ",")
 }
 
+// GetMemcachedAuthSecret - return the secret holding the SASL credentials, empty if
+// authentication is disabled
+func (instance *Memcached) GetMemcachedAuthSecret() string {
+	return instance.Status.AuthSecret
+}
+
+// GetMemcachedAuthEnabled - return true if clients have to authenticate
+func (instance *Memcached) GetMemcachedAuthEnabled() bool {
+	return instance.Status.AuthSecret != ""
+}
+
+// CreateAuthVolume - add volume for the SASL credentials
+func (instance *Memcached) CreateAuthVolume() corev1.Volume {
+	volume := corev1.Volume{}
+	if instance.Status.AuthSecret != "" {
+		volume = corev1.Volume{
+			Name: AuthVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: instance.Status.AuthSecret,
+					Items: []corev1.KeyToPath{
+						{Key: AuthUsernameKey, Path: AuthUsernameKey},
+						{Key: AuthPasswordKey, Path: AuthPasswordKey},
+					},
+					DefaultMode: ptr.To[int32](0400),
+				},
+			},
+		}
+	}
+
+	return volume
+}
+
+// CreateAuthVolumeMounts - add volume mount for the SASL credentials, the username and
+// password files get mounted into AuthMountPath or DefaultAuthMountDir
+func (instance *Memcached) CreateAuthVolumeMounts(AuthMountPath *string) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{}
+	if instance.Status.AuthSecret != "" {
+		mountPath := DefaultAuthMountDir
+		if AuthMountPath != nil {
+			mountPath = *AuthMountPath
+		}
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      AuthVolumeName,
+			MountPath: mountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return volumeMounts
+}
+
 // GetMemcachedServerListQuotedString - return the memcached servers, each quoted, as comma separated list
 // to be used in OpenStack config.
 func (instance *Memcached) GetMemcachedServerListQuotedString() string {
@@ -102,6 +166,29 @@ func (instance *Memcached) GetMemcachedServerListWithInetQuotedString() string {
 	return "'" + strings.Join(instance.Status.ServerListWithInet, "','") + "'"
 }
 
+// GetMemcachedPoolServerListString - return the memcached servers of the pool as comma
+// separated list to be used in OpenStack config, empty if there is no such pool.
+func (instance *Memcached) GetMemcachedPoolServerListString(pool string) string {
+	for _, p := range instance.Status.Pools {
+		if p.Name == pool {
+			return strings.Join(p.ServerList, ",")
+		}
+	}
+	return ""
+}
+
+// GetMemcachedPoolServerListWithInetString - return the memcached servers of the pool with
+// inet(6) prefix as comma separated list to be used in OpenStack config, empty if there is
+// no such pool.
+func (instance *Memcached) GetMemcachedPoolServerListWithInetString(pool string) string {
+	for _, p := range instance.Status.Pools {
+		if p.Name == pool {
+			return strings.Join(p.ServerListWithInet, ",")
+		}
+	}
+	return ""
+}
+
 // GetMemcachedTLSSupport - return the TLS support of the memcached instance
 func (instance *Memcached) GetMemcachedTLSSupport() bool {
 	return instance.Status.TLSSupport
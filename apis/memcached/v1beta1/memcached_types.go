@@ -17,10 +17,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
+	commonv1 "github.com/openstack-k8s-operators/infra-operator/apis/common/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -31,6 +36,9 @@ const (
 	// MemcachedContainerImage is the fall-back container image for Memcached
 	MemcachedContainerImage = "quay.io/podified-antelope-centos9/openstack-memcached:current-podified"
 
+	// MemcachedExporterContainerImage is the fall-back container image for the memcached_exporter sidecar
+	MemcachedExporterContainerImage = "quay.io/prometheus/memcached-exporter:v0.15.0"
+
 	// CrMaxLengthCorrection - DNS1123LabelMaxLength (63) - CrMaxLengthCorrection used in validation to
 	// omit issue with statefulset pod label "controller-revision-hash": "<statefulset_name>-<hash>"
 	// Int32 is a 10 character + hyphen = 11
@@ -44,6 +52,11 @@ type MemcachedSpec struct {
 	// +kubebuilder:validation:Required
 	// Name of the memcached container image to run (will be set to environmental default if empty)
 	ContainerImage string `json:"containerImage"`
+
+	// +kubebuilder:validation:Optional
+	// Name of the memcached_exporter container image to run when metrics are enabled
+	// (will be set to environmental default if empty)
+	ExporterImage string `json:"exporterImage,omitempty"`
 }
 
 // MemcachedSpecCore - this version is used by the OpenStackControlplane CR (no container images)
@@ -64,6 +77,16 @@ type MemcachedSpecCore struct {
 	// TLS settings for memcached service
 	TLS TLSSection `json:"tls,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Auth settings for memcached service
+	Auth AuthSection `json:"auth,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Metrics settings for memcached service
+	Metrics MetricsSection `json:"metrics,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=9932
 	// Maximum Memcached cache size in MB
@@ -74,6 +97,31 @@ type MemcachedSpecCore struct {
 	// Maximum number of connections accepted by Memcached
 	MaxConn int32 `json:"maxConn"`
 
+	// +kubebuilder:validation:Optional
+	// Threads - number of worker threads, memcached uses 4 when not set
+	Threads *int32 `json:"threads,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ItemSizeMax - maximum size of an item, e.g. 2Mi. Must be between 1Ki and 1Gi and not more than
+	// half of the cache size. Memcached uses 1Mi when not set.
+	ItemSizeMax *resource.Quantity `json:"itemSizeMax,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Eviction settings for memcached service
+	Eviction EvictionSection `json:"eviction,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=2
+	// Verbosity - log verbosity of memcached between 0 (errors only) and 3
+	Verbosity int32 `json:"verbosity"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Extstore - extends the cache with a flash/disk backed store on a PVC per pod. Can not be
+	// changed once the Memcached got created.
+	Extstore *ExtstoreSection `json:"extstore,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// TopologyRef to apply the Topology defined by the associated CR referenced
 	// by name
@@ -82,6 +130,71 @@ type MemcachedSpecCore struct {
 	// +kubebuilder:validation:Optional
 	// Resources QoS configuration for pods
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// PodOverride - Override configuration for per-pod services. When specified, a service gets created
+	// for each pod with the provided configuration, LoadBalancer services get registered with dnsmasq and
+	// the server lists point to their external IPs so that clients outside the cluster can reach them.
+	PodOverride *PodOverride `json:"podOverride,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Resize - how replica changes get rolled out to the consumers of the server lists
+	Resize ResizeSection `json:"resize,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// PodDisruptionBudget - limits how many pods a node drain may evict at the same time
+	PodDisruptionBudget commonv1.PodDisruptionBudgetSection `json:"podDisruptionBudget,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Autoscaling - adjust the replicas between minReplicas and maxReplicas to the memory pressure
+	// reported by the exporters. When set, spec.replicas is only the initial number of replicas.
+	Autoscaling *AutoscalingSection `json:"autoscaling,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Pools - additional sets of memcached servers with their own size, e.g. one per kind of cached
+	// data. Each pool gets its own StatefulSet and server lists and shares the TLS, authentication,
+	// tunables and topology settings. Metrics, autoscaling, per-pod services and staged resizes only
+	// apply to the servers from spec.replicas.
+	Pools []MemcachedPool `json:"pools,omitempty"`
+}
+
+// MemcachedPool defines an additional set of memcached servers
+type MemcachedPool struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=20
+	// Name - of the pool, its StatefulSet and service are named <memcached name>-<pool name>
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	// Replicas - number of memcached servers in the pool
+	Replicas *int32 `json:"replicas"`
+
+	// +kubebuilder:validation:Optional
+	// CacheSize - maximum cache size of each server in MB, defaults to spec.cacheSize
+	CacheSize *int32 `json:"cacheSize,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Resources - QoS configuration for the pods of the pool, defaults to spec.resources
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// PodOverride defines per-pod service configurations
+type PodOverride struct {
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	// Services - list of per-pod service overrides, one per replica
+	Services []service.OverrideSpec `json:"services,omitempty"`
 }
 
 // TLSSection contains TLS and MTLS configuration
@@ -105,6 +218,192 @@ type MTLSSection struct {
 	AuthCertSecret tls.GenericService `json:"authCertSecret,omitempty"`
 }
 
+// AuthSection contains SASL authentication configuration
+type AuthSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled turns on SASL authentication, clients have to use the binary protocol
+	Enabled bool `json:"enabled"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=memcached
+	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9_.-]+$"
+	// Username clients authenticate with, the password gets generated
+	Username string `json:"username,omitempty"`
+}
+
+// EvictionSection contains the memcached eviction settings
+type EvictionSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Disabled - return errors when the memory is exhausted instead of evicting items
+	Disabled bool `json:"disabled"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// LRUCrawler - reclaim the memory of expired items in the background
+	LRUCrawler *bool `json:"lruCrawler,omitempty"`
+}
+
+// ExtstoreSection contains the memcached extstore configuration
+type ExtstoreSection struct {
+	// +kubebuilder:validation:Required
+	// StorageRequest - size of the PVC holding the extstore file, at least 1Gi
+	StorageRequest string `json:"storageRequest"`
+
+	// +kubebuilder:validation:Optional
+	// StorageClass - storage class of the PVC, the cluster default when not set
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// MetricsSection contains the memcached_exporter configuration
+type MetricsSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled adds a memcached_exporter sidecar to the pods and a ServiceMonitor if the
+	// Prometheus operator is installed. Not supported together with SASL authentication.
+	Enabled bool `json:"enabled"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern="^[0-9]+(ms|s|m|h)$"
+	// ScrapeInterval - how often Prometheus scrapes the exporters
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+}
+
+const (
+	// ResizeStrategyImmediate - the server lists follow the replicas right away
+	ResizeStrategyImmediate = "Immediate"
+	// ResizeStrategyStaged - new servers warm up before they get listed, removed
+	// servers get unlisted before their pods get deleted
+	ResizeStrategyStaged = "Staged"
+
+	// ResizePhaseWarmingUp - new pods are running but not listed yet
+	ResizePhaseWarmingUp = "WarmingUp"
+	// ResizePhaseDraining - removed servers are unlisted but their pods are kept
+	ResizePhaseDraining = "Draining"
+)
+
+// ResizeSection controls how replica changes are applied
+type ResizeSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Immediate
+	// +kubebuilder:validation:Enum=Immediate;Staged
+	// Strategy - Immediate updates the server lists together with the replicas. Staged adds new
+	// servers to the server lists only after they warmed up and deletes the pods of removed servers
+	// only after a grace period, so that consumers rehash once per resize.
+	Strategy string `json:"strategy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=0
+	// WarmupSeconds - how long new pods have to be ready before they get added to the server lists
+	WarmupSeconds int32 `json:"warmupSeconds"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=0
+	// GracePeriodSeconds - how long removed servers keep running after they got removed from the
+	// server lists
+	GracePeriodSeconds int32 `json:"gracePeriodSeconds"`
+}
+
+// AutoscalingSection contains the bounds and targets of the autoscaler
+type AutoscalingSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// MinReplicas - lower limit for the number of replicas
+	MinReplicas int32 `json:"minReplicas"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// MaxReplicas - upper limit for the number of replicas
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// TargetMemoryUtilization - percentage of the cache memory of all replicas which should be in use
+	TargetMemoryUtilization int32 `json:"targetMemoryUtilization"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// TargetEvictionRate - evictions per second and replica above which another replica gets added,
+	// evictions are ignored when not set
+	TargetEvictionRate *int32 `json:"targetEvictionRate,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=0
+	// ScaleDownStabilizationSeconds - how long after the last scaling the replicas may get reduced
+	ScaleDownStabilizationSeconds int32 `json:"scaleDownStabilizationSeconds"`
+}
+
+// AutoscalingStatus reports the last decision of the autoscaler
+type AutoscalingStatus struct {
+	// DesiredReplicas - number of replicas chosen by the autoscaler
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// MemoryUtilization - percentage of the cache memory of all replicas in use
+	MemoryUtilization int32 `json:"memoryUtilization"`
+
+	// EvictionRate - evictions per second and replica since the previous stats collection
+	EvictionRate string `json:"evictionRate,omitempty"`
+
+	// LastScaleTime - when the autoscaler last changed the replicas
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
+// PoolStatus reports the servers of a pool
+type PoolStatus struct {
+	// Name - of the pool
+	Name string `json:"name"`
+
+	// ReadyCount - number of ready servers of the pool
+	ReadyCount int32 `json:"readyCount,omitempty"`
+
+	// ServerList - List of memcached endpoints of the pool without inet(6) prefix
+	ServerList []string `json:"serverList,omitempty"`
+
+	// ServerListWithInet - List of memcached endpoints of the pool with inet(6) prefix
+	ServerListWithInet []string `json:"serverListWithInet,omitempty"`
+}
+
+// ResizeStatus tracks a staged resize
+type ResizeStatus struct {
+	// From - number of replicas when the resize started
+	From int32 `json:"from"`
+
+	// To - requested number of replicas
+	To int32 `json:"to"`
+
+	// Phase - WarmingUp or Draining
+	Phase string `json:"phase"`
+
+	// Started - when the warmup or the grace period started
+	Started *metav1.Time `json:"started,omitempty"`
+}
+
+// MetricsStatus summarizes the stats reported by the exporters of all replicas
+type MetricsStatus struct {
+	// HitRatio - percentage of get commands which found the key since the pods started
+	HitRatio string `json:"hitRatio,omitempty"`
+
+	// Evictions - number of valid items removed from the cache to free memory
+	Evictions int64 `json:"evictions"`
+
+	// CurrentConnections - number of open connections
+	CurrentConnections int64 `json:"currentConnections"`
+
+	// MaxConnections - maximum number of connections accepted
+	MaxConnections int64 `json:"maxConnections"`
+
+	// LastUpdated - when the stats got collected
+	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+}
+
 // MemcachedStatus defines the observed state of Memcached
 type MemcachedStatus struct {
 	// Map of hashes to track input changes
@@ -122,12 +421,39 @@ type MemcachedStatus struct {
 	// ServerListWithInet - List of memcached endpoints with inet(6) prefix
 	ServerListWithInet []string `json:"serverListWithInet,omitempty" optional:"true"`
 
+	// ListedReplicas - number of replicas in the server lists
+	ListedReplicas int32 `json:"listedReplicas,omitempty"`
+
+	// Resize - progress of a staged resize
+	Resize *ResizeStatus `json:"resize,omitempty"`
+
+	// Pools - server lists of the pools
+	Pools []PoolStatus `json:"pools,omitempty"`
+
+	// ServiceHostnames - hostnames of the per-pod services
+	ServiceHostnames []string `json:"serviceHostnames,omitempty" optional:"true"`
+
 	// Whether TLS is supported by the memcached instance
 	TLSSupport bool `json:"tlsSupport,omitempty"`
 
 	// Name of the certificate used for MTLS
 	MTLSCert string `json:"mtlsCert,omitempty"`
 
+	// Name of the secret holding the SASL credentials
+	AuthSecret string `json:"authSecret,omitempty"`
+
+	// Name of the secret holding the oslo.cache and JSON client configuration
+	ClientConfigSecret string `json:"clientConfigSecret,omitempty"`
+
+	// Metrics - summary of the stats reported by the memcached_exporter sidecars
+	Metrics *MetricsStatus `json:"metrics,omitempty"`
+
+	// Autoscaling - last decision of the autoscaler
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// LastCertReload - when the pods last loaded a renewed TLS certificate without a restart
+	LastCertReload *metav1.Time `json:"lastCertReload,omitempty"`
+
 	// ObservedGeneration - the most recent generation observed for this
 	// service. If the observed generation is less than the spec generation,
 	// then the controller has not processed the latest changes injected by
@@ -165,6 +491,57 @@ func init() {
 	SchemeBuilder.Register(&Memcached{}, &MemcachedList{})
 }
 
+// ValidateMetrics - the exporter can not authenticate against memcached
+func (instance *MemcachedSpecCore) ValidateMetrics(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.Metrics.Enabled && instance.Auth.Enabled {
+		allErrs = append(allErrs, field.Forbidden(
+			basePath.Child("metrics").Child("enabled"),
+			"metrics are not supported together with SASL authentication"))
+	}
+	return allErrs
+}
+
+// ValidateAutoscaling - the autoscaler reads the stats of the exporters and can not
+// change the number of per-pod services
+func (instance *MemcachedSpecCore) ValidateAutoscaling(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.Autoscaling == nil {
+		return allErrs
+	}
+	path := basePath.Child("autoscaling")
+	if !instance.Metrics.Enabled {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("metrics").Child("enabled"), false,
+			"metrics have to be enabled for autoscaling"))
+	}
+	if instance.PodOverride != nil && len(instance.PodOverride.Services) > 0 {
+		allErrs = append(allErrs, field.Forbidden(
+			path, "autoscaling is not supported together with podOverride.services"))
+	}
+	if instance.Autoscaling.MaxReplicas < instance.Autoscaling.MinReplicas {
+		allErrs = append(allErrs, field.Invalid(
+			path.Child("maxReplicas"), instance.Autoscaling.MaxReplicas,
+			fmt.Sprintf("must not be lower than minReplicas (%d)", instance.Autoscaling.MinReplicas)))
+	}
+	return allErrs
+}
+
+// ValidatePodOverride - there has to be one per-pod service per replica
+func (instance *MemcachedSpecCore) ValidatePodOverride(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.PodOverride == nil || len(instance.PodOverride.Services) == 0 || instance.Replicas == nil {
+		return allErrs
+	}
+	if len(instance.PodOverride.Services) != int(*instance.Replicas) {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("podOverride").Child("services"),
+			len(instance.PodOverride.Services),
+			fmt.Sprintf("number of services must match the number of replicas (%d)", *instance.Replicas)))
+	}
+	return allErrs
+}
+
 // ValidateTopology -
 func (instance *MemcachedSpecCore) ValidateTopology(
 	basePath *field.Path,
@@ -23,10 +23,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -36,7 +41,8 @@ import (
 
 // MemcachedDefaults -
 type MemcachedDefaults struct {
-	ContainerImageURL string
+	ContainerImageURL         string
+	ExporterContainerImageURL string
 }
 
 var memcachedDefaults MemcachedDefaults
@@ -64,6 +70,9 @@ func (spec *MemcachedSpec) Default() {
 	if spec.ContainerImage == "" {
 		spec.ContainerImage = memcachedDefaults.ContainerImageURL
 	}
+	if spec.ExporterImage == "" {
+		spec.ExporterImage = memcachedDefaults.ExporterContainerImageURL
+	}
 	spec.MemcachedSpecCore.Default()
 }
 
@@ -90,6 +99,12 @@ func (r *Memcached) ValidateCreate() (admission.Warnings, error) {
 	// referenced because is not supported
 	basePath := field.NewPath("spec")
 	allErrs = append(allErrs, r.Spec.ValidateTopology(basePath, r.Namespace)...)
+	allErrs = append(allErrs, r.Spec.ValidateMetrics(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidatePodOverride(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateAutoscaling(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateTunables(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidatePools(basePath, r.Name)...)
+	allErrs = append(allErrs, r.Spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, ptr.Deref(r.Spec.Replicas, 1))...)
 
 	if len(allErrs) != 0 {
 		return allWarn, apierrors.NewInvalid(
@@ -101,9 +116,14 @@ func (r *Memcached) ValidateCreate() (admission.Warnings, error) {
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *Memcached) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
+func (r *Memcached) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	memcachedlog.Info("validate update", "name", r.Name)
 
+	oldMemcached, ok := old.(*Memcached)
+	if !ok || oldMemcached == nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("unable to convert existing object"))
+	}
+
 	var allErrs field.ErrorList
 	var allWarn []string
 	basePath := field.NewPath("spec")
@@ -111,6 +131,19 @@ func (r *Memcached) ValidateUpdate(_ runtime.Object) (admission.Warnings, error)
 	// When a TopologyRef CR is referenced, fail if a different Namespace is
 	// referenced because is not supported
 	allErrs = append(allErrs, r.Spec.ValidateTopology(basePath, r.Namespace)...)
+	allErrs = append(allErrs, r.Spec.ValidateMetrics(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidatePodOverride(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateAutoscaling(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateTunables(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidatePools(basePath, r.Name)...)
+	allErrs = append(allErrs, r.Spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, ptr.Deref(r.Spec.Replicas, 1))...)
+
+	// the PVCs are created from the volumeClaimTemplates of the statefulset,
+	// which can not be changed
+	if !equality.Semantic.DeepEqual(r.Spec.Extstore, oldMemcached.Spec.Extstore) {
+		allErrs = append(allErrs, field.Forbidden(
+			basePath.Child("extstore"), "extstore can not be changed"))
+	}
 
 	if len(allErrs) != 0 {
 		return allWarn, apierrors.NewInvalid(
@@ -127,3 +160,62 @@ func (r *Memcached) ValidateDelete() (admission.Warnings, error) {
 	// TODO(user): fill in your validation logic upon object deletion.
 	return nil, nil
 }
+
+// ValidateTunables - checks the ranges of the memcached server options
+func (spec *MemcachedSpecCore) ValidateTunables(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Threads != nil && (*spec.Threads < 1 || *spec.Threads > 64) {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("threads"), *spec.Threads, "must be between 1 and 64"))
+	}
+
+	if spec.ItemSizeMax != nil {
+		path := basePath.Child("itemSizeMax")
+		itemSize := spec.ItemSizeMax.Value()
+		switch {
+		case itemSize < 1024 || itemSize > 1024*1024*1024:
+			allErrs = append(allErrs, field.Invalid(
+				path, spec.ItemSizeMax.String(), "must be between 1Ki and 1Gi"))
+		case itemSize > int64(spec.CacheSize)*1024*1024/2:
+			allErrs = append(allErrs, field.Invalid(
+				path, spec.ItemSizeMax.String(),
+				fmt.Sprintf("must not be more than half of the cache size (%dMB)", spec.CacheSize)))
+		}
+	}
+
+	if spec.Verbosity < 0 || spec.Verbosity > 3 {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("verbosity"), spec.Verbosity, "must be between 0 and 3"))
+	}
+
+	if spec.Extstore != nil {
+		path := basePath.Child("extstore").Child("storageRequest")
+		size, err := resource.ParseQuantity(spec.Extstore.StorageRequest)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(path, spec.Extstore.StorageRequest, err.Error()))
+		} else if size.Cmp(resource.MustParse("1Gi")) < 0 {
+			allErrs = append(allErrs, field.Invalid(path, spec.Extstore.StorageRequest, "must be at least 1Gi"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidatePools - the names of the pool StatefulSets have the same limit as the one of the
+// Memcached and the item size has to fit into the cache of each pool
+func (spec *MemcachedSpecCore) ValidatePools(basePath *field.Path, name string) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, pool := range spec.Pools {
+		path := basePath.Child("pools").Index(i)
+		allErrs = append(allErrs, common_webhook.ValidateDNS1123Label(
+			path.Child("name"), []string{name + "-" + pool.Name}, CrMaxLengthCorrection)...)
+		if pool.CacheSize != nil && spec.ItemSizeMax != nil &&
+			spec.ItemSizeMax.Value() > int64(*pool.CacheSize)*1024*1024/2 {
+			allErrs = append(allErrs, field.Invalid(
+				path.Child("cacheSize"), *pool.CacheSize,
+				fmt.Sprintf("must be at least twice itemSizeMax (%s)", spec.ItemSizeMax.String())))
+		}
+	}
+	return allErrs
+}
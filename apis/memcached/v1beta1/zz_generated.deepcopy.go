@@ -23,9 +23,100 @@ package v1beta1
 import (
 	topologyv1beta1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
+	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthSection) DeepCopyInto(out *AuthSection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthSection.
+func (in *AuthSection) DeepCopy() *AuthSection {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthSection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingSection) DeepCopyInto(out *AutoscalingSection) {
+	*out = *in
+	if in.TargetEvictionRate != nil {
+		in, out := &in.TargetEvictionRate, &out.TargetEvictionRate
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingSection.
+func (in *AutoscalingSection) DeepCopy() *AutoscalingSection {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingSection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingStatus) DeepCopyInto(out *AutoscalingStatus) {
+	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingStatus.
+func (in *AutoscalingStatus) DeepCopy() *AutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvictionSection) DeepCopyInto(out *EvictionSection) {
+	*out = *in
+	if in.LRUCrawler != nil {
+		in, out := &in.LRUCrawler, &out.LRUCrawler
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvictionSection.
+func (in *EvictionSection) DeepCopy() *EvictionSection {
+	if in == nil {
+		return nil
+	}
+	out := new(EvictionSection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtstoreSection) DeepCopyInto(out *ExtstoreSection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtstoreSection.
+func (in *ExtstoreSection) DeepCopy() *ExtstoreSection {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtstoreSection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MTLSSection) DeepCopyInto(out *MTLSSection) {
 	*out = *in
@@ -69,6 +160,63 @@ func (in *Memcached) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemcachedClientAuthConfig) DeepCopyInto(out *MemcachedClientAuthConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemcachedClientAuthConfig.
+func (in *MemcachedClientAuthConfig) DeepCopy() *MemcachedClientAuthConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MemcachedClientAuthConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemcachedClientConfig) DeepCopyInto(out *MemcachedClientConfig) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServersWithInet != nil {
+		in, out := &in.ServersWithInet, &out.ServersWithInet
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.TLS = in.TLS
+	out.Auth = in.Auth
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemcachedClientConfig.
+func (in *MemcachedClientConfig) DeepCopy() *MemcachedClientConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MemcachedClientConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemcachedClientTLSConfig) DeepCopyInto(out *MemcachedClientTLSConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemcachedClientTLSConfig.
+func (in *MemcachedClientTLSConfig) DeepCopy() *MemcachedClientTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MemcachedClientTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemcachedDefaults) DeepCopyInto(out *MemcachedDefaults) {
 	*out = *in
@@ -116,6 +264,36 @@ func (in *MemcachedList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemcachedPool) DeepCopyInto(out *MemcachedPool) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CacheSize != nil {
+		in, out := &in.CacheSize, &out.CacheSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemcachedPool.
+func (in *MemcachedPool) DeepCopy() *MemcachedPool {
+	if in == nil {
+		return nil
+	}
+	out := new(MemcachedPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemcachedSpec) DeepCopyInto(out *MemcachedSpec) {
 	*out = *in
@@ -152,12 +330,49 @@ func (in *MemcachedSpecCore) DeepCopyInto(out *MemcachedSpecCore) {
 		}
 	}
 	in.TLS.DeepCopyInto(&out.TLS)
+	out.Auth = in.Auth
+	out.Metrics = in.Metrics
+	if in.Threads != nil {
+		in, out := &in.Threads, &out.Threads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ItemSizeMax != nil {
+		in, out := &in.ItemSizeMax, &out.ItemSizeMax
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	in.Eviction.DeepCopyInto(&out.Eviction)
+	if in.Extstore != nil {
+		in, out := &in.Extstore, &out.Extstore
+		*out = new(ExtstoreSection)
+		**out = **in
+	}
 	if in.TopologyRef != nil {
 		in, out := &in.TopologyRef, &out.TopologyRef
 		*out = new(topologyv1beta1.TopoRef)
 		**out = **in
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.PodOverride != nil {
+		in, out := &in.PodOverride, &out.PodOverride
+		*out = new(PodOverride)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Resize = in.Resize
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingSection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]MemcachedPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemcachedSpecCore.
@@ -197,6 +412,37 @@ func (in *MemcachedStatus) DeepCopyInto(out *MemcachedStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Resize != nil {
+		in, out := &in.Resize, &out.Resize
+		*out = new(ResizeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]PoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceHostnames != nil {
+		in, out := &in.ServiceHostnames, &out.ServiceHostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastCertReload != nil {
+		in, out := &in.LastCertReload, &out.LastCertReload
+		*out = (*in).DeepCopy()
+	}
 	if in.LastAppliedTopology != nil {
 		in, out := &in.LastAppliedTopology, &out.LastAppliedTopology
 		*out = new(topologyv1beta1.TopoRef)
@@ -214,6 +460,118 @@ func (in *MemcachedStatus) DeepCopy() *MemcachedStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsSection) DeepCopyInto(out *MetricsSection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsSection.
+func (in *MetricsSection) DeepCopy() *MetricsSection {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsSection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsStatus) DeepCopyInto(out *MetricsStatus) {
+	*out = *in
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsStatus.
+func (in *MetricsStatus) DeepCopy() *MetricsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodOverride) DeepCopyInto(out *PodOverride) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]service.OverrideSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodOverride.
+func (in *PodOverride) DeepCopy() *PodOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(PodOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolStatus) DeepCopyInto(out *PoolStatus) {
+	*out = *in
+	if in.ServerList != nil {
+		in, out := &in.ServerList, &out.ServerList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServerListWithInet != nil {
+		in, out := &in.ServerListWithInet, &out.ServerListWithInet
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolStatus.
+func (in *PoolStatus) DeepCopy() *PoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResizeSection) DeepCopyInto(out *ResizeSection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResizeSection.
+func (in *ResizeSection) DeepCopy() *ResizeSection {
+	if in == nil {
+		return nil
+	}
+	out := new(ResizeSection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResizeStatus) DeepCopyInto(out *ResizeStatus) {
+	*out = *in
+	if in.Started != nil {
+		in, out := &in.Started, &out.Started
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResizeStatus.
+func (in *ResizeStatus) DeepCopy() *ResizeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResizeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSSection) DeepCopyInto(out *TLSSection) {
 	*out = *in
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	commonv1 "github.com/openstack-k8s-operators/infra-operator/apis/common/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
@@ -85,6 +86,11 @@ type DNSMasqSpecCore struct {
 	// TopologyRef to apply the Topology defined by the associated CR referenced
 	// by name
 	TopologyRef *topologyv1.TopoRef `json:"topologyRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// PodDisruptionBudget - limits how many pods a node drain may evict at the same time
+	PodDisruptionBudget commonv1.PodDisruptionBudgetSection `json:"podDisruptionBudget,omitempty"`
 }
 
 // DNSMasqOverrideSpec to override the generated manifest of several child resources.
@@ -152,6 +158,12 @@ func (instance DNSMasq) IsReady() bool {
 	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
 }
 
+// ServiceDNSDataName returns the name of the DNSData holding the hostnames of the
+// annotated LoadBalancer services registered with the DNSMasq
+func (instance DNSMasq) ServiceDNSDataName() string {
+	return instance.Name + "-svc"
+}
+
 // RbacConditionsSet - set the conditions for the rbac object
 func (instance DNSMasq) RbacConditionsSet(c *condition.Condition) {
 	instance.Status.Conditions.Set(c)
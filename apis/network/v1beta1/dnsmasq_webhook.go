@@ -24,6 +24,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 )
 
 // DNSMasqDefaults -
@@ -77,6 +78,7 @@ func (r *DNSMasq) ValidateCreate() (admission.Warnings, error) {
 	// When a TopologyRef CR is referenced, fail if a different Namespace is
 	// referenced because is not supported
 	allErrs = append(allErrs, r.Spec.ValidateTopology(basePath, r.Namespace)...)
+	allErrs = append(allErrs, r.Spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, ptr.Deref(r.Spec.Replicas, 1))...)
 
 	if len(allErrs) != 0 {
 		return allWarn, apierrors.NewInvalid(
@@ -97,6 +99,7 @@ func (r *DNSMasq) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
 	// When a TopologyRef CR is referenced, fail if a different Namespace is
 	// referenced because is not supported
 	allErrs = append(allErrs, r.Spec.ValidateTopology(basePath, r.Namespace)...)
+	allErrs = append(allErrs, r.Spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, ptr.Deref(r.Spec.Replicas, 1))...)
 
 	if len(allErrs) != 0 {
 		return allWarn, apierrors.NewInvalid(
@@ -430,6 +430,7 @@ func (in *DNSMasqSpecCore) DeepCopyInto(out *DNSMasqSpecCore) {
 		*out = new(topologyv1beta1.TopoRef)
 		**out = **in
 	}
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSMasqSpecCore.
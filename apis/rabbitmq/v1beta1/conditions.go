@@ -26,6 +26,25 @@ const (
 
 	// TransportURLFinalizer - finalizer to add to RabbitMQUsers owned by TransportURL
 	TransportURLFinalizer = "transporturl.rabbitmq.openstack.org/finalizer"
+
+	// TransportURLNameLabel - label set on cross-namespace RabbitMQUsers and RabbitMQVhosts
+	// with the name of the owning TransportURL
+	TransportURLNameLabel = "rabbitmq.openstack.org/transporturl-name"
+
+	// TransportURLNamespaceLabel - label set on cross-namespace RabbitMQUsers and RabbitMQVhosts
+	// with the namespace of the owning TransportURL
+	TransportURLNamespaceLabel = "rabbitmq.openstack.org/transporturl-namespace"
+)
+
+// RabbitMq Condition Types used by API objects.
+const (
+	// RabbitMqServiceDNSReadyCondition Status=True condition which indicates if the per-pod service
+	// hostnames resolve through the DNSMasq addresses
+	RabbitMqServiceDNSReadyCondition condition.Type = "ServiceDNSReady"
+
+	// RabbitMqUpgradeReadyCondition Status=True condition which indicates that no change of the
+	// container image is in progress
+	RabbitMqUpgradeReadyCondition condition.Type = "UpgradeReady"
 )
 
 // TransportURL Reasons used by API objects.
@@ -48,4 +67,51 @@ const (
 
 	// TransportURLInProgressMessage
 	TransportURLInProgressMessage = "TransportURL in progress"
+
+	// TransportURLWaitingServiceDNSMessage
+	TransportURLWaitingServiceDNSMessage = "TransportURL waiting for the per-pod service hostnames of RabbitMq %s to resolve"
+
+	//
+	// RabbitMqServiceDNSReady condition messages
+	//
+
+	// RabbitMqServiceDNSReadyInitMessage
+	RabbitMqServiceDNSReadyInitMessage = "Per-pod service DNS not checked"
+
+	// RabbitMqServiceDNSReadyMessage
+	RabbitMqServiceDNSReadyMessage = "Per-pod service hostnames resolve"
+
+	// RabbitMqServiceDNSReadyWaitingMessage
+	RabbitMqServiceDNSReadyWaitingMessage = "Waiting for per-pod service hostnames to resolve: %s"
+
+	// RabbitMqServiceDNSReadyNoDNSMasqMessage
+	RabbitMqServiceDNSReadyNoDNSMasqMessage = "Waiting for a DNSMasq in namespace %s to become ready"
+
+	// RabbitMqServiceDNSReadyErrorMessage
+	RabbitMqServiceDNSReadyErrorMessage = "Per-pod service DNS error occured %s"
+
+	//
+	// RabbitMqUpgradeReady condition messages
+	//
+
+	// RabbitMqUpgradeReadyInitMessage
+	RabbitMqUpgradeReadyInitMessage = "Upgrade not checked"
+
+	// RabbitMqUpgradeReadyMessage
+	RabbitMqUpgradeReadyMessage = "No upgrade in progress"
+
+	// RabbitMqUpgradeInProgressMessage
+	RabbitMqUpgradeInProgressMessage = "Upgrade from %s to %s in phase %s"
+
+	// RabbitMqUpgradeReadyErrorMessage
+	RabbitMqUpgradeReadyErrorMessage = "Upgrade error occured %s"
+
+	// RabbitMqUpgradeStrategyErrorMessage
+	RabbitMqUpgradeStrategyErrorMessage = "%s upgrade from version %q to %q needs a known running version and a major or minor version change"
+
+	// RabbitMqNamespaceNotAllowedMessage
+	RabbitMqNamespaceNotAllowedMessage = "namespace %s is not allowed to bind to RabbitMq %s/%s"
+
+	// TransportURLCrossNamespaceCredentialsMessage
+	TransportURLCrossNamespaceCredentialsMessage = "TransportURL bound to RabbitMq in namespace %s has invalid credentials: %s"
 )
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	commonv1 "github.com/openstack-k8s-operators/infra-operator/apis/common/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
@@ -51,6 +52,36 @@ const (
 	QueueTypeQuorum = "Quorum"
 	// QueueTypeNone - no special queue type
 	QueueTypeNone = "None"
+
+	// AllowAllNamespaces - AllowedNamespaces entry which allows any namespace to bind
+	AllowAllNamespaces = "*"
+
+	// CertificateSecretNameSuffix - suffix of the default secret name of the per-pod service certificate
+	CertificateSecretNameSuffix = "-svc-cert"
+
+	// UpgradeStrategyInPlace - enable the required feature flags, then roll the new image over the running cluster
+	UpgradeStrategyInPlace = "InPlace"
+	// UpgradeStrategyBlueGreen - move definitions and messages with shovels through a second cluster
+	// running the new version
+	UpgradeStrategyBlueGreen = "BlueGreen"
+
+	// UpgradePhaseEnablingFeatureFlags - the stable feature flags get enabled on the running cluster
+	UpgradePhaseEnablingFeatureFlags = "EnablingFeatureFlags"
+	// UpgradePhaseRollingImage - the new image rolls over the running cluster
+	UpgradePhaseRollingImage = "RollingImage"
+	// UpgradePhaseDeployingGreen - the green cluster with the new image gets deployed
+	UpgradePhaseDeployingGreen = "DeployingGreen"
+	// UpgradePhaseMigratingToGreen - clients use the green cluster while shovels drain the blue one
+	UpgradePhaseMigratingToGreen = "MigratingToGreen"
+	// UpgradePhaseRecreatingBlue - the blue cluster gets recreated with the new image
+	UpgradePhaseRecreatingBlue = "RecreatingBlue"
+	// UpgradePhaseMigratingToBlue - clients use the blue cluster again while shovels drain the green one
+	UpgradePhaseMigratingToBlue = "MigratingToBlue"
+	// UpgradePhaseCompleted - the upgrade finished
+	UpgradePhaseCompleted = "Completed"
+
+	// GreenClusterSuffix - suffix of the RabbitmqCluster deployed during a BlueGreen upgrade
+	GreenClusterSuffix = "-green"
 )
 
 // PodOverride defines per-pod service configurations
@@ -59,6 +90,26 @@ type PodOverride struct {
 	// +listType=atomic
 	// Services - list of per-pod service overrides
 	Services []service.OverrideSpec `json:"services,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Certificate - when set, a cert-manager Certificate is requested which has the cluster and the
+	// per-pod service hostnames as SANs
+	Certificate *PodOverrideCertificate `json:"certificate,omitempty"`
+}
+
+// PodOverrideCertificate defines the certificate requested for the per-pod services
+type PodOverrideCertificate struct {
+	// +kubebuilder:validation:Required
+	// IssuerName - name of the cert-manager issuer which signs the certificate
+	IssuerName string `json:"issuerName"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Issuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// IssuerKind - kind of the cert-manager issuer
+	IssuerKind string `json:"issuerKind,omitempty"`
+	// +kubebuilder:validation:Optional
+	// SecretName - name of the secret the certificate gets stored in. Defaults to <name>-svc-cert.
+	// Set spec.tls.secretName to the same value for RabbitMQ to serve it.
+	SecretName string `json:"secretName,omitempty"`
 }
 
 // RabbitMqSpec defines the desired state of RabbitMq
@@ -67,6 +118,11 @@ type RabbitMqSpec struct {
 	// +kubebuilder:validation:Required
 	// Name of the rabbitmq container image to run (will be set to environmental default if empty)
 	ContainerImage string `json:"containerImage"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+(\.[0-9]+)?$`
+	// TargetVersion - RabbitMQ version shipped in ContainerImage, e.g. 4.0 or 3.13.7. When set, changes of
+	// ContainerImage are checked against the running version and unsafe jumps get refused.
+	TargetVersion string `json:"targetVersion,omitempty"`
 }
 
 // RabbitMqSpecCore - this version is used by the OpenStackControlplane CR (no container images)
@@ -93,6 +149,27 @@ type RabbitMqSpecCore struct {
 	// services will be created for each pod with the provided configuration, and the transport URL will be
 	// configured to use these per-pod services.
 	PodOverride *PodOverride `json:"podOverride,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// AllowedNamespaces - list of namespaces, other than the one of this RabbitMq, whose TransportURL and
+	// RabbitMQUser resources may bind to the cluster by setting rabbitmqClusterNamespace. The special value
+	// "*" allows any namespace. Cross-namespace binding is refused when the list is empty.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=InPlace
+	// +kubebuilder:validation:Enum=InPlace;BlueGreen
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// UpgradeStrategy - how to move to a new RabbitMQ major or minor version. InPlace enables the stable
+	// feature flags before the new image rolls over the cluster. BlueGreen deploys a second cluster with
+	// the new image, moves clients to it and drains the messages with shovels, then recreates this cluster.
+	// BlueGreen requires TargetVersion and a major or minor version change.
+	UpgradeStrategy string `json:"upgradeStrategy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// PodDisruptionBudget - limits how many pods a node drain may evict at the same time,
+	// maxUnavailable defaults to 1
+	PodDisruptionBudget commonv1.PodDisruptionBudgetSection `json:"podDisruptionBudget,omitempty"`
 }
 
 // MarshalInto converts RabbitMqSpec to RabbitmqClusterSpec.
@@ -133,6 +210,41 @@ type RabbitMqStatus struct {
 	// When populated, transport URLs use these hostnames instead of pod names.
 	// +listType=atomic
 	ServiceHostnames []string `json:"serviceHostnames,omitempty"`
+
+	// CertificateSecretName - name of the secret of the certificate requested for the per-pod
+	// service hostnames
+	CertificateSecretName string `json:"certificateSecretName,omitempty"`
+
+	// Version - RabbitMQ version reported by the running cluster
+	Version string `json:"version,omitempty"`
+
+	// Upgrade - state of the last change of the container image
+	Upgrade *RabbitMqUpgradeStatus `json:"upgrade,omitempty"`
+}
+
+// RabbitMqUpgradeStatus defines the observed state of an upgrade
+type RabbitMqUpgradeStatus struct {
+	// Strategy - strategy used for the upgrade
+	Strategy string `json:"strategy,omitempty"`
+
+	// Phase - current phase of the upgrade
+	Phase string `json:"phase,omitempty"`
+
+	// FromVersion - RabbitMQ version running before the upgrade
+	FromVersion string `json:"fromVersion,omitempty"`
+
+	// ToVersion - RabbitMQ version running after the upgrade
+	ToVersion string `json:"toVersion,omitempty"`
+
+	// FromImage - container image running before the upgrade
+	FromImage string `json:"fromImage,omitempty"`
+
+	// ToImage - container image running after the upgrade
+	ToImage string `json:"toImage,omitempty"`
+
+	// ActiveClusterName - name of the RabbitmqCluster clients get pointed to during a
+	// BlueGreen upgrade, empty when it is the one of this RabbitMq
+	ActiveClusterName string `json:"activeClusterName,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -168,6 +280,34 @@ func (instance RabbitMq) IsReady() bool {
 	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
 }
 
+// IsNamespaceAllowed - returns true if resources in the given namespace may bind to this RabbitMq.
+// The namespace of the RabbitMq itself is always allowed.
+func (instance RabbitMq) IsNamespaceAllowed(namespace string) bool {
+	if namespace == instance.Namespace {
+		return true
+	}
+	for _, ns := range instance.Spec.AllowedNamespaces {
+		if ns == AllowAllNamespaces || ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// GetActiveClusterName - returns the name of the RabbitmqCluster clients get pointed to, which
+// differs from the name of the RabbitMq while a BlueGreen upgrade runs on the green cluster
+func (instance RabbitMq) GetActiveClusterName() string {
+	if instance.Status.Upgrade != nil && instance.Status.Upgrade.ActiveClusterName != "" {
+		return instance.Status.Upgrade.ActiveClusterName
+	}
+	return instance.Name
+}
+
+// IsUpgradeInProgress - returns true if a change of the container image has not completed yet
+func (instance RabbitMq) IsUpgradeInProgress() bool {
+	return instance.Status.Upgrade != nil && instance.Status.Upgrade.Phase != UpgradePhaseCompleted
+}
+
 // RbacConditionsSet - set the conditions for the rbac object
 func (instance RabbitMq) RbacConditionsSet(c *condition.Condition) {
 	instance.Status.Conditions.Set(c)
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsNamespaceAllowed(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowedNamespaces []string
+		namespace         string
+		want              bool
+	}{
+		{
+			name:      "should allow the namespace of the RabbitMq",
+			namespace: "openstack",
+			want:      true,
+		},
+		{
+			name:      "should refuse other namespaces when the list is empty",
+			namespace: "tenant-a",
+			want:      false,
+		},
+		{
+			name:              "should allow a listed namespace",
+			allowedNamespaces: []string{"tenant-a", "tenant-b"},
+			namespace:         "tenant-b",
+			want:              true,
+		},
+		{
+			name:              "should refuse a namespace which is not listed",
+			allowedNamespaces: []string{"tenant-a"},
+			namespace:         "tenant-c",
+			want:              false,
+		},
+		{
+			name:              "should allow any namespace with a wildcard",
+			allowedNamespaces: []string{AllowAllNamespaces},
+			namespace:         "tenant-c",
+			want:              true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rabbitmq := RabbitMq{
+				ObjectMeta: metav1.ObjectMeta{Name: "rabbitmq", Namespace: "openstack"},
+			}
+			rabbitmq.Spec.AllowedNamespaces = tt.allowedNamespaces
+
+			if got := rabbitmq.IsNamespaceAllowed(tt.namespace); got != tt.want {
+				t.Errorf("IsNamespaceAllowed(%q) = %v, want %v", tt.namespace, got, tt.want)
+			}
+		})
+	}
+}
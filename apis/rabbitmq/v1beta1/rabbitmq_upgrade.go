@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lastMinorVersion - last minor version of each major release, an in-place upgrade
+// to the next major release is only supported from it
+var lastMinorVersion = map[int]int{
+	3: 13,
+}
+
+// ParseVersion - returns the major and minor part of a RabbitMQ version like 3.13.7
+func ParseVersion(version string) (int, int, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("invalid RabbitMQ version %q", version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid RabbitMQ version %q: %w", version, err)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid RabbitMQ version %q: %w", version, err)
+	}
+	return major, minor, nil
+}
+
+// IsMinorVersionChange - returns true if the major or minor part of the versions differ
+func IsMinorVersionChange(from, to string) bool {
+	fromMajor, fromMinor, err := ParseVersion(from)
+	if err != nil {
+		return false
+	}
+	toMajor, toMinor, err := ParseVersion(to)
+	if err != nil {
+		return false
+	}
+	return fromMajor != toMajor || fromMinor != toMinor
+}
+
+// ValidateUpgradePath - returns an error if the cluster can not be moved from one RabbitMQ version
+// to the other with the given strategy. Downgrades are always refused. In place, the cluster can
+// move one minor version at a time, or to the first minors of the next major release from the last
+// minor of the previous one. BlueGreen starts from an empty cluster and accepts any upgrade.
+func ValidateUpgradePath(from, to, strategy string) error {
+	fromMajor, fromMinor, err := ParseVersion(from)
+	if err != nil {
+		return err
+	}
+	toMajor, toMinor, err := ParseVersion(to)
+	if err != nil {
+		return err
+	}
+
+	if toMajor < fromMajor || (toMajor == fromMajor && toMinor < fromMinor) {
+		return fmt.Errorf("downgrade from RabbitMQ %s to %s is not supported", from, to)
+	}
+	if strategy == UpgradeStrategyBlueGreen {
+		return nil
+	}
+
+	switch {
+	case toMajor == fromMajor && toMinor-fromMinor <= 1:
+		return nil
+	case toMajor == fromMajor+1 && toMinor <= 1:
+		if last, ok := lastMinorVersion[fromMajor]; ok && fromMinor == last {
+			return nil
+		}
+	}
+	return fmt.Errorf("in-place upgrade from RabbitMQ %s to %s is not supported, use the %s upgrade strategy", from, to, UpgradeStrategyBlueGreen)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestValidateUpgradePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		strategy string
+		wantErr  bool
+	}{
+		{
+			name:     "should allow a patch upgrade",
+			from:     "3.13.2",
+			to:       "3.13.7",
+			strategy: UpgradeStrategyInPlace,
+		},
+		{
+			name:     "should allow the next minor version in place",
+			from:     "3.12.14",
+			to:       "3.13",
+			strategy: UpgradeStrategyInPlace,
+		},
+		{
+			name:     "should refuse to skip a minor version in place",
+			from:     "3.11.28",
+			to:       "3.13",
+			strategy: UpgradeStrategyInPlace,
+			wantErr:  true,
+		},
+		{
+			name:     "should allow the next major version from the last minor in place",
+			from:     "3.13.7",
+			to:       "4.0.5",
+			strategy: UpgradeStrategyInPlace,
+		},
+		{
+			name:     "should refuse the next major version from an older minor in place",
+			from:     "3.12.14",
+			to:       "4.0",
+			strategy: UpgradeStrategyInPlace,
+			wantErr:  true,
+		},
+		{
+			name:     "should allow any upgrade with BlueGreen",
+			from:     "3.9.29",
+			to:       "4.1",
+			strategy: UpgradeStrategyBlueGreen,
+		},
+		{
+			name:     "should refuse a downgrade",
+			from:     "4.0.5",
+			to:       "3.13",
+			strategy: UpgradeStrategyBlueGreen,
+			wantErr:  true,
+		},
+		{
+			name:     "should refuse an invalid version",
+			from:     "3.13.7",
+			to:       "latest",
+			strategy: UpgradeStrategyInPlace,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpgradePath(tt.from, tt.to, tt.strategy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpgradePath(%q, %q, %q) error = %v, wantErr %v", tt.from, tt.to, tt.strategy, err, tt.wantErr)
+			}
+		})
+	}
+}
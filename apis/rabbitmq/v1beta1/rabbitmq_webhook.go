@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
 
 	common_webhook "github.com/openstack-k8s-operators/lib-common/modules/common/webhook"
 	rabbitmqv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -123,6 +125,7 @@ func (r *RabbitMq) ValidateCreate() (admission.Warnings, error) {
 	// When a TopologyRef CR is referenced, fail if a different Namespace is
 	// referenced because is not supported
 	allErrs = append(allErrs, r.Spec.ValidateTopology(basePath, r.Namespace)...)
+	allErrs = append(allErrs, r.Spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, ptr.Deref(r.Spec.Replicas, 1))...)
 
 	warn, errs := r.Spec.ValidateOverride(basePath, r.Namespace)
 	allWarn = append(allWarn, warn...)
@@ -137,6 +140,8 @@ func (r *RabbitMq) ValidateCreate() (admission.Warnings, error) {
 	// Validate QueueType if specified
 	allErrs = append(allErrs, r.Spec.ValidateQueueType(basePath)...)
 
+	allErrs = append(allErrs, r.Spec.ValidateUpgradeStrategy(basePath)...)
+
 	if len(allErrs) != 0 {
 		return allWarn, apierrors.NewInvalid(
 			schema.GroupKind{Group: "rabbitmq.openstack.org", Kind: "RabbitMq"},
@@ -149,7 +154,7 @@ func (r *RabbitMq) ValidateCreate() (admission.Warnings, error) {
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *RabbitMq) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
+func (r *RabbitMq) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	rabbitmqlog.Info("validate update", "name", r.Name)
 
 	var allErrs field.ErrorList
@@ -159,6 +164,7 @@ func (r *RabbitMq) ValidateUpdate(_ runtime.Object) (admission.Warnings, error)
 	// When a TopologyRef CR is referenced, fail if a different Namespace is
 	// referenced because is not supported
 	allErrs = append(allErrs, r.Spec.ValidateTopology(basePath, r.Namespace)...)
+	allErrs = append(allErrs, r.Spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, ptr.Deref(r.Spec.Replicas, 1))...)
 
 	warn, errs := r.Spec.ValidateOverride(basePath, r.Namespace)
 	allWarn = append(allWarn, warn...)
@@ -167,6 +173,13 @@ func (r *RabbitMq) ValidateUpdate(_ runtime.Object) (admission.Warnings, error)
 	// Validate QueueType if specified
 	allErrs = append(allErrs, r.Spec.ValidateQueueType(basePath)...)
 
+	allErrs = append(allErrs, r.Spec.ValidateUpgradeStrategy(basePath)...)
+
+	// Refuse unsafe RabbitMQ version jumps
+	if oldRabbitMq, ok := old.(*RabbitMq); ok {
+		allErrs = append(allErrs, r.ValidateUpgrade(oldRabbitMq, basePath)...)
+	}
+
 	if len(allErrs) != 0 {
 		return allWarn, apierrors.NewInvalid(
 			schema.GroupKind{Group: "rabbitmq.openstack.org", Kind: "RabbitMq"},
@@ -177,6 +190,69 @@ func (r *RabbitMq) ValidateUpdate(_ runtime.Object) (admission.Warnings, error)
 	return allWarn, nil
 }
 
+// ValidateUpgrade refuses container image changes while another upgrade has not completed, and
+// changes which move the running RabbitMQ version to TargetVersion along an unsupported path
+func (r *RabbitMq) ValidateUpgrade(old *RabbitMq, basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if old.Spec.ContainerImage == r.Spec.ContainerImage {
+		return allErrs
+	}
+
+	if old.IsUpgradeInProgress() && r.Spec.ContainerImage != old.Status.Upgrade.ToImage {
+		allErrs = append(allErrs, field.Forbidden(
+			basePath.Child("containerImage"),
+			fmt.Sprintf("upgrade to %s is in phase %s, wait for it to complete", old.Status.Upgrade.ToImage, old.Status.Upgrade.Phase),
+		))
+		return allErrs
+	}
+
+	// The controller only runs a BlueGreen upgrade when it knows the running version and
+	// the major or minor version changes, anything else would silently roll in place
+	if r.Spec.UpgradeStrategy == UpgradeStrategyBlueGreen && r.Spec.TargetVersion != "" {
+		if old.Status.Version == "" {
+			allErrs = append(allErrs, field.Forbidden(
+				basePath.Child("upgradeStrategy"),
+				fmt.Sprintf("%s upgrade needs the running RabbitMQ version, wait for it to be reported", UpgradeStrategyBlueGreen),
+			))
+			return allErrs
+		}
+		if !IsMinorVersionChange(old.Status.Version, r.Spec.TargetVersion) {
+			allErrs = append(allErrs, field.Invalid(
+				basePath.Child("upgradeStrategy"),
+				r.Spec.UpgradeStrategy,
+				fmt.Sprintf("%s upgrade from RabbitMQ %s to %s is not supported, it needs a major or minor version change, use the %s upgrade strategy",
+					UpgradeStrategyBlueGreen, old.Status.Version, r.Spec.TargetVersion, UpgradeStrategyInPlace),
+			))
+			return allErrs
+		}
+	}
+
+	if r.Spec.TargetVersion == "" || old.Status.Version == "" {
+		return allErrs
+	}
+	if err := ValidateUpgradePath(old.Status.Version, r.Spec.TargetVersion, r.Spec.UpgradeStrategy); err != nil {
+		allErrs = append(allErrs, field.Invalid(basePath.Child("targetVersion"), r.Spec.TargetVersion, err.Error()))
+	}
+
+	return allErrs
+}
+
+// ValidateUpgradeStrategy refuses the BlueGreen upgrade strategy without targetVersion, the
+// controller can not tell a major or minor version change from a patch release without it
+func (spec *RabbitMqSpec) ValidateUpgradeStrategy(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.UpgradeStrategy == UpgradeStrategyBlueGreen && spec.TargetVersion == "" {
+		allErrs = append(allErrs, field.Required(
+			basePath.Child("targetVersion"),
+			fmt.Sprintf("targetVersion is required with the %s upgrade strategy", UpgradeStrategyBlueGreen),
+		))
+	}
+
+	return allErrs
+}
+
 // ValidateCreate performs validation when creating a new RabbitMqSpecCore.
 func (spec *RabbitMqSpecCore) ValidateCreate(basePath *field.Path, namespace string) (admission.Warnings, field.ErrorList) {
 	var allErrs field.ErrorList
@@ -185,6 +261,7 @@ func (spec *RabbitMqSpecCore) ValidateCreate(basePath *field.Path, namespace str
 	// When a TopologyRef CR is referenced, fail if a different Namespace is
 	// referenced because is not supported
 	allErrs = append(allErrs, spec.ValidateTopology(basePath, namespace)...)
+	allErrs = append(allErrs, spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, ptr.Deref(spec.Replicas, 1))...)
 	warn, errs := spec.ValidateOverride(basePath, namespace)
 	allWarn = append(allWarn, warn...)
 	allErrs = append(allErrs, errs...)
@@ -200,6 +277,7 @@ func (spec *RabbitMqSpecCore) ValidateUpdate(_ RabbitMqSpecCore, basePath *field
 	// When a TopologyRef CR is referenced, fail if a different Namespace is
 	// referenced because is not supported
 	allErrs = append(allErrs, spec.ValidateTopology(basePath, namespace)...)
+	allErrs = append(allErrs, spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, ptr.Deref(spec.Replicas, 1))...)
 	warn, errs := spec.ValidateOverride(basePath, namespace)
 	allWarn = append(allWarn, warn...)
 	allErrs = append(allErrs, errs...)
@@ -49,6 +49,13 @@ type RabbitMQUserSpec struct {
 	// RabbitmqClusterName - the name of the RabbitMQ cluster
 	RabbitmqClusterName string `json:"rabbitmqClusterName"`
 
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=63
+	// RabbitmqClusterNamespace - namespace of the RabbitMQ cluster, defaults to the namespace of the RabbitMQUser.
+	// When it differs, the namespace of the RabbitMQUser must be allowed by the RabbitMq allowedNamespaces and
+	// VhostRef refers to a RabbitMQVhost in the cluster namespace.
+	RabbitmqClusterNamespace string `json:"rabbitmqClusterNamespace,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// VhostRef - reference to the RabbitMQVhost resource (defaults to default vhost "/" if empty)
 	VhostRef string `json:"vhostRef,omitempty"`
@@ -123,6 +130,19 @@ func (instance RabbitMQUser) IsReady() bool {
 	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
 }
 
+// GetRabbitmqClusterNamespace returns the namespace of the referenced RabbitMQ cluster
+func (instance RabbitMQUser) GetRabbitmqClusterNamespace() string {
+	if instance.Spec.RabbitmqClusterNamespace != "" {
+		return instance.Spec.RabbitmqClusterNamespace
+	}
+	return instance.Namespace
+}
+
+// IsCrossNamespace returns true if the referenced RabbitMQ cluster lives in another namespace
+func (instance RabbitMQUser) IsCrossNamespace() bool {
+	return instance.GetRabbitmqClusterNamespace() != instance.Namespace
+}
+
 const (
 	// UserFinalizer - finalizer to protect user from deletion when owned by TransportURL
 	UserFinalizer = "rabbitmquser.rabbitmq.openstack.org/finalizer"
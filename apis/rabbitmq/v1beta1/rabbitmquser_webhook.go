@@ -67,7 +67,7 @@ func (r *RabbitMQUser) ValidateCreate(k8sClient client.Client) (admission.Warnin
 	if r.Spec.VhostRef != "" {
 		vhost := &RabbitMQVhost{}
 		if err := k8sClient.Get(context.TODO(),
-			client.ObjectKey{Name: r.Spec.VhostRef, Namespace: r.Namespace},
+			client.ObjectKey{Name: r.Spec.VhostRef, Namespace: r.GetRabbitmqClusterNamespace()},
 			vhost); err != nil {
 			return nil, apierrors.NewInvalid(
 				schema.GroupKind{Group: "rabbitmq.openstack.org", Kind: "RabbitMQUser"},
@@ -116,21 +116,23 @@ func (r *RabbitMQUser) ValidateDelete(client.Client) (admission.Warnings, error)
 
 // validateUniqueUsername checks that no other RabbitMQUser exists with the same username, vhost, and cluster
 func (r *RabbitMQUser) validateUniqueUsername(k8sClient client.Client) error {
-	// List all RabbitMQUsers in the same namespace
+	// List all RabbitMQUsers, users of the same cluster may live in other
+	// namespaces when they set rabbitmqClusterNamespace
 	userList := &RabbitMQUserList{}
-	if err := k8sClient.List(context.TODO(), userList, client.InNamespace(r.Namespace)); err != nil {
+	if err := k8sClient.List(context.TODO(), userList); err != nil {
 		return apierrors.NewInternalError(fmt.Errorf("failed to list RabbitMQUsers: %w", err))
 	}
 
 	// Check for conflicts
 	for _, user := range userList.Items {
 		// Skip self
-		if user.Name == r.Name {
+		if user.Name == r.Name && user.Namespace == r.Namespace {
 			continue
 		}
 
 		// Check if same RabbitMQ cluster
-		if user.Spec.RabbitmqClusterName != r.Spec.RabbitmqClusterName {
+		if user.Spec.RabbitmqClusterName != r.Spec.RabbitmqClusterName ||
+			user.GetRabbitmqClusterNamespace() != r.GetRabbitmqClusterNamespace() {
 			continue
 		}
 
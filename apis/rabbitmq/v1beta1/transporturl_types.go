@@ -27,6 +27,14 @@ type TransportURLSpec struct {
 	// RabbitmqClusterName the name of the Rabbitmq cluster which to configure the transport URL
 	RabbitmqClusterName string `json:"rabbitmqClusterName"`
 
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=63
+	// RabbitmqClusterNamespace - namespace of the Rabbitmq cluster, defaults to the namespace of the TransportURL.
+	// When it differs, the namespace of the TransportURL must be allowed by the RabbitMq allowedNamespaces, the
+	// RabbitMQUser and RabbitMQVhost are created in the cluster namespace and the user credentials are mirrored
+	// into the namespace of the TransportURL.
+	RabbitmqClusterNamespace string `json:"rabbitmqClusterNamespace,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// UserRef - reference to a RabbitMQUser resource. If not specified, Username will be used to create a RabbitMQUser on-demand
 	UserRef string `json:"userRef,omitempty"`
@@ -58,6 +66,9 @@ type TransportURLStatus struct {
 	// RabbitmqVhost - the actual vhost name used
 	RabbitmqVhost string `json:"rabbitmqVhost,omitempty"`
 
+	// UserSecretName - name of the secret in the TransportURL namespace holding the RabbitMQ user credentials
+	UserSecretName string `json:"userSecretName,omitempty"`
+
 	// ObservedGeneration - the most recent generation observed for this
 	// service. If the observed generation is less than the spec generation,
 	// then the controller has not processed the latest changes injected by
@@ -96,3 +107,16 @@ func init() {
 func (instance TransportURL) IsReady() bool {
 	return instance.Status.Conditions.IsTrue(TransportURLReadyCondition)
 }
+
+// GetRabbitmqClusterNamespace - returns the namespace of the referenced Rabbitmq cluster
+func (instance TransportURL) GetRabbitmqClusterNamespace() string {
+	if instance.Spec.RabbitmqClusterNamespace != "" {
+		return instance.Spec.RabbitmqClusterNamespace
+	}
+	return instance.Namespace
+}
+
+// IsCrossNamespace - returns true if the referenced Rabbitmq cluster lives in another namespace
+func (instance TransportURL) IsCrossNamespace() bool {
+	return instance.GetRabbitmqClusterNamespace() != instance.Namespace
+}
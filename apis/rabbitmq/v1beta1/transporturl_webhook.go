@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var transporturllog = logf.Log.WithName("transporturl-resource")
+
+//+kubebuilder:webhook:path=/validate-rabbitmq-openstack-org-v1beta1-transporturl,mutating=false,failurePolicy=fail,sideEffects=None,groups=rabbitmq.openstack.org,resources=transporturls,verbs=create;update,versions=v1beta1,name=vtransporturl.kb.io,admissionReviewVersions=v1
+
+// ValidateCreate validates the TransportURL on creation
+func (r *TransportURL) ValidateCreate(_ client.Client) (admission.Warnings, error) {
+	transporturllog.Info("validate create", "name", r.Name)
+
+	return nil, r.validate()
+}
+
+// ValidateUpdate validates the TransportURL on update
+func (r *TransportURL) ValidateUpdate(_ client.Client, _ runtime.Object) (admission.Warnings, error) {
+	transporturllog.Info("validate update", "name", r.Name)
+
+	return nil, r.validate()
+}
+
+// ValidateDelete validates the TransportURL on deletion
+func (r *TransportURL) ValidateDelete(_ client.Client) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *TransportURL) validate() error {
+	if errs := r.ValidateCrossNamespaceCredentials(); len(errs) > 0 {
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: "rabbitmq.openstack.org", Kind: "TransportURL"},
+			r.Name,
+			errs,
+		)
+	}
+	return nil
+}
+
+// ValidateCrossNamespaceCredentials - a TransportURL bound to a Rabbitmq cluster in another namespace
+// must get its own user created from username. A userRef would resolve to a RabbitMQUser of the cluster
+// namespace the tenant does not own, and the admin credentials must never leave the cluster namespace.
+func (r *TransportURL) ValidateCrossNamespaceCredentials() field.ErrorList {
+	if !r.IsCrossNamespace() {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if r.Spec.UserRef != "" {
+		errs = append(errs, field.Forbidden(
+			field.NewPath("spec", "userRef"),
+			"userRef is not supported together with rabbitmqClusterNamespace, use username instead"))
+	}
+	if r.Spec.Username == "" {
+		errs = append(errs, field.Required(
+			field.NewPath("spec", "username"),
+			"username is required together with rabbitmqClusterNamespace"))
+	}
+	return errs
+}
@@ -37,6 +37,11 @@ func (in *PodOverride) DeepCopyInto(out *PodOverride) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(PodOverrideCertificate)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodOverride.
@@ -49,6 +54,21 @@ func (in *PodOverride) DeepCopy() *PodOverride {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodOverrideCertificate) DeepCopyInto(out *PodOverrideCertificate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodOverrideCertificate.
+func (in *PodOverrideCertificate) DeepCopy() *PodOverrideCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(PodOverrideCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RabbitMQPolicy) DeepCopyInto(out *RabbitMQPolicy) {
 	*out = *in
@@ -494,6 +514,12 @@ func (in *RabbitMqSpecCore) DeepCopyInto(out *RabbitMqSpecCore) {
 		*out = new(PodOverride)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitMqSpecCore.
@@ -526,6 +552,11 @@ func (in *RabbitMqStatus) DeepCopyInto(out *RabbitMqStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(RabbitMqUpgradeStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitMqStatus.
@@ -538,6 +569,21 @@ func (in *RabbitMqStatus) DeepCopy() *RabbitMqStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitMqUpgradeStatus) DeepCopyInto(out *RabbitMqUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitMqUpgradeStatus.
+func (in *RabbitMqUpgradeStatus) DeepCopy() *RabbitMqUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitMqUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TransportURL) DeepCopyInto(out *TransportURL) {
 	*out = *in
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+)
+
+// Replication conditions
+const (
+	// RedisMasterReadyCondition - the sentinels agree on a master
+	RedisMasterReadyCondition condition.Type = "RedisMasterReady"
+
+	// RedisMasterReadyInitMessage -
+	RedisMasterReadyInitMessage = "Redis master not checked"
+
+	// RedisMasterReadyMessage -
+	RedisMasterReadyMessage = "Redis master is %s"
+
+	// RedisMasterReadyErrorMessage -
+	RedisMasterReadyErrorMessage = "No Redis master elected: %s"
+)
+
+// Cluster mode conditions
+const (
+	// RedisClusterReadyCondition - the slots are distributed over the shards and served
+	RedisClusterReadyCondition condition.Type = "RedisClusterReady"
+
+	// RedisClusterReadyInitMessage -
+	RedisClusterReadyInitMessage = "Redis cluster not checked"
+
+	// RedisClusterReadyMessage -
+	RedisClusterReadyMessage = "Redis cluster serves all slots with %d shards"
+
+	// RedisClusterReadyRunningMessage -
+	RedisClusterReadyRunningMessage = "Redis cluster job in progress"
+
+	// RedisClusterReadyWaitingMessage -
+	RedisClusterReadyWaitingMessage = "Redis cluster waiting for %s"
+
+	// RedisClusterReadyErrorMessage -
+	RedisClusterReadyErrorMessage = "Redis cluster error occurred %s"
+)
+
+// Restore messages
+const (
+	// RedisRestoreWaitingMessage - the statefulset gets created once the backup to restore is known
+	RedisRestoreWaitingMessage = "Deployment waiting for the backup of RedisRestore %s"
+)
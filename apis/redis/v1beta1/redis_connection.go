@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+const (
+	// ConnectionSecretSuffix - suffix of the secret holding the connection details
+	ConnectionSecretSuffix = "-redis-connection"
+	// ConnectionSentinelURLKey - key of the redis+sentinel:// URL listing all sentinels
+	ConnectionSentinelURLKey = "sentinel_url"
+	// ConnectionMasterURLKey - key of the URL of the service that points to the current master
+	ConnectionMasterURLKey = "master_url"
+	// ConnectionSentinelHostsKey - key of the comma separated host:port list of the sentinels
+	ConnectionSentinelHostsKey = "sentinel_hosts"
+	// ConnectionSentinelMasterKey - key of the name the sentinels monitor the master with
+	ConnectionSentinelMasterKey = "sentinel_master"
+	// ConnectionClusterNodesKey - key of the comma separated host:port list of the nodes in
+	// cluster mode, which replaces the sentinel keys
+	ConnectionClusterNodesKey = "cluster_nodes"
+	// ConnectionClusterURLKey - key of the URL of the service that points to all nodes in
+	// cluster mode, clients discover the slots from any of them
+	ConnectionClusterURLKey = "cluster_url"
+	// ConnectionCAPathKey - key of the CA bundle path to verify the servers with, only set with TLS
+	ConnectionCAPathKey = "ca_path"
+	// ConnectionPasswordKey - key of the password of the default user, only set with auth
+	ConnectionPasswordKey = "password"
+
+	// SentinelMasterName - name of the master monitored by the sentinels
+	SentinelMasterName = "redis"
+	// RedisPort - port of the redis servers
+	RedisPort = 6379
+	// SentinelPort - port of the sentinels
+	SentinelPort = 26379
+	// ClusterBusPort - port the nodes of the cluster mode talk to each other on
+	ClusterBusPort = 16379
+)
+
+// GetRedisConnectionSecret - return the secret holding the connection details
+func (instance *Redis) GetRedisConnectionSecret() string {
+	return instance.Status.SecretName
+}
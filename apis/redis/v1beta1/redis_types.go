@@ -17,13 +17,22 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	commonv1 "github.com/openstack-k8s-operators/infra-operator/apis/common/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -32,10 +41,18 @@ const (
 	// RedisContainerImage is the fall-back container image for Redis
 	RedisContainerImage = "quay.io/podified-antelope-centos9/openstack-redis:current-podified"
 
+	// RedisExporterContainerImage is the fall-back container image for the redis_exporter sidecars
+	RedisExporterContainerImage = "quay.io/oliver006/redis_exporter:v1.67.0"
+
 	// CrMaxLengthCorrection - DNS1123LabelMaxLength (63) - CrMaxLengthCorrection used in validation to
 	// omit issue with statefulset pod label "controller-revision-hash": "<statefulset_name>-<hash>"
 	// Int32 is a 10 character + hyphen = 11
 	CrMaxLengthCorrection = 11
+
+	// AuthSecretSuffix - suffix of the secret holding the password of the default user
+	AuthSecretSuffix = "-redis-auth"
+	// AuthPasswordKey - password key in the auth secret
+	AuthPasswordKey = "password"
 )
 
 // RedisSpec defines the desired state of Redis
@@ -45,16 +62,34 @@ type RedisSpec struct {
 	// +kubebuilder:validation:Required
 	// Name of the redis container image to run (will be set to environmental default if empty)
 	ContainerImage string `json:"containerImage"`
+
+	// +kubebuilder:validation:Optional
+	// Name of the redis_exporter container image to run when metrics are enabled
+	// (will be set to environmental default if empty)
+	ExporterImage string `json:"exporterImage,omitempty"`
 }
 
 // RedisSpecCore - this version is used by the OpenStackControlplane CR (no container images)
 type RedisSpecCore struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=1
-	// Size of the redis cluster
+	// Size of the redis cluster, not used in cluster mode where the shards define it
 	Replicas *int32 `json:"replicas"`
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:default=sentinel
+	// +kubebuilder:validation:Enum=sentinel;cluster
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="mode is immutable"
+	// Mode - sentinel runs a master and its replicas monitored by sentinels, cluster runs a Redis
+	// Cluster sharding the keys over several masters. Cluster mode requires persistence, the
+	// nodes keep their identity in the volume claims.
+	Mode string `json:"mode,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Cluster - shards of the cluster mode, changes get applied by resharding the running cluster
+	Cluster ClusterSection `json:"cluster,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// TLS settings for Redis service and internal Redis replication
 	TLS tls.SimpleService `json:"tls,omitempty"`
 	// +kubebuilder:validation:Optional
@@ -70,6 +105,213 @@ type RedisSpecCore struct {
 	// +kubebuilder:validation:Optional
 	// Resources QoS configuration for sentinel servers
 	SentinelResources corev1.ResourceRequirements `json:"sentinelResources,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// PodDisruptionBudget - limits how many pods a node drain may evict at the same time
+	PodDisruptionBudget commonv1.PodDisruptionBudgetSection `json:"podDisruptionBudget,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Persistence - keep the data in a volume claim per pod so that it survives a restart of all pods
+	Persistence PersistenceSection `json:"persistence,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Auth - require a password from the clients of redis and sentinel
+	Auth AuthSection `json:"auth,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Sentinel - failover tuning of the sentinels, changes get applied to the running sentinels
+	// without restarting the pods
+	Sentinel SentinelSection `json:"sentinel,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// MaxMemory - memory limit of the dataset, either a quantity like 512Mi or a percentage of the
+	// memory limit of the redis container like 75%. Defaults to 75% of the memory limit, no limit
+	// without one. Redis evicts keys according to maxmemoryPolicy when it is reached.
+	MaxMemory string `json:"maxmemory,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:default=noeviction
+	// +kubebuilder:validation:Enum=noeviction;allkeys-lru;allkeys-lfu;allkeys-random;volatile-lru;volatile-lfu;volatile-random;volatile-ttl
+	// MaxMemoryPolicy - how keys get evicted when maxmemory is reached, noeviction rejects writes
+	MaxMemoryPolicy string `json:"maxmemoryPolicy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// ExtraConfig - additional redis.conf directives and their values, e.g. hz: "20". The directives
+	// managed by the operator can't be set.
+	ExtraConfig map[string]string `json:"extraConfig,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Metrics settings for the redis and sentinel servers
+	Metrics MetricsSection `json:"metrics,omitempty"`
+}
+
+const (
+	// RedisModeSentinel - a master and its replicas monitored by sentinels
+	RedisModeSentinel = "sentinel"
+	// RedisModeCluster - a Redis Cluster sharding the keys over several masters
+	RedisModeCluster = "cluster"
+
+	// DefaultClusterShards - default of ClusterSection.Shards, the minimum of a Redis Cluster
+	DefaultClusterShards = 3
+	// DefaultClusterReplicasPerShard - default of ClusterSection.ReplicasPerShard
+	DefaultClusterReplicasPerShard = 1
+)
+
+// ClusterSection contains the shape of the cluster mode
+type ClusterSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=3
+	// Shards - number of masters the hash slots get distributed over
+	Shards int32 `json:"shards,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// ReplicasPerShard - number of replicas of each master, a replica takes over when its
+	// master fails
+	ReplicasPerShard *int32 `json:"replicasPerShard,omitempty"`
+}
+
+// IsClusterMode - whether the keys get sharded in a Redis Cluster
+func (instance *RedisSpecCore) IsClusterMode() bool {
+	return instance.Mode == RedisModeCluster
+}
+
+// ClusterShards - the shards of the cluster mode with the defaults applied
+func (instance *RedisSpecCore) ClusterShards() int32 {
+	if instance.Cluster.Shards == 0 {
+		return DefaultClusterShards
+	}
+	return instance.Cluster.Shards
+}
+
+// ClusterReplicasPerShard - the replicas of each shard with the defaults applied
+func (instance *RedisSpecCore) ClusterReplicasPerShard() int32 {
+	return ptr.Deref(instance.Cluster.ReplicasPerShard, DefaultClusterReplicasPerShard)
+}
+
+// PodCount - the number of redis pods, the replicas in sentinel mode and the masters and
+// their replicas in cluster mode
+func (instance *RedisSpecCore) PodCount() int32 {
+	if instance.IsClusterMode() {
+		return instance.ClusterShards() * (1 + instance.ClusterReplicasPerShard())
+	}
+	return ptr.Deref(instance.Replicas, 1)
+}
+
+// MetricsSection contains the redis_exporter configuration
+type MetricsSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled adds redis_exporter sidecars for the redis and the sentinel server to the pods and
+	// a ServiceMonitor if the Prometheus operator is installed. Changing it restarts all the pods.
+	Enabled bool `json:"enabled"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern="^[0-9]+(ms|s|m|h)$"
+	// ScrapeInterval - how often Prometheus scrapes the exporters
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+}
+
+const (
+	// DefaultMaxMemory - maxmemory when only the memory limit of the redis container is set, leaves
+	// room for the replication buffers and the fork of the snapshots
+	DefaultMaxMemory = "75%"
+)
+
+// configDirectiveRegexp - the names of the redis.conf directives
+var configDirectiveRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// ManagedConfigDirectives - redis.conf directives set by the operator that can't be overridden
+// with ExtraConfig
+var ManagedConfigDirectives = []string{
+	"aclfile", "appendonly", "bind", "daemonize", "dbfilename", "dir", "include",
+	"cluster-announce-hostname", "cluster-config-file", "cluster-enabled", "cluster-preferred-endpoint-type",
+	"masterauth", "masteruser", "maxmemory", "maxmemory-policy", "port", "protected-mode",
+	"rename-command", "replica-announce-ip", "replicaof", "requirepass", "save", "slaveof", "user",
+}
+
+const (
+	// DefaultSentinelDownAfterMilliseconds - default of SentinelSection.DownAfterMilliseconds
+	DefaultSentinelDownAfterMilliseconds = 10000
+	// DefaultSentinelFailoverTimeout - default of SentinelSection.FailoverTimeout
+	DefaultSentinelFailoverTimeout = 20000
+	// DefaultSentinelParallelSyncs - default of SentinelSection.ParallelSyncs
+	DefaultSentinelParallelSyncs = 1
+)
+
+// SentinelSection contains the failover settings of the sentinels
+type SentinelSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10000
+	// +kubebuilder:validation:Minimum=1
+	// DownAfterMilliseconds - how long the master has to be unreachable before a sentinel
+	// considers it down
+	DownAfterMilliseconds int32 `json:"downAfterMilliseconds,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=20000
+	// +kubebuilder:validation:Minimum=1
+	// FailoverTimeout - milliseconds before a failover that did not complete gets retried
+	FailoverTimeout int32 `json:"failoverTimeout,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// ParallelSyncs - how many replicas resync with the new master at the same time after a
+	// failover, replicas that resync can't serve reads
+	ParallelSyncs int32 `json:"parallelSyncs,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Quorum - how many sentinels have to agree that the master is down, the majority of the
+	// replicas if not set. A failover still needs to be authorized by the majority.
+	Quorum *int32 `json:"quorum,omitempty"`
+}
+
+// AuthSection contains the password authentication settings
+type AuthSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled - a password gets generated into the <name>-redis-auth secret and set as requirepass
+	// and masterauth of redis and requirepass of sentinel. It is also required to manage ACL users
+	// with RedisUser resources. Changing it restarts all the pods.
+	Enabled bool `json:"enabled"`
+}
+
+const (
+	// PersistenceModeNone - the data is only kept in memory and in the replicas
+	PersistenceModeNone = "none"
+	// PersistenceModeRDB - the data gets snapshotted to the volume claim
+	PersistenceModeRDB = "rdb"
+	// PersistenceModeAOF - every write gets appended to a log in the volume claim
+	PersistenceModeAOF = "aof"
+)
+
+// PersistenceSection defines how the data of the redis servers is persisted
+type PersistenceSection struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=none
+	// +kubebuilder:validation:Enum=none;rdb;aof
+	// Mode - none keeps the data only in memory, rdb writes snapshots according to snapshotSchedule,
+	// aof logs every write and fsyncs the log every second
+	Mode string `json:"mode,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="3600 1 300 100 60 10000"
+	// +kubebuilder:validation:Pattern="^[0-9]+ [0-9]+( [0-9]+ [0-9]+)*$"
+	// SnapshotSchedule - pairs of seconds and changes, a snapshot gets written when the given number
+	// of keys changed within the given seconds (the redis save directive). Only used by the rdb mode.
+	SnapshotSchedule string `json:"snapshotSchedule,omitempty"`
+	// +kubebuilder:validation:Optional
+	// StorageClass - storage class of the volume claims, the default storage class if not set
+	StorageClass string `json:"storageClass,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1Gi"
+	// StorageRequest - size of the volume claim of each pod
+	StorageRequest string `json:"storageRequest,omitempty"`
+}
+
+// Enabled - whether the data is persisted in volume claims
+func (p *PersistenceSection) Enabled() bool {
+	return p.Mode == PersistenceModeRDB || p.Mode == PersistenceModeAOF
 }
 
 // RedisStatus defines the observed state of Redis
@@ -87,11 +329,112 @@ type RedisStatus struct {
 
 	// LastAppliedTopology - the last applied Topology
 	LastAppliedTopology *topologyv1.TopoRef `json:"lastAppliedTopology,omitempty"`
+
+	// AuthSecret - name of the secret holding the password of the default user
+	AuthSecret string `json:"authSecret,omitempty"`
+
+	// SecretName - name of the secret holding the sentinel and master URLs, the CA path
+	// and the password for the consumers
+	SecretName string `json:"secretName,omitempty"`
+
+	// Replication - replication topology as reported by the sentinels and the master
+	Replication *ReplicationStatus `json:"replication,omitempty"`
+
+	// SentinelPods - the pods whose sentinel got the current settings, with the pod UID, the
+	// restart count of the sentinel container and the hash of the settings at the time
+	SentinelPods map[string]string `json:"sentinelPods,omitempty"`
+
+	// RestoreName - the RedisRestore the instance got seeded with when it was created
+	RestoreName string `json:"restoreName,omitempty"`
+
+	// Cluster - shape and nodes of the cluster mode
+	Cluster *ClusterStatus `json:"cluster,omitempty"`
+}
+
+// ClusterStatus - shape and nodes of the cluster mode
+type ClusterStatus struct {
+	// Shards - number of masters the last successful cluster job distributed the slots over
+	Shards int32 `json:"shards,omitempty"`
+
+	// ReplicasPerShard - number of replicas of each master the last successful cluster job set up
+	ReplicasPerShard int32 `json:"replicasPerShard,omitempty"`
+
+	// State - cluster_state reported by CLUSTER INFO, ok when all slots are served
+	State string `json:"state,omitempty"`
+
+	// Nodes - nodes of the cluster as reported by CLUSTER NODES
+	Nodes []ClusterNodeStatus `json:"nodes,omitempty"`
+
+	// LastUpdated - when the nodes were queried last
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// UnhealthySince - when CLUSTER INFO started to report a failed cluster or slots no node
+	// serves, the cluster job runs again when this lasts longer than an automatic failover
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
+}
+
+// ClusterNodeStatus - a node of the cluster mode
+type ClusterNodeStatus struct {
+	// Pod - pod of the node
+	Pod string `json:"pod"`
+
+	// ID - cluster node ID
+	ID string `json:"id"`
+
+	// Role - master or replica
+	Role string `json:"role"`
+
+	// Master - pod of the master of a replica
+	Master string `json:"master,omitempty"`
+
+	// Slots - hash slot ranges served by a master, e.g. 0-5460
+	Slots string `json:"slots,omitempty"`
+
+	// Failing - whether the other nodes consider the node down
+	Failing bool `json:"failing,omitempty"`
+}
+
+// ReplicationStatus - replication topology of the redis servers
+type ReplicationStatus struct {
+	// Master - pod of the current master
+	Master string `json:"master,omitempty"`
+
+	// Replicas - replicas connected to the master
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
+
+	// QuorumReachable - whether the sentinels can reach the quorum needed to fail over
+	QuorumReachable bool `json:"quorumReachable"`
+
+	// SentinelMessage - reply of SENTINEL CKQUORUM, or the error when it failed
+	SentinelMessage string `json:"sentinelMessage,omitempty"`
+
+	// LastFailoverTime - when a change of the master was observed last
+	LastFailoverTime *metav1.Time `json:"lastFailoverTime,omitempty"`
+
+	// LastUpdated - when the topology was queried last
+	LastUpdated metav1.Time `json:"lastUpdated"`
+}
+
+// ReplicaStatus - replication state of a replica
+type ReplicaStatus struct {
+	// Pod - pod of the replica
+	Pod string `json:"pod"`
+
+	// State - replication state reported by the master, online once the initial sync is done
+	State string `json:"state,omitempty"`
+
+	// LagSeconds - seconds since the replica acknowledged the replication stream last
+	LagSeconds int64 `json:"lagSeconds"`
+
+	// OffsetLag - bytes of the replication stream the replica is behind the master
+	OffsetLag int64 `json:"offsetLag"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=redises
+//+kubebuilder:printcolumn:name="Master",type="string",JSONPath=".status.replication.master",description="Master"
+//+kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode",description="Mode"
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
 //+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
 
@@ -141,7 +484,8 @@ func (instance Redis) RbacResourceName() string {
 func SetupDefaults() {
 	// Acquire environmental defaults and initialize Redis defaults with them
 	redisDefaults := RedisDefaults{
-		ContainerImageURL: util.GetEnvVar("RELATED_IMAGE_INFRA_REDIS_IMAGE_URL_DEFAULT", RedisContainerImage),
+		ContainerImageURL:         util.GetEnvVar("RELATED_IMAGE_INFRA_REDIS_IMAGE_URL_DEFAULT", RedisContainerImage),
+		ExporterContainerImageURL: util.GetEnvVar("RELATED_IMAGE_INFRA_REDIS_EXPORTER_IMAGE_URL_DEFAULT", RedisExporterContainerImage),
 	}
 
 	SetupRedisDefaults(redisDefaults)
@@ -160,6 +504,117 @@ func (instance Redis) GetLastAppliedTopologyRef() *topologyv1.TopoRef {
 	}
 }
 
+// ValidatePersistence - the volume claim needs a valid size
+func (instance *RedisSpecCore) ValidatePersistence(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !instance.Persistence.Enabled() {
+		return allErrs
+	}
+	path := basePath.Child("persistence").Child("storageRequest")
+	size, err := resource.ParseQuantity(instance.Persistence.StorageRequest)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(path, instance.Persistence.StorageRequest, err.Error()))
+	} else if size.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(path, instance.Persistence.StorageRequest, "must be greater than zero"))
+	}
+	return allErrs
+}
+
+// ValidatePersistenceUpdate - the volume claim templates of the StatefulSet can not be changed, so
+// persistence can't be turned on or off and the storage settings can't change
+func (instance *RedisSpecCore) ValidatePersistenceUpdate(basePath *field.Path, old *RedisSpecCore) field.ErrorList {
+	var allErrs field.ErrorList
+	path := basePath.Child("persistence")
+	if instance.Persistence.Enabled() != old.Persistence.Enabled() {
+		allErrs = append(allErrs, field.Forbidden(
+			path.Child("mode"), "persistence can not be turned on or off"))
+	}
+	if instance.Persistence.Enabled() && old.Persistence.Enabled() &&
+		(instance.Persistence.StorageClass != old.Persistence.StorageClass ||
+			instance.Persistence.StorageRequest != old.Persistence.StorageRequest) {
+		allErrs = append(allErrs, field.Forbidden(
+			path, "storageClass and storageRequest can not be changed"))
+	}
+	return allErrs
+}
+
+// ValidateCluster - the nodes of the cluster mode keep their identity and slots in nodes.conf,
+// which needs to survive a restart of the pods
+func (instance *RedisSpecCore) ValidateCluster(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.IsClusterMode() && !instance.Persistence.Enabled() {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("persistence").Child("mode"), instance.Persistence.Mode,
+			"cluster mode requires the rdb or aof persistence"))
+	}
+	return allErrs
+}
+
+// ValidateSentinel - the quorum can't be reached with less sentinels than the quorum
+func (instance *RedisSpecCore) ValidateSentinel(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if instance.IsClusterMode() {
+		return allErrs
+	}
+	quorum := instance.Sentinel.Quorum
+	replicas := ptr.Deref(instance.Replicas, 1)
+	if quorum != nil && *quorum > replicas {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("sentinel").Child("quorum"), *quorum,
+			fmt.Sprintf("must not be greater than the %d replicas", replicas)))
+	}
+	return allErrs
+}
+
+// ValidateMemory - maxmemory has to be a positive quantity or a percentage of the memory limit
+// of the redis container, which then needs to be set
+func (instance *RedisSpecCore) ValidateMemory(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	path := basePath.Child("maxmemory")
+	value := instance.MaxMemory
+	if value == "" {
+		return allErrs
+	}
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.Atoi(percent)
+		if err != nil || p < 1 || p > 100 {
+			allErrs = append(allErrs, field.Invalid(path, value, "must be a percentage between 1% and 100%"))
+		} else if instance.Resources.Limits.Memory().IsZero() {
+			allErrs = append(allErrs, field.Invalid(path, value,
+				"a percentage needs a memory limit in resources.limits.memory"))
+		}
+		return allErrs
+	}
+	size, err := resource.ParseQuantity(value)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(path, value, err.Error()))
+	} else if size.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(path, value, "must be greater than zero"))
+	}
+	return allErrs
+}
+
+// ValidateExtraConfig - the extra directives must be single line redis.conf directives not managed
+// by the operator
+func (instance *RedisSpecCore) ValidateExtraConfig(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	path := basePath.Child("extraConfig")
+	for key, value := range instance.ExtraConfig {
+		switch {
+		case !configDirectiveRegexp.MatchString(key):
+			allErrs = append(allErrs, field.Invalid(path.Key(key), key,
+				"must be a lowercase redis.conf directive"))
+		case slices.Contains(ManagedConfigDirectives, key) || strings.HasPrefix(key, "tls-"):
+			allErrs = append(allErrs, field.Forbidden(path.Key(key),
+				"the directive is managed by the operator"))
+		case value == "" || strings.ContainsAny(value, "\r\n"):
+			allErrs = append(allErrs, field.Invalid(path.Key(key), value,
+				"must be a non-empty single line"))
+		}
+	}
+	return allErrs
+}
+
 // ValidateTopology -
 func (instance *RedisSpecCore) ValidateTopology(
 	basePath *field.Path,
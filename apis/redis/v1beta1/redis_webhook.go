@@ -23,6 +23,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
         "k8s.io/apimachinery/pkg/runtime/schema"
@@ -36,7 +38,8 @@ import (
 
 // RedisDefaults -
 type RedisDefaults struct {
-	ContainerImageURL string
+	ContainerImageURL         string
+	ExporterContainerImageURL string
 }
 
 var redisDefaults RedisDefaults
@@ -64,6 +67,9 @@ func (spec *RedisSpec) Default() {
 	if spec.ContainerImage == "" {
 		spec.ContainerImage = redisDefaults.ContainerImageURL
 	}
+	if spec.ExporterImage == "" {
+		spec.ExporterImage = redisDefaults.ExporterContainerImageURL
+	}
 	spec.RedisSpecCore.Default()
 }
 
@@ -90,6 +96,12 @@ func (r *Redis) ValidateCreate() (admission.Warnings, error) {
 	// When a TopologyRef CR is referenced, fail if a different Namespace is
 	// referenced because is not supported
 	allErrs = append(allErrs, r.Spec.ValidateTopology(basePath, r.Namespace)...)
+	allErrs = append(allErrs, r.Spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, r.Spec.PodCount())...)
+	allErrs = append(allErrs, r.Spec.ValidatePersistence(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateCluster(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateSentinel(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateMemory(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateExtraConfig(basePath)...)
 
 	if len(allErrs) != 0 {
 		return allWarn, apierrors.NewInvalid(
@@ -103,9 +115,14 @@ func (r *Redis) ValidateCreate() (admission.Warnings, error) {
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *Redis) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
+func (r *Redis) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	redislog.Info("validate update", "name", r.Name)
 
+	oldRedis, ok := old.(*Redis)
+	if !ok || oldRedis == nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("unable to convert existing object"))
+	}
+
 	var allErrs field.ErrorList
 	var allWarn []string
 	basePath := field.NewPath("spec")
@@ -113,6 +130,20 @@ func (r *Redis) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
 	// When a TopologyRef CR is referenced, fail if a different Namespace is
 	// referenced because is not supported
 	allErrs = append(allErrs, r.Spec.ValidateTopology(basePath, r.Namespace)...)
+	allErrs = append(allErrs, r.Spec.PodDisruptionBudget.ValidatePodDisruptionBudget(basePath, r.Spec.PodCount())...)
+	allErrs = append(allErrs, r.Spec.ValidatePersistence(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateCluster(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateSentinel(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateMemory(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidateExtraConfig(basePath)...)
+	allErrs = append(allErrs, r.Spec.ValidatePersistenceUpdate(basePath, &oldRedis.Spec.RedisSpecCore)...)
+
+	if len(allErrs) != 0 {
+		return allWarn, apierrors.NewInvalid(
+			schema.GroupKind{Group: "redis.openstack.org", Kind: "Redis"},
+			r.Name, allErrs,
+		)
+	}
 
 	return allWarn, nil
 }
@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisBackupSpec defines the desired state of RedisBackup
+type RedisBackupSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="redisName is immutable"
+	// RedisName - the name of the Redis instance in the namespace of the RedisBackup
+	RedisName string `json:"redisName"`
+
+	// +kubebuilder:validation:Optional
+	// Schedule - cron schedule of the backups, e.g. "0 3 * * *". A single backup is taken
+	// if not set.
+	Schedule string `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="target is immutable"
+	// Target - where the backups get stored
+	Target BackupTarget `json:"target"`
+}
+
+// BackupTarget - the storage of the backups, either a volume claim or an S3 compatible bucket
+// +kubebuilder:validation:XValidation:rule="has(self.pvc) != has(self.s3)",message="exactly one of pvc and s3 is required"
+type BackupTarget struct {
+	// +kubebuilder:validation:Optional
+	// PVC - store the backups in a volume claim
+	PVC *BackupPVCTarget `json:"pvc,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// S3 - upload the backups to an S3 compatible bucket
+	S3 *BackupS3Target `json:"s3,omitempty"`
+}
+
+// BackupPVCTarget - a volume claim holding the backups
+type BackupPVCTarget struct {
+	// +kubebuilder:validation:Required
+	// ClaimName - name of an existing volume claim in the namespace of the RedisBackup. A restore
+	// mounts it in the first redis pod, so it needs to be ReadWriteMany unless everything runs on
+	// the same node.
+	ClaimName string `json:"claimName"`
+}
+
+// BackupS3Target - an S3 compatible bucket holding the backups
+type BackupS3Target struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://[^/]+$"
+	// Endpoint - URL of the S3 endpoint, e.g. http://minio.minio.svc:9000. The bucket is
+	// addressed path-style.
+	Endpoint string `json:"endpoint"`
+
+	// +kubebuilder:validation:Required
+	// Bucket - name of an existing bucket
+	Bucket string `json:"bucket"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="us-east-1"
+	// Region - region used to sign the requests
+	Region string `json:"region,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// SecretName - name of the secret holding the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	SecretName string `json:"secretName"`
+}
+
+// BackupRecord - a backup taken from a Redis instance
+type BackupRecord struct {
+	// Location - path of the backup in the volume claim or key of the object in the bucket
+	Location string `json:"location"`
+
+	// Size - size of the RDB file in bytes
+	Size int64 `json:"size"`
+
+	// Checksum - sha256 checksum of the RDB file
+	Checksum string `json:"checksum"`
+
+	// Time - when the backup was taken
+	Time metav1.Time `json:"time"`
+}
+
+// RedisBackupStatus defines the observed state of RedisBackup
+type RedisBackupStatus struct {
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// ObservedGeneration - the most recent generation observed for this resource
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastBackup - the most recent successful backup
+	LastBackup *BackupRecord `json:"lastBackup,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=redisbackups,shortName=redisbackup,categories=all;redis
+//+kubebuilder:printcolumn:name="Redis",type="string",JSONPath=".spec.redisName"
+//+kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+//+kubebuilder:printcolumn:name="Last Backup",type="date",JSONPath=".status.lastBackup.time"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message"
+
+// RedisBackup is the Schema for the redisbackups API
+type RedisBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisBackupSpec   `json:"spec,omitempty"`
+	Status RedisBackupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RedisBackupList contains a list of RedisBackup
+type RedisBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RedisBackup{}, &RedisBackupList{})
+}
+
+// IsReady returns true if a backup was taken
+func (instance RedisBackup) IsReady() bool {
+	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
+}
+
+const (
+	// RedisBackupReadyCondition indicates that a backup was taken
+	RedisBackupReadyCondition condition.Type = "RedisBackupReady"
+
+	// RedisBackupReadyMessage is the message for the RedisBackupReady condition
+	RedisBackupReadyMessage = "Redis backup %s taken"
+
+	// RedisBackupReadyInitMessage is the message for the RedisBackupReady condition when not started
+	RedisBackupReadyInitMessage = "Redis backup not started"
+
+	// RedisBackupReadyRunningMessage is the message for the RedisBackupReady condition while the
+	// first backup is running
+	RedisBackupReadyRunningMessage = "Redis backup in progress"
+
+	// RedisBackupReadyWaitingMessage is the message for the RedisBackupReady condition when waiting for dependencies
+	RedisBackupReadyWaitingMessage = "Redis backup waiting for %s"
+
+	// RedisBackupReadyErrorMessage is the message format for the RedisBackupReady condition when an error occurs
+	RedisBackupReadyErrorMessage = "Redis backup error occurred %s"
+)
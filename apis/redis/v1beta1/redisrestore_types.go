@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisRestoreSpec defines the desired state of RedisRestore
+type RedisRestoreSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="redisName is immutable"
+	// RedisName - the name of the Redis instance to seed. It has to be created after the
+	// RedisRestore, a running Redis does not get overwritten.
+	RedisName string `json:"redisName"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="backupName is immutable"
+	// BackupName - the name of the RedisBackup whose last backup gets restored
+	BackupName string `json:"backupName"`
+}
+
+// RedisRestoreStatus defines the observed state of RedisRestore
+type RedisRestoreStatus struct {
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// ObservedGeneration - the most recent generation observed for this resource
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Backup - the backup the Redis instance gets seeded with, it is kept once the restore started
+	// so that newer backups don't change it
+	Backup *BackupRecord `json:"backup,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=redisrestores,shortName=redisrestore,categories=all;redis
+//+kubebuilder:printcolumn:name="Redis",type="string",JSONPath=".spec.redisName"
+//+kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backupName"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message"
+
+// RedisRestore is the Schema for the redisrestores API
+type RedisRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisRestoreSpec   `json:"spec,omitempty"`
+	Status RedisRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RedisRestoreList contains a list of RedisRestore
+type RedisRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RedisRestore{}, &RedisRestoreList{})
+}
+
+// IsReady returns true if the Redis instance got seeded
+func (instance RedisRestore) IsReady() bool {
+	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
+}
+
+const (
+	// RedisRestoreReadyCondition indicates that the Redis instance got seeded with the backup
+	RedisRestoreReadyCondition condition.Type = "RedisRestoreReady"
+
+	// RedisRestoreReadyMessage is the message for the RedisRestoreReady condition
+	RedisRestoreReadyMessage = "Redis %s restored from %s"
+
+	// RedisRestoreReadyInitMessage is the message for the RedisRestoreReady condition when not started
+	RedisRestoreReadyInitMessage = "Redis restore not started"
+
+	// RedisRestoreReadyRunningMessage is the message for the RedisRestoreReady condition while the
+	// Redis instance bootstraps from the backup
+	RedisRestoreReadyRunningMessage = "Redis restore in progress"
+
+	// RedisRestoreReadyWaitingMessage is the message for the RedisRestoreReady condition when waiting for dependencies
+	RedisRestoreReadyWaitingMessage = "Redis restore waiting for %s"
+
+	// RedisRestoreReadyErrorMessage is the message format for the RedisRestoreReady condition when an error occurs
+	RedisRestoreReadyErrorMessage = "Redis restore error occurred %s"
+
+	// RedisRestoreRunningRedisMessage is the message for the RedisRestoreReady condition when the
+	// Redis instance already runs without the restore
+	RedisRestoreRunningRedisMessage = "Redis %s is already running, restore into a Redis created after the RedisRestore"
+)
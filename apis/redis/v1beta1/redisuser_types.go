@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisUserSpec defines the desired state of RedisUser
+type RedisUserSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="redisName is immutable"
+	// RedisName - the name of the Redis instance in the namespace of the RedisUser
+	RedisName string `json:"redisName"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9_.-]+$"
+	// +kubebuilder:validation:XValidation:rule="self != 'default'",message="the default user is managed by the Redis instance"
+	// Username - the ACL username in Redis (defaults to CR name)
+	Username string `json:"username,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={"+@all"}
+	// +kubebuilder:validation:items:Pattern="^[+-][@a-zA-Z0-9_|-]+$"
+	// Commands - ACL command rules applied in order, e.g. +@read, -@dangerous or +config|get
+	Commands []string `json:"commands,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={"*"}
+	// +kubebuilder:validation:items:Pattern="^[^\\s]+$"
+	// Keys - glob patterns of the keys the user can access, e.g. cache:*
+	Keys []string `json:"keys,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Pattern="^[^\\s]+$"
+	// Channels - glob patterns of the pub/sub channels the user can access, none if empty
+	Channels []string `json:"channels,omitempty"`
+}
+
+// RedisUserStatus defines the observed state of RedisUser
+type RedisUserStatus struct {
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// ObservedGeneration - the most recent generation observed for this resource
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SecretName - name of the secret holding the credentials and the connection URL of the user
+	SecretName string `json:"secretName,omitempty"`
+
+	// Username - actual username used in Redis
+	Username string `json:"username,omitempty"`
+
+	// AppliedPods - the redis pods the user is set on, with the pod UID, the restart count of
+	// the redis container and the hash of the ACL rules at the time it was set
+	AppliedPods map[string]string `json:"appliedPods,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=redisusers,shortName=redisuser,categories=all;redis
+//+kubebuilder:printcolumn:name="Redis",type="string",JSONPath=".spec.redisName"
+//+kubebuilder:printcolumn:name="Username",type="string",JSONPath=".status.username"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message"
+
+// RedisUser is the Schema for the redisusers API
+type RedisUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisUserSpec   `json:"spec,omitempty"`
+	Status RedisUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RedisUserList contains a list of RedisUser
+type RedisUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RedisUser{}, &RedisUserList{})
+}
+
+// IsReady returns true if the user is ready
+func (instance RedisUser) IsReady() bool {
+	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
+}
+
+// GetUsername returns the ACL username, the CR name if none is set
+func (instance RedisUser) GetUsername() string {
+	if instance.Spec.Username != "" {
+		return instance.Spec.Username
+	}
+	return instance.Name
+}
+
+const (
+	// RedisUserSecretPrefix - prefix of the secret holding the credentials of a RedisUser
+	RedisUserSecretPrefix = "redis-user-"
+	// RedisUserUsernameKey - username key in the user secret
+	RedisUserUsernameKey = "username"
+	// RedisUserPasswordKey - password key in the user secret
+	RedisUserPasswordKey = "password"
+	// RedisUserURLKey - connection URL key in the user secret
+	RedisUserURLKey = "url"
+
+	// RedisUserReadyCondition indicates that the user is applied to all redis servers
+	RedisUserReadyCondition condition.Type = "RedisUserReady"
+
+	// RedisUserReadyMessage is the message for the RedisUserReady condition
+	RedisUserReadyMessage = "Redis user is ready"
+
+	// RedisUserReadyInitMessage is the message for the RedisUserReady condition when not started
+	RedisUserReadyInitMessage = "Redis user not started"
+
+	// RedisUserReadyWaitingMessage is the message for the RedisUserReady condition when waiting for dependencies
+	RedisUserReadyWaitingMessage = "Redis user waiting for %s"
+
+	// RedisUserReadyErrorMessage is the message format for the RedisUserReady condition when an error occurs
+	RedisUserReadyErrorMessage = "Redis user error occurred %s"
+
+	// RedisUserAuthDisabledMessage is the message for the RedisUserReady condition when the Redis
+	// instance does not require a password
+	RedisUserAuthDisabledMessage = "Redis %s does not have authentication enabled"
+)
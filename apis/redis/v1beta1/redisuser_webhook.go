@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var redisuserlog = logf.Log.WithName("redisuser-resource")
+
+//+kubebuilder:webhook:path=/validate-redis-openstack-org-v1beta1-redisuser,mutating=false,failurePolicy=fail,sideEffects=None,groups=redis.openstack.org,resources=redisusers,verbs=create;update,versions=v1beta1,name=vredisuser.kb.io,admissionReviewVersions=v1
+
+// ValidateCreate validates the RedisUser on creation
+func (r *RedisUser) ValidateCreate(k8sClient client.Client) (admission.Warnings, error) {
+	redisuserlog.Info("validate create", "name", r.Name)
+
+	return nil, r.validateUniqueUsername(k8sClient)
+}
+
+// ValidateUpdate validates the RedisUser on update
+func (r *RedisUser) ValidateUpdate(k8sClient client.Client, _ runtime.Object) (admission.Warnings, error) {
+	redisuserlog.Info("validate update", "name", r.Name)
+
+	return nil, r.validateUniqueUsername(k8sClient)
+}
+
+// ValidateDelete validates the RedisUser on deletion
+func (r *RedisUser) ValidateDelete(_ client.Client) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateUniqueUsername checks that no other RedisUser of the same Redis instance uses the
+// same ACL username, the users would overwrite each other on the redis servers
+func (r *RedisUser) validateUniqueUsername(k8sClient client.Client) error {
+	// RedisUsers always belong to a Redis instance in their own namespace
+	userList := &RedisUserList{}
+	if err := k8sClient.List(context.TODO(), userList, client.InNamespace(r.Namespace)); err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to list RedisUsers: %w", err))
+	}
+
+	for _, user := range userList.Items {
+		if user.Name == r.Name || user.Spec.RedisName != r.Spec.RedisName {
+			continue
+		}
+		if user.GetUsername() == r.GetUsername() {
+			return apierrors.NewInvalid(
+				schema.GroupKind{Group: "redis.openstack.org", Kind: "RedisUser"},
+				r.Name,
+				field.ErrorList{
+					field.Duplicate(
+						field.NewPath("spec", "username"),
+						fmt.Sprintf("username %q already exists on redis %q (existing RedisUser: %s)",
+							r.GetUsername(), r.Spec.RedisName, user.Name),
+					),
+				},
+			)
+		}
+	}
+	return nil
+}
@@ -297,6 +297,30 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Redis")
 		os.Exit(1)
 	}
+	if err := (&rediscontroller.RedisUserReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Kclient: kclient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RedisUser")
+		os.Exit(1)
+	}
+	if err := (&rediscontroller.RedisBackupReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Kclient: kclient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RedisBackup")
+		os.Exit(1)
+	}
+	if err := (&rediscontroller.RedisRestoreReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Kclient: kclient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RedisRestore")
+		os.Exit(1)
+	}
 	if err := (&networkcontroller.DNSMasqReconciler{
 		Client:  mgr.GetClient(),
 		Scheme:  mgr.GetScheme(),
@@ -347,27 +371,30 @@ func main() {
 	}
 
 	if err := (&rabbitmqcontroller.RabbitMQVhostReconciler{
-		Client:  mgr.GetClient(),
-		Scheme:  mgr.GetScheme(),
-		Kclient: kclient,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Kclient:  kclient,
+		Recorder: mgr.GetEventRecorderFor("rabbitmqvhost-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RabbitMQVhost")
 		os.Exit(1)
 	}
 
 	if err := (&rabbitmqcontroller.RabbitMQUserReconciler{
-		Client:  mgr.GetClient(),
-		Scheme:  mgr.GetScheme(),
-		Kclient: kclient,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Kclient:  kclient,
+		Recorder: mgr.GetEventRecorderFor("rabbitmquser-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RabbitMQUser")
 		os.Exit(1)
 	}
 
 	if err := (&rabbitmqcontroller.RabbitMQPolicyReconciler{
-		Client:  mgr.GetClient(),
-		Scheme:  mgr.GetScheme(),
-		Kclient: kclient,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Kclient:  kclient,
+		Recorder: mgr.GetEventRecorderFor("rabbitmqpolicy-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RabbitMQPolicy")
 		os.Exit(1)
@@ -395,6 +422,10 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Redis")
 			os.Exit(1)
 		}
+		if err := webhookredisv1beta1.SetupRedisUserWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RedisUser")
+			os.Exit(1)
+		}
 		if err := webhooknetworkv1beta1.SetupDNSMasqWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "DNSMasq")
 			os.Exit(1)
@@ -419,6 +450,10 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "RabbitMQVhost")
 			os.Exit(1)
 		}
+		if err := webhookrabbitmqv1beta1.SetupTransportURLWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TransportURL")
+			os.Exit(1)
+		}
 		if err := webhooknetworkv1beta1.SetupNetConfigWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "NetConfig")
 			os.Exit(1)
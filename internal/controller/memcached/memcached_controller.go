@@ -18,16 +18,27 @@ limitations under the License.
 package memcached
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"net"
+	"slices"
+	"strconv"
 	"time"
 
+	networkv1 "github.com/openstack-k8s-operators/infra-operator/apis/network/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	"github.com/openstack-k8s-operators/lib-common/modules/common"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	configmap "github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/labels"
 	common_rbac "github.com/openstack-k8s-operators/lib-common/modules/common/rbac"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/rsh"
+	oko_secret "github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	commonservice "github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	commonstatefulset "github.com/openstack-k8s-operators/lib-common/modules/common/statefulset"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
@@ -37,12 +48,16 @@ import (
 	util "github.com/openstack-k8s-operators/lib-common/modules/common/util"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -50,6 +65,8 @@ import (
 	"github.com/go-logr/logr"
 	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
 	memcached "github.com/openstack-k8s-operators/infra-operator/internal/memcached"
+	"github.com/openstack-k8s-operators/infra-operator/internal/monitoring"
+	"github.com/openstack-k8s-operators/infra-operator/internal/pdb"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -71,12 +88,23 @@ var allWatchFields = []string{
 	topologyField,
 }
 
+// metricsRefreshInterval - how often the stats summary in the status gets refreshed
+const metricsRefreshInterval = 60 * time.Second
+
+// certReloadRetryInterval - how often a cert reload gets retried, e.g. while the
+// kubelet did not yet update the mounted secret
+const certReloadRetryInterval = 10 * time.Second
+
 // Reconciler reconciles a Memcached object
 type Reconciler struct {
 	client.Client
 	Kclient kubernetes.Interface
 	config  *rest.Config
 	Scheme  *runtime.Scheme
+	// Scraper - used to collect the stats of the exporters, defaults to memcached.DefaultScraper
+	Scraper memcached.Scraper
+	// CertReloader - runs the cert reload command in a memcached pod, defaults to an exec into the pod
+	CertReloader func(ctx context.Context, pod types.NamespacedName, cmd []string) error
 }
 
 // GetLogger returns a logger object with a prefix of "controller.name" and additional controller context fields
@@ -93,6 +121,12 @@ func (r *Reconciler) GetLogger(ctx context.Context) logr.Logger {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
 
+// RBAC for the generated SASL credentials
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+
+// RBAC for the ServiceMonitor of the exporters
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+
 // RBAC for services
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete;
 
@@ -105,6 +139,15 @@ func (r *Reconciler) GetLogger(ctx context.Context) logr.Logger {
 // +kubebuilder:rbac:groups="",resources=pods,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=topology.openstack.org,resources=topologies,verbs=get;list;watch;update
 
+// RBAC for the config maps of the servers and pools
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+// Required to reload renewed certificates in the pods
+// +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
+
+// Required to limit voluntary disruptions of the pods
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+
 // Reconcile - Memcached
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, _err error) {
 	Log := r.GetLogger(ctx)
@@ -185,6 +228,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		condition.UnknownCondition(condition.ServiceAccountReadyCondition, condition.InitReason, condition.ServiceAccountReadyInitMessage),
 		condition.UnknownCondition(condition.RoleReadyCondition, condition.InitReason, condition.RoleReadyInitMessage),
 		condition.UnknownCondition(condition.RoleBindingReadyCondition, condition.InitReason, condition.RoleBindingReadyInitMessage),
+		// PodDisruptionBudget
+		condition.UnknownCondition(condition.PDBReadyCondition, condition.InitReason, condition.PDBReadyInitMessage),
+		// staged resize progress
+		condition.UnknownCondition(memcachedv1.MemcachedResizeReadyCondition, condition.InitReason, memcachedv1.MemcachedResizeReadyInitMessage),
 	)
 
 	instance.Status.Conditions.Init(&cl)
@@ -242,6 +289,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 
 	// Hash of all resources that may cause a service restart
 	inputHashEnv := make(map[string]env.Setter)
+	// Hash of the certificates the pods can reload without a restart
+	certHashEnv := make(map[string]env.Setter)
+	if !memcached.CertReloadSupported(instance) {
+		certHashEnv = inputHashEnv
+	}
 
 	//
 	// TLS input validation
@@ -301,7 +353,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 				err.Error()))
 			return ctrl.Result{}, err
 		}
-		inputHashEnv["Cert"] = env.SetValue(hash)
+		certHashEnv["Cert"] = env.SetValue(hash)
 	}
 
 	// Validate client cert secret
@@ -325,7 +377,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 					err.Error()))
 				return ctrl.Result{}, err
 			}
-			inputHashEnv["ClientCert"] = env.SetValue(hash)
+			certHashEnv["ClientCert"] = env.SetValue(hash)
 			instance.Status.MTLSCert = *instance.Spec.TLS.MTLS.AuthCertSecret.SecretName
 			instance.Status.Conditions.MarkTrue(memcachedv1.MTLSInputReadyCondition, condition.InputReadyMessage)
 		} else {
@@ -341,8 +393,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	// all cert input checks out so report InputReady
 	instance.Status.Conditions.MarkTrue(condition.TLSInputReadyCondition, condition.InputReadyMessage)
 
+	// SASL credentials
+	if instance.Spec.Auth.Enabled {
+		hash, err := r.ensureAuthSecret(ctx, instance)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.ServiceConfigReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.ServiceConfigReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, fmt.Errorf("error creating auth secret: %w", err)
+		}
+		inputHashEnv["Auth"] = env.SetValue(hash)
+		instance.Status.AuthSecret = memcached.AuthSecretName(instance)
+	} else {
+		instance.Status.AuthSecret = ""
+	}
+
 	// Memcached config maps
-	err = r.generateConfigMaps(ctx, helper, instance, &inputHashEnv)
+	err = r.generateConfigMaps(ctx, helper, instance, instance, &inputHashEnv)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.ServiceConfigReadyCondition,
@@ -397,13 +467,84 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return sres, serr
 	}
 
+	// The autoscaler decides on the replicas only while it is configured
+	if instance.Spec.Autoscaling == nil {
+		instance.Status.Autoscaling = nil
+	}
+
+	// Decide how many replicas the server lists contain
+	resizeRequeue, err := r.reconcileResize(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// TODO: We have to make sure this works properly in dual stack env (if we support it)
 	ipFamily := commonsvc.GetIPFamilies()[0]
-	serverList, serverListWithInet := r.GetServerLists(instance, ipFamily)
-	instance.Status.ServerList = serverList
-	instance.Status.ServerListWithInet = serverListWithInet
 
-	instance.Status.Conditions.MarkTrue(condition.CreateServiceReadyCondition, condition.CreateServiceReadyMessage)
+	// Per-pod services make the replicas reachable from outside of the cluster,
+	// the server lists then point to them. Until all of them got an address the
+	// previous server lists are kept.
+	perPodResult := ctrl.Result{}
+	if instance.Spec.PodOverride != nil && len(instance.Spec.PodOverride.Services) > 0 {
+		var endpoints []string
+		perPodResult, endpoints, err = r.reconcilePerPodServices(ctx, helper, instance)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.CreateServiceReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.CreateServiceReadyErrorMessage,
+				err.Error()))
+			return perPodResult, err
+		}
+		if (perPodResult == ctrl.Result{}) {
+			if len(endpoints) > int(instance.Status.ListedReplicas) {
+				endpoints = endpoints[:instance.Status.ListedReplicas]
+			}
+			serverList, serverListWithInet := r.GetPerPodServerLists(instance, endpoints, ipFamily)
+			instance.Status.ServerList = serverList
+			instance.Status.ServerListWithInet = serverListWithInet
+			instance.Status.Conditions.MarkTrue(condition.CreateServiceReadyCondition, condition.CreateServiceReadyMessage)
+		} else {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.CreateServiceReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				condition.CreateServiceReadyRunningMessage))
+		}
+	} else {
+		if len(instance.Status.ServiceHostnames) > 0 {
+			// PodOverride was removed, clean up per-pod services
+			if err := r.deletePerPodServices(ctx, instance, 0); err != nil {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					condition.CreateServiceReadyCondition,
+					condition.ErrorReason,
+					condition.SeverityWarning,
+					condition.CreateServiceReadyErrorMessage,
+					err.Error()))
+				return ctrl.Result{}, err
+			}
+			instance.Status.ServiceHostnames = nil
+		}
+		serverList, serverListWithInet := r.GetServerLists(instance, ipFamily)
+		instance.Status.ServerList = serverList
+		instance.Status.ServerListWithInet = serverListWithInet
+		instance.Status.Conditions.MarkTrue(condition.CreateServiceReadyCondition, condition.CreateServiceReadyMessage)
+	}
+
+	// Ready-made client configuration for the consumers of the server lists
+	err = r.ensureClientConfigSecret(ctx, helper, instance)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ServiceConfigReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.ServiceConfigReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, fmt.Errorf("error creating client config secret: %w", err)
+	}
+	instance.Status.ClientConfigSecret = memcached.ClientConfigSecretName(instance)
+
 	serviceLabels := map[string]string{
 		"app":                instance.Name,
 		common.AppSelector:   instance.Name,
@@ -444,6 +585,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		// remove LastAppliedTopology from the .Status
 		instance.Status.LastAppliedTopology = nil
 	}
+	// PodDisruptionBudget
+	pdbRes, err := pdb.EnsurePodDisruptionBudget(
+		ctx, helper, &instance.Spec.PodDisruptionBudget, memcached.Replicas(instance), instance.Name, instance.Namespace, serviceLabels)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.PDBReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.PDBReadyErrorMessage,
+			err.Error()))
+		return pdbRes, err
+	} else if (pdbRes != ctrl.Result{}) {
+		return pdbRes, nil
+	}
+	instance.Status.Conditions.MarkTrue(condition.PDBReadyCondition, condition.PDBReadyMessage)
+
 	// Statefulset for stable names
 	ss := commonstatefulset.NewStatefulSet(
 		memcached.StatefulSet(instance, hashOfHashes, topology), time.Duration(5)*time.Second)
@@ -452,6 +609,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return sfres, sferr
 	}
 
+	// Config, service and StatefulSet of each pool
+	poolsReady, err := r.reconcilePools(ctx, helper, instance, inputHashEnv, topology, ipFamily)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.DeploymentReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
 	//
 	// Reconstruct the state of the memcached resource based on the statefulset and its pods
 	//
@@ -459,7 +628,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	if deploy.Generation == deploy.Status.ObservedGeneration {
 		instance.Status.ReadyCount = deploy.Status.ReadyReplicas
 	}
-	if commonstatefulset.IsReady(deploy) {
+	if commonstatefulset.IsReady(deploy) && poolsReady {
 		instance.Status.Conditions.MarkTrue(condition.DeploymentReadyCondition, condition.DeploymentReadyMessage)
 	} else {
 		instance.Status.Conditions.Set(condition.FalseCondition(
@@ -471,34 +640,537 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return ctrl.Result{}, nil
 	}
 
+	// Renewed certificates
+	if memcached.CertReloadSupported(instance) {
+		reloadResult, err := r.reconcileCertReload(ctx, instance, certHashEnv)
+		if err != nil || (reloadResult != ctrl.Result{}) {
+			return reloadResult, err
+		}
+	} else {
+		delete(instance.Status.Hash, memcached.CertHashName)
+	}
+
+	// ServiceMonitor and stats summary of the exporters
+	ctrlResult, err := r.reconcileMetrics(ctx, instance)
+	if err != nil {
+		return ctrlResult, err
+	}
+
+	// Wait for the addresses of the per-pod services
+	if (perPodResult != ctrl.Result{}) {
+		return perPodResult, nil
+	}
+
+	// Come back when the warmup or grace period of a staged resize is over
+	if resizeRequeue > 0 && (ctrlResult.RequeueAfter == 0 || resizeRequeue < ctrlResult.RequeueAfter) {
+		ctrlResult.RequeueAfter = resizeRequeue
+	}
+
 	// We reached the end of the Reconcile, update the Ready condition based on
 	// the sub conditions
 	if instance.Status.Conditions.AllSubConditionIsTrue() {
 		instance.Status.Conditions.MarkTrue(
 			condition.ReadyCondition, condition.ReadyMessage)
 	}
+	return ctrlResult, nil
+}
+
+// reconcilePools creates the config, headless service and StatefulSet of each pool, deletes the
+// ones of removed pools and reports the server lists of the pools in the status. The pools share
+// all inputs of the instance except for its config map. It returns whether all pools are ready.
+func (r *Reconciler) reconcilePools(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *memcachedv1.Memcached,
+	inputHashEnv map[string]env.Setter,
+	topology *topologyv1.Topology,
+	ipFamily corev1.IPFamily,
+) (bool, error) {
+	ready := true
+	pools := []memcachedv1.PoolStatus{}
+	for _, pool := range instance.Spec.Pools {
+		servers := memcached.PoolMemcached(instance, pool)
+
+		hashEnv := make(map[string]env.Setter)
+		for k, v := range inputHashEnv {
+			if k != fmt.Sprintf("%s-config-data", instance.Name) {
+				hashEnv[k] = v
+			}
+		}
+		if err := r.generateConfigMaps(ctx, h, instance, servers, &hashEnv); err != nil {
+			return false, err
+		}
+		hash, err := util.HashOfInputHashes(hashEnv)
+		if err != nil {
+			return false, err
+		}
+
+		svc, err := commonservice.NewService(memcached.HeadlessService(servers), time.Duration(5)*time.Second, nil)
+		if err != nil {
+			return false, err
+		}
+		if _, err := svc.CreateOrPatch(ctx, h); err != nil {
+			return false, err
+		}
+
+		ss := commonstatefulset.NewStatefulSet(
+			memcached.PoolStatefulSet(instance, pool, hash, topology), time.Duration(5)*time.Second)
+		if _, err := ss.CreateOrPatch(ctx, h); err != nil {
+			return false, err
+		}
+		deploy := ss.GetStatefulSet()
+
+		// Each pool gets its own PodDisruptionBudget, the one of the instance only
+		// selects the pods of its own StatefulSet
+		poolLabels := map[string]string{
+			"app":                servers.Name,
+			common.AppSelector:   servers.Name,
+			"cr":                 servers.Name,
+			common.OwnerSelector: "infra-operator",
+		}
+		if _, err := pdb.EnsurePodDisruptionBudget(
+			ctx, h, &instance.Spec.PodDisruptionBudget, *servers.Spec.Replicas, servers.Name, instance.Namespace, poolLabels); err != nil {
+			return false, err
+		}
+
+		status := memcachedv1.PoolStatus{Name: pool.Name}
+		if deploy.Generation == deploy.Status.ObservedGeneration {
+			status.ReadyCount = deploy.Status.ReadyReplicas
+		}
+		ready = ready && commonstatefulset.IsReady(deploy)
+		status.ServerList, status.ServerListWithInet = r.GetServerLists(servers, ipFamily)
+		pools = append(pools, status)
+	}
+
+	for _, old := range instance.Status.Pools {
+		if slices.ContainsFunc(instance.Spec.Pools, func(p memcachedv1.MemcachedPool) bool { return p.Name == old.Name }) {
+			continue
+		}
+		name := memcached.PoolName(instance, old.Name)
+		for _, obj := range []client.Object{
+			&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace}},
+			&policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name + "-config-data", Namespace: instance.Namespace}},
+		} {
+			if err := r.Delete(ctx, obj); err != nil && !k8s_errors.IsNotFound(err) {
+				return false, err
+			}
+		}
+	}
+	instance.Status.Pools = pools
+
+	return ready, nil
+}
+
+// reconcileCertReload makes the running pods load a renewed certificate. The pods of a new
+// StatefulSet or of a rollout already started with it, so the hash only gets recorded then.
+func (r *Reconciler) reconcileCertReload(
+	ctx context.Context,
+	instance *memcachedv1.Memcached,
+	certHashEnv map[string]env.Setter,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	certHash, err := util.HashOfInputHashes(certHashEnv)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	lastHash, found := instance.Status.Hash[memcached.CertHashName]
+	if lastHash == certHash {
+		return ctrl.Result{}, nil
+	}
+	if !found {
+		instance.Status.Hash[memcached.CertHashName] = certHash
+		return ctrl.Result{}, nil
+	}
+
+	certSecret := &corev1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Name: *instance.Spec.TLS.SecretName, Namespace: instance.Namespace}, certSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	cmd := memcached.CertReloadCommand(
+		fmt.Sprintf("%x", sha256.Sum256(certSecret.Data[tls.CertKey])),
+		fmt.Sprintf("%x", sha256.Sum256(certSecret.Data[tls.PrivateKey])),
+	)
+
+	pods := []string{}
+	for i := int32(0); i < *memcached.StatefulSetReplicas(instance); i++ {
+		pods = append(pods, fmt.Sprintf("%s-%d", instance.Name, i))
+	}
+	for _, pool := range instance.Spec.Pools {
+		for i := int32(0); i < ptr.Deref(pool.Replicas, 1); i++ {
+			pods = append(pods, fmt.Sprintf("%s-%d", memcached.PoolName(instance, pool.Name), i))
+		}
+	}
+	for _, podName := range pods {
+		pod := types.NamespacedName{Name: podName, Namespace: instance.Namespace}
+		if err := r.CertReloader(ctx, pod, cmd); err != nil {
+			Log.Info(fmt.Sprintf("Reloading the certificate in %s failed, retrying in %s: %s", pod.Name, certReloadRetryInterval, err))
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.TLSInputReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				memcachedv1.CertReloadWaitingMessage,
+				pod.Name))
+			return ctrl.Result{RequeueAfter: certReloadRetryInterval}, nil
+		}
+	}
+
+	Log.Info("Reloaded the renewed certificate", "pods", len(pods))
+	instance.Status.Hash[memcached.CertHashName] = certHash
+	now := metav1.Now()
+	instance.Status.LastCertReload = &now
 	return ctrl.Result{}, nil
 }
 
-// generateConfigMaps returns the config map resource for a memcached instance
+// execCertReload runs the cert reload command in the memcached container of the pod
+func (r *Reconciler) execCertReload(ctx context.Context, pod types.NamespacedName, cmd []string) error {
+	return rsh.ExecInPod(ctx, r.Kclient, r.config, pod, "memcached", cmd,
+		func(_ *bytes.Buffer, stderr *bytes.Buffer) error {
+			if stderr.Len() > 0 {
+				return fmt.Errorf("%s", stderr.String())
+			}
+			return nil
+		},
+	)
+}
+
+// reconcileResize updates the number of listed replicas according to the resize strategy. With the
+// Staged strategy new servers get listed once all pods were ready for the warmup period, removed
+// servers get unlisted first and memcached.StatefulSetReplicas keeps their pods for the grace period.
+// It returns when the next phase is due.
+func (r *Reconciler) reconcileResize(ctx context.Context, instance *memcachedv1.Memcached) (time.Duration, error) {
+	desired := memcached.Replicas(instance)
+	listed := instance.Status.ListedReplicas
+	if listed == 0 {
+		// instances created before the server list size was tracked
+		listed = int32(len(instance.Status.ServerList))
+	}
+
+	// The first deployment and the Immediate strategy list all replicas right away
+	if instance.Spec.Resize.Strategy != memcachedv1.ResizeStrategyStaged || listed == 0 {
+		instance.Status.ListedReplicas = desired
+		instance.Status.Resize = nil
+		instance.Status.Conditions.MarkTrue(memcachedv1.MemcachedResizeReadyCondition, memcachedv1.MemcachedResizeReadyMessage)
+		return 0, nil
+	}
+
+	now := metav1.Now()
+	resize := instance.Status.Resize
+	if resize == nil || resize.To != desired {
+		switch {
+		case desired > listed:
+			resize = &memcachedv1.ResizeStatus{From: listed, To: desired, Phase: memcachedv1.ResizePhaseWarmingUp}
+		case desired < listed:
+			from := listed
+			if resize != nil && resize.Phase == memcachedv1.ResizePhaseDraining && resize.From > from {
+				// the pods of the previous scale down are still running
+				from = resize.From
+			}
+			resize = &memcachedv1.ResizeStatus{From: from, To: desired, Phase: memcachedv1.ResizePhaseDraining, Started: &now}
+			// unlist the removed servers first
+			listed = desired
+		default:
+			resize = nil
+		}
+	}
+	instance.Status.ListedReplicas = listed
+	instance.Status.Resize = resize
+	if resize == nil {
+		instance.Status.Conditions.MarkTrue(memcachedv1.MemcachedResizeReadyCondition, memcachedv1.MemcachedResizeReadyMessage)
+		return 0, nil
+	}
+
+	var remaining time.Duration
+	switch resize.Phase {
+	case memcachedv1.ResizePhaseWarmingUp:
+		if resize.Started == nil {
+			sts := &appsv1.StatefulSet{}
+			err := r.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, sts)
+			if err != nil && !k8s_errors.IsNotFound(err) {
+				return 0, err
+			}
+			// the warmup starts once all the new pods are ready, the watch on the
+			// StatefulSet triggers the reconcile
+			if err != nil || sts.Generation != sts.Status.ObservedGeneration ||
+				sts.Spec.Replicas == nil || *sts.Spec.Replicas != desired || sts.Status.ReadyReplicas < desired {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					memcachedv1.MemcachedResizeReadyCondition,
+					condition.RequestedReason,
+					condition.SeverityInfo,
+					memcachedv1.MemcachedResizeWarmingUpMessage,
+					resize.From, resize.To))
+				return 0, nil
+			}
+			resize.Started = &now
+		}
+		remaining = time.Duration(instance.Spec.Resize.WarmupSeconds)*time.Second - now.Sub(resize.Started.Time)
+		if remaining > 0 {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				memcachedv1.MemcachedResizeReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				memcachedv1.MemcachedResizeWarmingUpMessage,
+				resize.From, resize.To))
+			return remaining, nil
+		}
+	case memcachedv1.ResizePhaseDraining:
+		remaining = time.Duration(instance.Spec.Resize.GracePeriodSeconds)*time.Second - now.Sub(resize.Started.Time)
+		if remaining > 0 {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				memcachedv1.MemcachedResizeReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				memcachedv1.MemcachedResizeDrainingMessage,
+				resize.From, resize.To))
+			return remaining, nil
+		}
+	}
+
+	// phase is over
+	instance.Status.ListedReplicas = desired
+	instance.Status.Resize = nil
+	instance.Status.Conditions.MarkTrue(memcachedv1.MemcachedResizeReadyCondition, memcachedv1.MemcachedResizeReadyMessage)
+	return 0, nil
+}
+
+// reconcilePerPodServices creates a service for each pod from spec.podOverride and registers the
+// hostnames of the LoadBalancer ones with dnsmasq. It returns the endpoint of each pod, the external
+// IP of LoadBalancer services and the service hostname otherwise.
+func (r *Reconciler) reconcilePerPodServices(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *memcachedv1.Memcached,
+) (ctrl.Result, []string, error) {
+	replicas := int(*instance.Spec.Replicas)
+	if len(instance.Spec.PodOverride.Services) != replicas {
+		return ctrl.Result{}, nil, fmt.Errorf("number of services in podOverride (%d) must match number of replicas (%d)",
+			len(instance.Spec.PodOverride.Services), replicas)
+	}
+
+	endpoints := []string{}
+	serviceHostnames := []string{}
+	requeueNeeded := false
+	for i := 0; i < replicas; i++ {
+		svc, err := commonservice.NewService(
+			memcached.PerPodService(instance, i),
+			time.Duration(5)*time.Second,
+			&instance.Spec.PodOverride.Services[i],
+		)
+		if err != nil {
+			return ctrl.Result{}, nil, err
+		}
+
+		if svc.GetServiceType() == corev1.ServiceTypeLoadBalancer {
+			svc.AddAnnotation(map[string]string{
+				networkv1.AnnotationHostnameKey: svc.GetServiceHostname(),
+			})
+		}
+
+		ctrlResult, err := svc.CreateOrPatch(ctx, h)
+		if err != nil {
+			if !errors.Is(err, util.ErrResourceIsNotReady) {
+				return ctrlResult, nil, err
+			}
+			// LoadBalancer IP still pending, continue with the other services
+			requeueNeeded = true
+		} else if (ctrlResult != ctrl.Result{}) {
+			requeueNeeded = true
+		}
+
+		serviceHostnames = append(serviceHostnames, svc.GetServiceHostname())
+		if ips := svc.GetExternalIPs(); len(ips) > 0 && ips[0] != "" {
+			endpoints = append(endpoints, ips[0])
+		} else {
+			endpoints = append(endpoints, svc.GetServiceHostname())
+		}
+	}
+
+	// The pods of a scale down keep their service until they are gone, a staged
+	// scale down still drains them. The watch on the StatefulSet triggers the
+	// reconcile which removes the services once the pods terminated.
+	inUse, err := r.perPodServicesInUse(ctx, instance, replicas)
+	if err != nil {
+		return ctrl.Result{}, nil, err
+	}
+	if inUse > replicas {
+		serviceHostnames = append(serviceHostnames, instance.Status.ServiceHostnames[replicas:inUse]...)
+	}
+	if err := r.deletePerPodServices(ctx, instance, inUse); err != nil {
+		return ctrl.Result{}, nil, err
+	}
+	instance.Status.ServiceHostnames = serviceHostnames
+
+	if requeueNeeded {
+		return ctrl.Result{RequeueAfter: time.Duration(5) * time.Second}, nil, nil
+	}
+	return ctrl.Result{}, endpoints, nil
+}
+
+// perPodServicesInUse returns the number of per-pod services listed in the status which are
+// still needed, at least replicas and up to the highest index of a pod which still exists
+func (r *Reconciler) perPodServicesInUse(ctx context.Context, instance *memcachedv1.Memcached, replicas int) (int, error) {
+	inUse := replicas
+	for i := replicas; i < len(instance.Status.ServiceHostnames); i++ {
+		pod := &corev1.Pod{}
+		err := r.Get(ctx, types.NamespacedName{Name: memcached.PerPodServiceName(instance, i), Namespace: instance.Namespace}, pod)
+		if err != nil {
+			if k8s_errors.IsNotFound(err) {
+				continue
+			}
+			return 0, err
+		}
+		inUse = i + 1
+	}
+	return inUse, nil
+}
+
+// deletePerPodServices deletes the per-pod services listed in the status starting at index from
+func (r *Reconciler) deletePerPodServices(ctx context.Context, instance *memcachedv1.Memcached, from int) error {
+	for i := from; i < len(instance.Status.ServiceHostnames); i++ {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      memcached.PerPodServiceName(instance, i),
+				Namespace: instance.Namespace,
+			},
+		}
+		if err := r.Delete(ctx, svc); err != nil && !k8s_errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileMetrics creates the ServiceMonitor of the exporters when the Prometheus operator is
+// installed and refreshes the stats summary in the status
+func (r *Reconciler) reconcileMetrics(
+	ctx context.Context,
+	instance *memcachedv1.Memcached,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	if !instance.Spec.Metrics.Enabled {
+		instance.Status.Metrics = nil
+		return ctrl.Result{}, monitoring.DeleteServiceMonitor(ctx, r.Client, instance.Name, instance)
+	}
+
+	sm := monitoring.NewServiceMonitor(instance.Name, instance.Namespace)
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, sm, func() error {
+		if err := memcached.SetServiceMonitorSpec(sm, instance); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(instance, sm, r.Scheme)
+	})
+	if err != nil {
+		if !meta.IsNoMatchError(err) {
+			return ctrl.Result{}, err
+		}
+		Log.Info("ServiceMonitor CRD not installed, not creating a ServiceMonitor")
+	}
+
+	// Writing the stats triggers a new reconcile, so only refresh them periodically
+	if instance.Status.Metrics != nil &&
+		time.Since(instance.Status.Metrics.LastUpdated.Time) < metricsRefreshInterval {
+		return ctrl.Result{RequeueAfter: metricsRefreshInterval}, nil
+	}
+
+	scraper := r.Scraper
+	if scraper == nil {
+		scraper = memcached.DefaultScraper
+	}
+	total := memcached.ExporterStats{}
+	for _, url := range memcached.ExporterURLs(instance) {
+		stats, err := scraper(ctx, url)
+		if err != nil {
+			Log.Info(fmt.Sprintf("Could not collect memcached stats: %s", err))
+			return ctrl.Result{RequeueAfter: metricsRefreshInterval}, nil
+		}
+		total.Add(stats)
+	}
+	r.reconcileAutoscaling(ctx, instance, total)
+	instance.Status.Metrics = &memcachedv1.MetricsStatus{
+		HitRatio:           total.HitRatio(),
+		Evictions:          total.Evictions,
+		CurrentConnections: total.CurrentConnections,
+		MaxConnections:     total.MaxConnections,
+		LastUpdated:        metav1.Now(),
+	}
+
+	return ctrl.Result{RequeueAfter: metricsRefreshInterval}, nil
+}
+
+// reconcileAutoscaling picks the replicas for the stats collected from the exporters. Scaling
+// up happens right away, scaling down only after the stabilization window. The eviction rate
+// is taken from the evictions counted since the previous stats in the status.
+func (r *Reconciler) reconcileAutoscaling(
+	ctx context.Context,
+	instance *memcachedv1.Memcached,
+	stats memcached.ExporterStats,
+) {
+	as := instance.Spec.Autoscaling
+	if as == nil {
+		return
+	}
+	Log := r.GetLogger(ctx)
+	current := memcached.Replicas(instance)
+
+	evictionRate := 0.0
+	if prev := instance.Status.Metrics; prev != nil && stats.Evictions > prev.Evictions {
+		if elapsed := time.Since(prev.LastUpdated.Time).Seconds(); elapsed > 0 {
+			evictionRate = float64(stats.Evictions-prev.Evictions) / elapsed / float64(current)
+		}
+	}
+
+	status := instance.Status.Autoscaling
+	if status == nil {
+		status = &memcachedv1.AutoscalingStatus{}
+	}
+	status.MemoryUtilization = stats.MemoryUtilization()
+	status.EvictionRate = strconv.FormatFloat(evictionRate, 'f', 1, 64)
+
+	desired := memcached.DesiredReplicas(as, current, status.MemoryUtilization, evictionRate)
+	if desired < current {
+		lastScale := instance.CreationTimestamp
+		if status.LastScaleTime != nil {
+			lastScale = *status.LastScaleTime
+		}
+		if time.Since(lastScale.Time) < time.Duration(as.ScaleDownStabilizationSeconds)*time.Second {
+			desired = current
+		}
+	}
+	if desired != current {
+		Log.Info(fmt.Sprintf("Scaling from %d to %d replicas", current, desired),
+			"memoryUtilization", status.MemoryUtilization, "evictionRate", status.EvictionRate)
+		now := metav1.Now()
+		status.LastScaleTime = &now
+	}
+	status.DesiredReplicas = desired
+	instance.Status.Autoscaling = status
+}
+
+// generateConfigMaps returns the config map resource for the servers of a memcached instance,
+// servers is either the instance itself or one of its pools
 func (r *Reconciler) generateConfigMaps(
 	ctx context.Context,
 	h *helper.Helper,
 	instance *memcachedv1.Memcached,
+	servers *memcachedv1.Memcached,
 	envVars *map[string]env.Setter,
 ) error {
 	Log := h.GetLogger()
 
 	customData := make(map[string]string)
 	var memcachedTLSListen, memcachedTLSOptions, memcachedPort string
-	if instance.Spec.TLS.Enabled() {
+	if servers.Spec.TLS.Enabled() {
 		memcachedTLSListen = "| sed 's/\\(.*\\)/\\1\\nnotls:\\1:11211/'"
 		memcachedTLSOptions = "-Z " +
-			"-o ssl_chain_cert=/etc/pki/tls/certs/memcached.crt " +
-			"-o ssl_key=/etc/pki/tls/private/memcached.key " +
+			"-o ssl_chain_cert=" + memcached.CertPath(servers) + " " +
+			"-o ssl_key=" + memcached.KeyPath(servers) + " " +
 			"-o ssl_ca_cert=/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"
 
-		switch instance.Spec.TLS.MTLS.SslVerifyMode {
+		switch servers.Spec.TLS.MTLS.SslVerifyMode {
 		case "Request":
 			memcachedTLSOptions = memcachedTLSOptions + " -o ssl_verify_mode=1"
 		case "Require":
@@ -506,28 +1178,36 @@ func (r *Reconciler) generateConfigMaps(
 		}
 
 		memcachedPort = fmt.Sprint(memcached.MemcachedTLSPort)
-		instance.Status.TLSSupport = true
+		servers.Status.TLSSupport = true
 	} else {
 		memcachedTLSListen = ""
 		memcachedTLSOptions = ""
 		memcachedPort = fmt.Sprint(memcached.MemcachedPort)
-		instance.Status.TLSSupport = false
+		servers.Status.TLSSupport = false
+	}
+	// SASL is only available with the binary protocol
+	var memcachedAuthOptions string
+	if servers.Spec.Auth.Enabled {
+		memcachedAuthOptions = "-S -B binary"
 	}
 	templateParameters := map[string]any{
-		"memcachedTLSListen":  memcachedTLSListen,
-		"memcachedTLSOptions": memcachedTLSOptions,
-		"memcachedPort":       memcachedPort,
-		"memcachedCacheSize":  instance.Spec.CacheSize,
-		"memcachedMaxConn":    instance.Spec.MaxConn,
+		"memcachedTLSListen":     memcachedTLSListen,
+		"memcachedTLSOptions":    memcachedTLSOptions,
+		"memcachedAuth":          servers.Spec.Auth.Enabled,
+		"memcachedAuthOptions":   memcachedAuthOptions,
+		"memcachedPort":          memcachedPort,
+		"memcachedCacheSize":     servers.Spec.CacheSize,
+		"memcachedMaxConn":       servers.Spec.MaxConn,
+		"memcachedServerOptions": memcached.ServerOptions(instance),
 	}
 
 	cms := []util.Template{
 		// ConfigMap
 		{
-			Name:          fmt.Sprintf("%s-config-data", instance.Name),
-			Namespace:     instance.Namespace,
+			Name:          fmt.Sprintf("%s-config-data", servers.Name),
+			Namespace:     servers.Namespace,
 			Type:          util.TemplateTypeConfig,
-			InstanceType:  instance.Kind,
+			InstanceType:  servers.Kind,
 			CustomData:    customData,
 			ConfigOptions: templateParameters,
 			Labels:        map[string]string{},
@@ -543,9 +1223,85 @@ func (r *Reconciler) generateConfigMaps(
 	return nil
 }
 
+// generatePassword generates a random password
+func generatePassword(length int) (string, error) {
+	bytes := make([]byte, length)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(bytes)[:length], nil
+}
+
+// ensureAuthSecret creates the secret holding the SASL credentials and returns its hash.
+// The password gets generated once and is kept when the username changes.
+func (r *Reconciler) ensureAuthSecret(
+	ctx context.Context,
+	instance *memcachedv1.Memcached,
+) (string, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      memcached.AuthSecretName(instance),
+			Namespace: instance.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, secret, func() error {
+		password := string(secret.Data[memcachedv1.AuthPasswordKey])
+		if password == "" {
+			var err error
+			password, err = generatePassword(32)
+			if err != nil {
+				return err
+			}
+		}
+		secret.Data = memcached.AuthSecretData(instance.Spec.Auth.Username, password)
+		return controllerutil.SetControllerReference(instance, secret, r.Scheme)
+	})
+	if err != nil {
+		return "", err
+	}
+	return oko_secret.Hash(secret)
+}
+
+// ensureClientConfigSecret creates the secret holding the oslo.cache section and the JSON
+// descriptor of the client configuration. It includes the SASL credentials, if any.
+func (r *Reconciler) ensureClientConfigSecret(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *memcachedv1.Memcached,
+) error {
+	var username, password string
+	if instance.Status.AuthSecret != "" {
+		authSecret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: instance.Status.AuthSecret, Namespace: instance.Namespace}, authSecret)
+		if err != nil {
+			return err
+		}
+		username = string(authSecret.Data[memcachedv1.AuthUsernameKey])
+		password = string(authSecret.Data[memcachedv1.AuthPasswordKey])
+	}
+
+	data, err := memcached.ClientConfigData(instance, username, password)
+	if err != nil {
+		return err
+	}
+	secrets := []util.Template{{
+		Name:         memcached.ClientConfigSecretName(instance),
+		Namespace:    instance.Namespace,
+		Type:         util.TemplateTypeNone,
+		InstanceType: instance.Kind,
+		CustomData:   data,
+		Labels:       map[string]string{},
+	}}
+	return oko_secret.EnsureSecrets(ctx, h, instance, secrets, nil)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.config = mgr.GetConfig()
+	if r.CertReloader == nil {
+		r.CertReloader = r.execCertReload
+	}
 
 	// Various CR fields need to be indexed to filter watch events
 	// for the secret changes we want to be notified of
@@ -589,7 +1345,9 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&memcachedv1.Memcached{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&corev1.Service{}).
+		Owns(&corev1.Secret{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
@@ -660,6 +1418,44 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, instance *memcachedv1.
 	return ctrl.Result{}, nil
 }
 
+// GetPerPodServerLists returns list of memcached server without/with inet prefix for the
+// endpoints of the per-pod services, which are either IPs or hostnames
+func (r *Reconciler) GetPerPodServerLists(
+	instance *memcachedv1.Memcached,
+	endpoints []string,
+	ipFamily corev1.IPFamily,
+) ([]string, []string) {
+	var serverList []string
+	var serverListWithInet []string
+
+	var port int32
+	if instance.Spec.TLS.Enabled() {
+		port = memcached.MemcachedTLSPort
+	} else {
+		port = memcached.MemcachedPort
+	}
+	for _, endpoint := range endpoints {
+		prefix := "inet"
+		server := endpoint
+		if ip := net.ParseIP(endpoint); ip != nil {
+			if ip.To4() == nil {
+				prefix = "inet6"
+				server = fmt.Sprintf("[%s]", endpoint)
+			}
+			serverList = append(serverList, fmt.Sprintf("%s:%d", server, port))
+		} else {
+			serverList = append(serverList, fmt.Sprintf("%s:%d", server, port))
+			if ipFamily == corev1.IPv6Protocol {
+				prefix = "inet6"
+				server = fmt.Sprintf("[%s]", endpoint)
+			}
+		}
+		serverListWithInet = append(serverListWithInet, fmt.Sprintf("%s:%s:%d", prefix, server, memcached.MemcachedPort))
+	}
+
+	return serverList, serverListWithInet
+}
+
 // GetServerLists returns list of memcached server without/with inet prefix
 func (r *Reconciler) GetServerLists(
 	instance *memcachedv1.Memcached,
@@ -678,7 +1474,7 @@ func (r *Reconciler) GetServerLists(
 	} else {
 		port = memcached.MemcachedPort
 	}
-	for i := int32(0); i < *(instance.Spec.Replicas); i++ {
+	for i := int32(0); i < instance.Status.ListedReplicas; i++ {
 		server := fmt.Sprintf("%s-%d.%s.%s.svc", instance.Name, i, instance.Name, instance.Namespace)
 		serverList = append(serverList, fmt.Sprintf("%s:%d", server, port))
 
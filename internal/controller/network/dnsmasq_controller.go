@@ -44,10 +44,12 @@ import (
 	networkv1 "github.com/openstack-k8s-operators/infra-operator/apis/network/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	dnsmasq "github.com/openstack-k8s-operators/infra-operator/internal/dnsmasq"
+	"github.com/openstack-k8s-operators/infra-operator/internal/pdb"
 	common "github.com/openstack-k8s-operators/lib-common/modules/common"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	configmap "github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
@@ -99,6 +101,9 @@ func (r *DNSMasqReconciler) GetLogger(ctx context.Context) logr.Logger {
 // +kubebuilder:rbac:groups="",resources=pods,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=topology.openstack.org,resources=topologies,verbs=get;list;watch;update
 
+// Required to limit voluntary disruptions of the pods
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 //
@@ -174,6 +179,8 @@ func (r *DNSMasqReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		condition.UnknownCondition(condition.ServiceAccountReadyCondition, condition.InitReason, condition.ServiceAccountReadyInitMessage),
 		condition.UnknownCondition(condition.RoleReadyCondition, condition.InitReason, condition.RoleReadyInitMessage),
 		condition.UnknownCondition(condition.RoleBindingReadyCondition, condition.InitReason, condition.RoleBindingReadyInitMessage),
+		// PodDisruptionBudget
+		condition.UnknownCondition(condition.PDBReadyCondition, condition.InitReason, condition.PDBReadyInitMessage),
 	)
 
 	instance.Status.Conditions.Init(&cl)
@@ -301,6 +308,7 @@ func (r *DNSMasqReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&networkv1.DNSMasq{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
@@ -559,6 +567,22 @@ func (r *DNSMasqReconciler) reconcileNormal(ctx context.Context, instance *netwo
 		instance.Status.LastAppliedTopology = nil
 	}
 
+	// PodDisruptionBudget
+	pdbRes, err := pdb.EnsurePodDisruptionBudget(
+		ctx, helper, &instance.Spec.PodDisruptionBudget, *instance.Spec.Replicas, fmt.Sprintf("%s-%s", dnsmasq.ServiceName, instance.Name), instance.Namespace, serviceLabels)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.PDBReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.PDBReadyErrorMessage,
+			err.Error()))
+		return pdbRes, err
+	} else if (pdbRes != ctrl.Result{}) {
+		return pdbRes, nil
+	}
+	instance.Status.Conditions.MarkTrue(condition.PDBReadyCondition, condition.PDBReadyMessage)
+
 	// Define a new Deployment object
 	deplDef := dnsmasq.Deployment(instance, instance.Status.Hash[common.InputHashName], serviceLabels, serviceAnnotations, configMaps, topology)
 	depl := deployment.NewDeployment(
@@ -162,7 +162,7 @@ func (r *ServiceReconciler) createOrPatchDNSData(
 
 	svcDNSData := &networkv1.DNSData{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      dnsmasq.GetName() + "-svc",
+			Name:      dnsmasq.ServiceDNSDataName(),
 			Namespace: dnsmasq.GetNamespace(),
 		},
 	}
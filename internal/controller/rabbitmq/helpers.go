@@ -19,13 +19,58 @@ package rabbitmq
 import (
 	"context"
 	"fmt"
+	"net/http"
 
+	rabbitmqv1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
+	rabbitmqapi "github.com/openstack-k8s-operators/infra-operator/pkg/rabbitmq/api"
 	helper "github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	oko_secret "github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Event reasons for RabbitMQ management API operations
+const (
+	// eventReasonAPIError - the management API rejected a request
+	eventReasonAPIError = "RabbitMQAPIError"
+	// eventReasonAPIRequestFailed - a management API request failed before getting a response
+	eventReasonAPIRequestFailed = "RabbitMQAPIRequestFailed"
+	eventReasonUserCreated      = "UserCreated"
+	eventReasonUserDeleted      = "UserDeleted"
+	eventReasonVhostCreated     = "VhostCreated"
+	eventReasonVhostDeleted     = "VhostDeleted"
+	eventReasonPolicyApplied    = "PolicyApplied"
+	eventReasonPolicyDeleted    = "PolicyDeleted"
+)
+
+// recordAPIError emits a warning Event on obj for a failed management API request and
+// returns the message to report in the conditions of obj, with passwords redacted.
+func recordAPIError(recorder record.EventRecorder, obj runtime.Object, err error) string {
+	msg := rabbitmqapi.Redact(err.Error())
+	if recorder == nil {
+		return msg
+	}
+	reason := eventReasonAPIRequestFailed
+	if _, ok := rabbitmqapi.AsAPIError(err); ok {
+		reason = eventReasonAPIError
+	}
+	recorder.Event(obj, corev1.EventTypeWarning, reason, msg)
+	return msg
+}
+
+// recordAPIEvent emits a normal Event on obj for a change applied through the management API
+func recordAPIEvent(recorder record.EventRecorder, obj runtime.Object, reason, messageFmt string, args ...interface{}) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(obj, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
 // getManagementURL constructs the RabbitMQ management API URL from cluster spec and secret data
 func getManagementURL(rabbit *rabbitmqclusterv2.RabbitmqCluster, rabbitSecret *corev1.Secret) string {
 	tlsEnabled := rabbit.Spec.TLS.SecretName != ""
@@ -56,3 +101,62 @@ func getTLSCACert(ctx context.Context, h *helper.Helper, rabbit *rabbitmqcluster
 
 	return caCert, nil
 }
+
+// isNamespaceAllowed checks if resources in requesterNamespace may bind to the RabbitMq clusterName
+// in clusterNamespace. Binding within the same namespace is always allowed, for cross-namespace
+// binding the RabbitMq CR must exist and list the requester namespace in AllowedNamespaces.
+func isNamespaceAllowed(ctx context.Context, h *helper.Helper, clusterName, clusterNamespace, requesterNamespace string) (bool, error) {
+	if clusterNamespace == requesterNamespace {
+		return true, nil
+	}
+
+	rabbitmq := &rabbitmqv1.RabbitMq{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: clusterName, Namespace: clusterNamespace}, rabbitmq)
+	if err != nil {
+		return false, err
+	}
+
+	return rabbitmq.IsNamespaceAllowed(requesterNamespace), nil
+}
+
+// getActiveRabbitmqCluster returns the RabbitmqCluster clients of the RabbitMq clusterName get pointed
+// to. It is the green cluster while a BlueGreen upgrade runs on it, and the RabbitmqCluster of the
+// same name otherwise or when there is no RabbitMq.
+func getActiveRabbitmqCluster(ctx context.Context, c client.Client, clusterName, namespace string) (*rabbitmqclusterv2.RabbitmqCluster, error) {
+	rabbitmq := &rabbitmqv1.RabbitMq{}
+	err := c.Get(ctx, types.NamespacedName{Name: clusterName, Namespace: namespace}, rabbitmq)
+	if err == nil {
+		clusterName = rabbitmq.GetActiveClusterName()
+	} else if !k8s_errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	rabbit := &rabbitmqclusterv2.RabbitmqCluster{}
+	err = c.Get(ctx, types.NamespacedName{Name: clusterName, Namespace: namespace}, rabbit)
+	return rabbit, err
+}
+
+// newClusterAPIClient returns a management API client for the RabbitmqCluster using its
+// default user, together with the default user secret. A non-nil transport overrides the
+// HTTP transport of the client.
+func newClusterAPIClient(ctx context.Context, h *helper.Helper, rabbit *rabbitmqclusterv2.RabbitmqCluster, transport http.RoundTripper) (*rabbitmqapi.Client, *corev1.Secret, error) {
+	if rabbit.Status.DefaultUser == nil || rabbit.Status.DefaultUser.SecretReference == nil {
+		return nil, nil, fmt.Errorf("default user of RabbitmqCluster %s not available yet", rabbit.Name)
+	}
+	rabbitSecret, _, err := oko_secret.GetSecret(ctx, h, rabbit.Status.DefaultUser.SecretReference.Name, rabbit.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	caCert, err := getTLSCACert(ctx, h, rabbit, rabbit.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	apiClient := rabbitmqapi.NewClientWithTransport(
+		getManagementURL(rabbit, rabbitSecret),
+		string(rabbitSecret.Data["username"]),
+		string(rabbitSecret.Data["password"]),
+		rabbit.Spec.TLS.SecretName != "",
+		caCert,
+		transport)
+	return apiClient, rabbitSecret, nil
+}
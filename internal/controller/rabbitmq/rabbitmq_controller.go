@@ -21,12 +21,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	rabbitmqv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -44,16 +46,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/go-logr/logr"
+	networkv1 "github.com/openstack-k8s-operators/infra-operator/apis/network/v1beta1"
 	rabbitmqv1beta1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
+	"github.com/openstack-k8s-operators/infra-operator/internal/pdb"
 	"github.com/openstack-k8s-operators/infra-operator/internal/rabbitmq"
 	"github.com/openstack-k8s-operators/infra-operator/internal/rabbitmq/impl"
+	rabbitmqapi "github.com/openstack-k8s-operators/infra-operator/pkg/rabbitmq/api"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/labels"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/ocp"
-	"github.com/openstack-k8s-operators/lib-common/modules/common/pdb"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
@@ -86,6 +90,9 @@ type Reconciler struct {
 	Kclient kubernetes.Interface
 	config  *rest.Config
 	Scheme  *runtime.Scheme
+	// Transport - overrides the HTTP transport of the management API clients,
+	// defaults to the transport of rabbitmqapi.NewClient
+	Transport http.RoundTripper
 }
 
 // +kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqs,verbs=get;list;watch;create;update;patch;delete
@@ -107,6 +114,11 @@ type Reconciler struct {
 // Required to create per-pod LoadBalancer services
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 
+// Required to request the per-pod service certificate and to resolve the per-pod service hostnames
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=network.openstack.org,resources=dnsmasqs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=network.openstack.org,resources=dnsdata,verbs=get;list;watch
+
 // Reconcile - RabbitMq
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, _err error) {
 	Log := r.GetLogger(ctx)
@@ -184,6 +196,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		condition.UnknownCondition(condition.PDBReadyCondition, condition.InitReason, condition.PDBReadyInitMessage),
 		// per-pod services ready
 		condition.UnknownCondition(condition.CreateServiceReadyCondition, condition.InitReason, condition.CreateServiceReadyInitMessage),
+		// per-pod service hostnames resolve
+		condition.UnknownCondition(rabbitmqv1beta1.RabbitMqServiceDNSReadyCondition, condition.InitReason, rabbitmqv1beta1.RabbitMqServiceDNSReadyInitMessage),
+		// container image upgrade
+		condition.UnknownCondition(rabbitmqv1beta1.RabbitMqUpgradeReadyCondition, condition.InitReason, rabbitmqv1beta1.RabbitMqUpgradeReadyInitMessage),
 	)
 
 	instance.Status.Conditions.Init(&cl)
@@ -200,6 +216,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return r.reconcileDelete(ctx, instance, helper)
 	}
 
+	//
+	// Request the certificate of the per-pod services, it has to exist before
+	// the TLS input validation in case spec.tls.secretName refers to it
+	//
+	if err := r.reconcilePerPodCertificate(ctx, instance, helper); err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.TLSInputReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.TLSInputErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
 	//
 	// TLS input validation
 	//
@@ -298,6 +328,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		},
 	}
 
+	// The green cluster of a BlueGreen upgrade mounts its own copy
+	if instance.Spec.UpgradeStrategy == rabbitmqv1beta1.UpgradeStrategyBlueGreen {
+		green := cms[0]
+		green.Name = fmt.Sprintf("%s%s-config-data", instance.Name, rabbitmqv1beta1.GreenClusterSuffix)
+		cms = append(cms, green)
+	}
+
 	err = configmap.EnsureConfigMaps(ctx, helper, instance, cms, nil)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(
@@ -380,6 +417,27 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return ctrl.Result{}, fmt.Errorf("error configuring RabbitmqCluster: %w", err)
 	}
 
+	//
+	// Handle changes of the container image, the upgrade pins the image
+	// of the RabbitmqCluster to the one its current phase needs
+	//
+	newRabbitmqCluster := func(name string) (*rabbitmqv2.RabbitmqCluster, error) {
+		cluster := &rabbitmqv2.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: instance.Namespace,
+			},
+		}
+		if err := instance.Spec.MarshalInto(&cluster.Spec); err != nil {
+			return nil, err
+		}
+		return cluster, rabbitmq.ConfigureCluster(cluster, IPv6Enabled, fipsEnabled, topology, instance.Spec.NodeSelector, instance.Spec.Override)
+	}
+	ctrlResult, err := r.reconcileUpgrade(ctx, instance, helper, rabbitmqCluster, newRabbitmqCluster)
+	if err != nil || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+
 	rabbitmqImplCluster := impl.NewRabbitMqCluster(rabbitmqCluster, 5)
 	rmqres, rmqerr := rabbitmqImplCluster.CreateOrPatch(ctx, helper)
 	if rmqerr != nil {
@@ -407,26 +465,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 			labels.K8sAppPartOf:    "rabbitmq",
 		}
 
-		if instance.Spec.Replicas != nil && *instance.Spec.Replicas > 1 {
-			// Apply PDB for multi-replica deployments
-			pdbSpec := pdb.MaxUnavailablePodDisruptionBudget(
-				instance.Name,
-				instance.Namespace,
-				intstr.FromInt(1),
-				labelMap,
-			)
-			pdbInstance := pdb.NewPDB(pdbSpec, 5*time.Second)
-
-			_, err := pdbInstance.CreateOrPatch(ctx, helper)
-			if err != nil {
-				Log.Error(err, "Could not apply PDB")
-				instance.Status.Conditions.Set(condition.FalseCondition(
-					condition.PDBReadyCondition,
-					condition.ErrorReason,
-					condition.SeverityWarning,
-					condition.PDBReadyErrorMessage, err.Error()))
-				return ctrl.Result{}, err
-			}
+		// Apply PDB for multi-replica deployments, the RabbitmqCluster does
+		// not manage one for its pods
+		replicas := int32(1)
+		if instance.Spec.Replicas != nil {
+			replicas = *instance.Spec.Replicas
+		}
+		// RabbitMQ keeps its maxUnavailable of 1 unless another one is configured,
+		// evicting more pods at once risks the quorum queues
+		pdbSection := instance.Spec.PodDisruptionBudget.DeepCopy()
+		if pdbSection.MaxUnavailable == nil {
+			pdbSection.MaxUnavailable = ptr.To(intstr.FromInt32(1))
+		}
+		_, err := pdb.EnsurePodDisruptionBudget(
+			ctx, helper, pdbSection, replicas, instance.Name, instance.Namespace, labelMap)
+		if err != nil {
+			Log.Error(err, "Could not apply PDB")
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.PDBReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.PDBReadyErrorMessage, err.Error()))
+			return ctrl.Result{}, err
 		}
 		instance.Status.Conditions.MarkTrue(condition.PDBReadyCondition, condition.PDBReadyMessage)
 
@@ -509,12 +569,44 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 				instance.Status.QueueType = ""
 			}
 		}
+
+		// Record the running version, this also completes an InPlace upgrade
+		if err := r.reconcileVersion(ctx, instance, helper, &rabbitmqClusterInstance); err != nil {
+			if instance.IsUpgradeInProgress() {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					rabbitmqv1beta1.RabbitMqUpgradeReadyCondition,
+					condition.ErrorReason,
+					condition.SeverityWarning,
+					rabbitmqv1beta1.RabbitMqUpgradeReadyErrorMessage,
+					recordAPIError(nil, instance, err)))
+				return ctrl.Result{}, err
+			}
+			Log.Info(fmt.Sprintf("Could not read the RabbitMQ version: %s", rabbitmqapi.Redact(err.Error())))
+		}
+
+		// Wait for the per-pod service hostnames to resolve through the DNSMasq
+		// addresses, TransportURLs only switch to them afterwards
+		ctrlResult, err = r.reconcileServiceDNS(ctx, instance)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				rabbitmqv1beta1.RabbitMqServiceDNSReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				rabbitmqv1beta1.RabbitMqServiceDNSReadyErrorMessage, err.Error()))
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
 	}
 
 	if instance.Status.Conditions.AllSubConditionIsTrue() {
 		instance.Status.Conditions.MarkTrue(
 			condition.ReadyCondition, condition.ReadyMessage)
 	}
+	// The migration phases of an upgrade wait for queues to drain
+	if instance.IsUpgradeInProgress() {
+		return ctrl.Result{RequeueAfter: upgradeRequeueInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -538,7 +630,7 @@ func (r *Reconciler) reconcilePerPodServices(ctx context.Context, instance *rabb
 	var serviceHostnames []string
 	var requeueNeeded bool
 	for i := 0; i < replicas; i++ {
-		podName := fmt.Sprintf("%s-server-%d", instance.Name, i)
+		podName := rabbitmq.PerPodServiceName(instance.Name, i)
 		svcName := podName
 
 		svc, err := service.NewService(
@@ -568,7 +660,7 @@ func (r *Reconciler) reconcilePerPodServices(ctx context.Context, instance *rabb
 
 		if svc.GetServiceType() == corev1.ServiceTypeLoadBalancer {
 			svc.AddAnnotation(map[string]string{
-				service.AnnotationHostnameKey: svc.GetServiceHostname(),
+				networkv1.AnnotationHostnameKey: svc.GetServiceHostname(),
 			})
 		}
 
@@ -606,6 +698,144 @@ func (r *Reconciler) reconcilePerPodServices(ctx context.Context, instance *rabb
 	return ctrl.Result{}, nil
 }
 
+// reconcilePerPodCertificate requests a certificate which has the per-pod service
+// hostnames as SANs, or deletes it when no longer configured
+func (r *Reconciler) reconcilePerPodCertificate(ctx context.Context, instance *rabbitmqv1beta1.RabbitMq, helper *helper.Helper) error {
+	Log := r.GetLogger(ctx)
+
+	cert := rabbitmq.NewCertificate(instance.Name+"-svc", instance.Namespace)
+
+	if instance.Spec.PodOverride == nil || instance.Spec.PodOverride.Certificate == nil ||
+		instance.Spec.Replicas == nil || *instance.Spec.Replicas == 0 {
+		if instance.Status.CertificateSecretName == "" {
+			return nil
+		}
+		err := r.Delete(ctx, cert)
+		if err != nil && !k8s_errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+		Log.Info(fmt.Sprintf("Certificate %s deleted", cert.GetName()))
+		instance.Status.CertificateSecretName = ""
+		return nil
+	}
+
+	spec := instance.Spec.PodOverride.Certificate
+	secretName := spec.SecretName
+	if secretName == "" {
+		secretName = instance.Name + rabbitmqv1beta1.CertificateSecretNameSuffix
+	}
+	issuerKind := spec.IssuerKind
+	if issuerKind == "" {
+		issuerKind = "Issuer"
+	}
+	dnsNames := rabbitmq.CertificateDNSNames(instance.Name, instance.Namespace, int(*instance.Spec.Replicas))
+
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, cert, func() error {
+		if err := rabbitmq.SetCertificateSpec(cert, secretName, dnsNames, spec.IssuerName, issuerKind); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(instance, cert, helper.GetScheme())
+	})
+	if err != nil {
+		return fmt.Errorf("error create/updating certificate %s: %w", cert.GetName(), err)
+	}
+	if op != controllerutil.OperationResultNone {
+		Log.Info(fmt.Sprintf("Certificate %s %s", cert.GetName(), op))
+	}
+
+	instance.Status.CertificateSecretName = secretName
+	return nil
+}
+
+// reconcileServiceDNS checks that the hostnames of the per-pod LoadBalancer services are
+// registered with their LoadBalancer addresses in the service DNSData of a ready DNSMasq
+// in the namespace
+func (r *Reconciler) reconcileServiceDNS(ctx context.Context, instance *rabbitmqv1beta1.RabbitMq) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	if len(instance.Status.ServiceHostnames) == 0 {
+		instance.Status.Conditions.MarkTrue(rabbitmqv1beta1.RabbitMqServiceDNSReadyCondition, rabbitmqv1beta1.RabbitMqServiceDNSReadyMessage)
+		return ctrl.Result{}, nil
+	}
+
+	// only services annotated with the hostname get registered with dnsmasq
+	hostnames := []string{}
+	addresses := map[string][]string{}
+	for i := range instance.Status.ServiceHostnames {
+		svc := &corev1.Service{}
+		err := r.Get(ctx, types.NamespacedName{Name: rabbitmq.PerPodServiceName(instance.Name, i), Namespace: instance.Namespace}, svc)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if hostname, ok := svc.Annotations[networkv1.AnnotationHostnameKey]; ok && svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			hostnames = append(hostnames, hostname)
+			for _, ingr := range svc.Status.LoadBalancer.Ingress {
+				addresses[hostname] = append(addresses[hostname], ingr.IP)
+			}
+		}
+	}
+
+	dnsmasqs := &networkv1.DNSMasqList{}
+	if err := r.List(ctx, dnsmasqs, client.InNamespace(instance.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// nothing the hostnames get registered with, there is nothing to wait for
+	if len(hostnames) == 0 || len(dnsmasqs.Items) == 0 {
+		instance.Status.Conditions.MarkTrue(rabbitmqv1beta1.RabbitMqServiceDNSReadyCondition, rabbitmqv1beta1.RabbitMqServiceDNSReadyMessage)
+		return ctrl.Result{}, nil
+	}
+
+	dnsData := []networkv1.DNSData{}
+	readyDNSMasqs := 0
+	for _, dnsmasq := range dnsmasqs.Items {
+		if !dnsmasq.IsReady() {
+			continue
+		}
+		readyDNSMasqs++
+
+		data := &networkv1.DNSData{}
+		err := r.Get(ctx, types.NamespacedName{Name: dnsmasq.ServiceDNSDataName(), Namespace: instance.Namespace}, data)
+		if err != nil {
+			if k8s_errors.IsNotFound(err) {
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+		if data.IsReady() {
+			dnsData = append(dnsData, *data)
+		}
+	}
+
+	// DNSMasqs getting ready trigger a reconcile, the rate limited requeue only
+	// covers missed events
+	if readyDNSMasqs == 0 {
+		Log.Info(fmt.Sprintf("None of the DNSMasqs in namespace %s is ready", instance.Namespace))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			rabbitmqv1beta1.RabbitMqServiceDNSReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			rabbitmqv1beta1.RabbitMqServiceDNSReadyNoDNSMasqMessage,
+			instance.Namespace))
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	unregistered := rabbitmq.UnregisteredHostnames(hostnames, addresses, dnsData)
+	if len(unregistered) > 0 {
+		Log.Info(fmt.Sprintf("Per-pod service hostnames %v are not registered with a ready DNSMasq yet", unregistered))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			rabbitmqv1beta1.RabbitMqServiceDNSReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			rabbitmqv1beta1.RabbitMqServiceDNSReadyWaitingMessage,
+			strings.Join(unregistered, ",")))
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	instance.Status.Conditions.MarkTrue(rabbitmqv1beta1.RabbitMqServiceDNSReadyCondition, rabbitmqv1beta1.RabbitMqServiceDNSReadyMessage)
+	return ctrl.Result{}, nil
+}
+
 func (r *Reconciler) deletePerPodServices(ctx context.Context, instance *rabbitmqv1beta1.RabbitMq) error {
 	// List all services owned by this RabbitMq instance
 	serviceList := &corev1.ServiceList{}
@@ -800,9 +1030,43 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&topologyv1.Topology{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSrc),
 			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&networkv1.DNSMasq{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsWithServiceHostnames),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&networkv1.DNSData{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsWithServiceHostnames),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Complete(r)
 }
 
+// findObjectsWithServiceHostnames - returns a reconcile request for every RabbitMq in the
+// namespace of the DNSMasq or DNSData which waits for its per-pod service hostnames
+func (r *Reconciler) findObjectsWithServiceHostnames(ctx context.Context, src client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	crList := &rabbitmqv1beta1.RabbitMqList{}
+	if err := r.List(ctx, crList, client.InNamespace(src.GetNamespace())); err != nil {
+		r.GetLogger(ctx).Error(err, fmt.Sprintf("listing %s in namespace %s", crList.GroupVersionKind().Kind, src.GetNamespace()))
+		return requests
+	}
+
+	for _, item := range crList.Items {
+		if len(item.Status.ServiceHostnames) == 0 {
+			continue
+		}
+		requests = append(requests,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.GetName(),
+					Namespace: item.GetNamespace(),
+				},
+			},
+		)
+	}
+
+	return requests
+}
+
 // findObjectsForSrc - returns a reconcile request if the object is referenced by a Redis CR
 func (r *Reconciler) findObjectsForSrc(ctx context.Context, src client.Object) []reconcile.Request {
 	requests := []reconcile.Request{}
@@ -0,0 +1,439 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"time"
+
+	rabbitmqv1beta1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
+	"github.com/openstack-k8s-operators/infra-operator/internal/rabbitmq/impl"
+	rabbitmqapi "github.com/openstack-k8s-operators/infra-operator/pkg/rabbitmq/api"
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	rabbitmqv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// shovelPlugin - plugin the destination cluster of a BlueGreen migration needs to run the shovels
+	shovelPlugin rabbitmqv2.Plugin = "rabbitmq_shovel"
+
+	// upgradeShovelPrefix - prefix of the shovels created during a BlueGreen upgrade
+	upgradeShovelPrefix = "upgrade-"
+
+	// upgradeRequeueInterval - how often to check on a running upgrade
+	upgradeRequeueInterval = 10 * time.Second
+)
+
+// isRabbitmqClusterReady returns true once the cluster operator has observed the
+// current spec of the RabbitmqCluster and all its replicas are ready
+func isRabbitmqClusterReady(cluster *rabbitmqv2.RabbitmqCluster) bool {
+	if cluster.Status.ObservedGeneration != cluster.Generation {
+		return false
+	}
+	available, replicasReady := false, false
+	for _, c := range cluster.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch string(c.Type) {
+		case "ClusterAvailable":
+			available = true
+		case "AllReplicasReady":
+			replicasReady = true
+		}
+	}
+	return available && replicasReady
+}
+
+// addShovelPlugin enables the shovel plugin on the cluster
+func addShovelPlugin(cluster *rabbitmqv2.RabbitmqCluster) {
+	if !slices.Contains(cluster.Spec.Rabbitmq.AdditionalPlugins, shovelPlugin) {
+		cluster.Spec.Rabbitmq.AdditionalPlugins = append(cluster.Spec.Rabbitmq.AdditionalPlugins, shovelPlugin)
+	}
+}
+
+// reconcileUpgrade starts an upgrade when the container image of the running cluster differs from
+// spec.containerImage and drives it through its phases. It sets the image of desired to the one
+// the current phase needs. A non empty result means the reconcile has to stop and requeue.
+func (r *Reconciler) reconcileUpgrade(
+	ctx context.Context,
+	instance *rabbitmqv1beta1.RabbitMq,
+	h *helper.Helper,
+	desired *rabbitmqv2.RabbitmqCluster,
+	newCluster func(name string) (*rabbitmqv2.RabbitmqCluster, error),
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	current := &rabbitmqv2.RabbitmqCluster{}
+	err := r.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, current)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	exists := err == nil
+
+	if !instance.IsUpgradeInProgress() {
+		if !exists || current.Spec.Image == "" || current.Spec.Image == desired.Spec.Image {
+			instance.Status.Conditions.MarkTrue(rabbitmqv1beta1.RabbitMqUpgradeReadyCondition, rabbitmqv1beta1.RabbitMqUpgradeReadyMessage)
+			return ctrl.Result{}, nil
+		}
+
+		// A BlueGreen upgrade needs a major or minor version change, the webhook refuses
+		// anything else. Keep the running image instead of rolling the new one in place.
+		if instance.Spec.UpgradeStrategy == rabbitmqv1beta1.UpgradeStrategyBlueGreen &&
+			!rabbitmqv1beta1.IsMinorVersionChange(instance.Status.Version, instance.Spec.TargetVersion) {
+			Log.Info(fmt.Sprintf("Refusing %s upgrade of RabbitMq %s from version %q to %q", instance.Spec.UpgradeStrategy, instance.Name, instance.Status.Version, instance.Spec.TargetVersion))
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				rabbitmqv1beta1.RabbitMqUpgradeReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				rabbitmqv1beta1.RabbitMqUpgradeStrategyErrorMessage,
+				instance.Spec.UpgradeStrategy,
+				instance.Status.Version,
+				instance.Spec.TargetVersion))
+			desired.Spec.Image = current.Spec.Image
+			return ctrl.Result{}, nil
+		}
+
+		upgrade := &rabbitmqv1beta1.RabbitMqUpgradeStatus{
+			Strategy:    rabbitmqv1beta1.UpgradeStrategyInPlace,
+			Phase:       rabbitmqv1beta1.UpgradePhaseEnablingFeatureFlags,
+			FromVersion: instance.Status.Version,
+			ToVersion:   instance.Spec.TargetVersion,
+			FromImage:   current.Spec.Image,
+			ToImage:     desired.Spec.Image,
+		}
+		if instance.Spec.UpgradeStrategy == rabbitmqv1beta1.UpgradeStrategyBlueGreen {
+			upgrade.Strategy = rabbitmqv1beta1.UpgradeStrategyBlueGreen
+			upgrade.Phase = rabbitmqv1beta1.UpgradePhaseDeployingGreen
+		}
+		instance.Status.Upgrade = upgrade
+		Log.Info(fmt.Sprintf("Starting %s upgrade of RabbitMq %s from %s to %s", upgrade.Strategy, instance.Name, upgrade.FromImage, upgrade.ToImage))
+	}
+	upgrade := instance.Status.Upgrade
+
+	// The upgrade of a cluster which is gone is over, it gets created again with the new image
+	if !exists && upgrade.Phase != rabbitmqv1beta1.UpgradePhaseRecreatingBlue {
+		upgrade.Phase = rabbitmqv1beta1.UpgradePhaseCompleted
+		upgrade.ActiveClusterName = ""
+	}
+
+	var ctrlResult ctrl.Result
+	switch upgrade.Strategy {
+	case rabbitmqv1beta1.UpgradeStrategyBlueGreen:
+		ctrlResult, err = r.reconcileBlueGreenUpgrade(ctx, instance, h, current, exists, desired, newCluster)
+	default:
+		err = r.reconcileInPlaceUpgrade(ctx, instance, h, current, desired)
+	}
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			rabbitmqv1beta1.RabbitMqUpgradeReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			rabbitmqv1beta1.RabbitMqUpgradeReadyErrorMessage,
+			recordAPIError(nil, instance, err)))
+		return ctrl.Result{}, err
+	}
+
+	if instance.IsUpgradeInProgress() {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			rabbitmqv1beta1.RabbitMqUpgradeReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			rabbitmqv1beta1.RabbitMqUpgradeInProgressMessage,
+			upgrade.FromImage, upgrade.ToImage, upgrade.Phase))
+	} else {
+		instance.Status.Conditions.MarkTrue(rabbitmqv1beta1.RabbitMqUpgradeReadyCondition, rabbitmqv1beta1.RabbitMqUpgradeReadyMessage)
+	}
+	return ctrlResult, nil
+}
+
+// reconcileInPlaceUpgrade enables the stable feature flags on the running cluster before the new
+// image rolls over it, RabbitMQ refuses to start a new version while flags of the previous one
+// are disabled. reconcileVersion completes the upgrade once the cluster is ready again.
+func (r *Reconciler) reconcileInPlaceUpgrade(
+	ctx context.Context,
+	instance *rabbitmqv1beta1.RabbitMq,
+	h *helper.Helper,
+	current *rabbitmqv2.RabbitmqCluster,
+	desired *rabbitmqv2.RabbitmqCluster,
+) error {
+	Log := r.GetLogger(ctx)
+	upgrade := instance.Status.Upgrade
+
+	if upgrade.Phase != rabbitmqv1beta1.UpgradePhaseEnablingFeatureFlags {
+		return nil
+	}
+
+	desired.Spec.Image = upgrade.FromImage
+	if !isRabbitmqClusterReady(current) {
+		Log.Info(fmt.Sprintf("Waiting for RabbitmqCluster %s to be ready to enable feature flags", current.Name))
+		return nil
+	}
+
+	apiClient, _, err := newClusterAPIClient(ctx, h, current, r.Transport)
+	if err != nil {
+		return err
+	}
+	enabled, err := enableStableFeatureFlags(apiClient)
+	if err != nil {
+		return err
+	}
+	if len(enabled) > 0 {
+		Log.Info(fmt.Sprintf("Enabled feature flags %v on RabbitmqCluster %s", enabled, current.Name))
+	}
+
+	upgrade.Phase = rabbitmqv1beta1.UpgradePhaseRollingImage
+	desired.Spec.Image = upgrade.ToImage
+	return nil
+}
+
+// enableStableFeatureFlags enables the stable and required feature flags which are still disabled
+// and returns their names
+func enableStableFeatureFlags(apiClient *rabbitmqapi.Client) ([]string, error) {
+	flags, err := apiClient.ListFeatureFlags()
+	if err != nil {
+		return nil, err
+	}
+	enabled := []string{}
+	for _, flag := range flags {
+		if flag.State == rabbitmqapi.FeatureFlagStateEnabled ||
+			(flag.Stability != rabbitmqapi.FeatureFlagStable && flag.Stability != rabbitmqapi.FeatureFlagRequired) {
+			continue
+		}
+		if err := apiClient.EnableFeatureFlag(flag.Name); err != nil {
+			return enabled, err
+		}
+		enabled = append(enabled, flag.Name)
+	}
+	return enabled, nil
+}
+
+// reconcileBlueGreenUpgrade moves the clients, definitions and messages to a green cluster running
+// the new image, recreates the blue cluster with the new image and moves everything back to it.
+func (r *Reconciler) reconcileBlueGreenUpgrade(
+	ctx context.Context,
+	instance *rabbitmqv1beta1.RabbitMq,
+	h *helper.Helper,
+	current *rabbitmqv2.RabbitmqCluster,
+	exists bool,
+	desired *rabbitmqv2.RabbitmqCluster,
+	newCluster func(name string) (*rabbitmqv2.RabbitmqCluster, error),
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+	upgrade := instance.Status.Upgrade
+	greenName := instance.Name + rabbitmqv1beta1.GreenClusterSuffix
+
+	switch upgrade.Phase {
+	case rabbitmqv1beta1.UpgradePhaseDeployingGreen:
+		desired.Spec.Image = upgrade.FromImage
+
+		green, err := newCluster(greenName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		green.Spec.Image = upgrade.ToImage
+		addShovelPlugin(green)
+
+		greenImpl := impl.NewRabbitMqCluster(green, 5)
+		ctrlResult, err := greenImpl.CreateOrPatch(ctx, h)
+		if err != nil || (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, err
+		}
+		greenCluster := greenImpl.GetRabbitMqCluster()
+		if !isRabbitmqClusterReady(&greenCluster) {
+			Log.Info(fmt.Sprintf("Waiting for green RabbitmqCluster %s to be ready", greenName))
+			return ctrl.Result{}, nil
+		}
+		upgrade.Phase = rabbitmqv1beta1.UpgradePhaseMigratingToGreen
+		upgrade.ActiveClusterName = greenName
+
+	case rabbitmqv1beta1.UpgradePhaseMigratingToGreen:
+		desired.Spec.Image = upgrade.FromImage
+
+		green := &rabbitmqv2.RabbitmqCluster{}
+		err := r.Get(ctx, types.NamespacedName{Name: greenName, Namespace: instance.Namespace}, green)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		drained, err := r.migrateCluster(ctx, h, current, green)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if drained {
+			Log.Info(fmt.Sprintf("RabbitmqCluster %s drained, recreating it with image %s", current.Name, upgrade.ToImage))
+			upgrade.Phase = rabbitmqv1beta1.UpgradePhaseRecreatingBlue
+		}
+
+	case rabbitmqv1beta1.UpgradePhaseRecreatingBlue:
+		desired.Spec.Image = upgrade.ToImage
+		addShovelPlugin(desired)
+
+		if !exists {
+			return ctrl.Result{}, nil
+		}
+		// The data of the blue cluster can not be read by the new version,
+		// it has to be deleted before it gets created again
+		if current.Spec.Image != upgrade.ToImage {
+			if current.DeletionTimestamp.IsZero() {
+				if err := r.Delete(ctx, current); err != nil && !k8s_errors.IsNotFound(err) {
+					return ctrl.Result{}, err
+				}
+				Log.Info(fmt.Sprintf("Deleted RabbitmqCluster %s", current.Name))
+			}
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		if isRabbitmqClusterReady(current) {
+			upgrade.Phase = rabbitmqv1beta1.UpgradePhaseMigratingToBlue
+			upgrade.ActiveClusterName = ""
+		}
+
+	case rabbitmqv1beta1.UpgradePhaseMigratingToBlue:
+		desired.Spec.Image = upgrade.ToImage
+		addShovelPlugin(desired)
+
+		green := &rabbitmqv2.RabbitmqCluster{}
+		err := r.Get(ctx, types.NamespacedName{Name: greenName, Namespace: instance.Namespace}, green)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		if err == nil {
+			drained, err := r.migrateCluster(ctx, h, green, current)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !drained {
+				return ctrl.Result{}, nil
+			}
+			if err := r.Delete(ctx, green); err != nil && !k8s_errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			Log.Info(fmt.Sprintf("Deleted green RabbitmqCluster %s", greenName))
+		}
+
+		apiClient, _, err := newClusterAPIClient(ctx, h, current, r.Transport)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		overview, err := apiClient.GetOverview()
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		instance.Status.Version = overview.RabbitMQVersion
+		upgrade.ToVersion = overview.RabbitMQVersion
+		upgrade.Phase = rabbitmqv1beta1.UpgradePhaseCompleted
+		Log.Info(fmt.Sprintf("Upgrade of RabbitMq %s to %s completed", instance.Name, upgrade.ToVersion))
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// migrateCluster copies the definitions of src to dest and creates a shovel on dest for every
+// queue of src which still has messages. It returns true once all queues of src are empty.
+func (r *Reconciler) migrateCluster(ctx context.Context, h *helper.Helper, src, dest *rabbitmqv2.RabbitmqCluster) (bool, error) {
+	srcClient, srcSecret, err := newClusterAPIClient(ctx, h, src, r.Transport)
+	if err != nil {
+		return false, err
+	}
+	destClient, _, err := newClusterAPIClient(ctx, h, dest, r.Transport)
+	if err != nil {
+		return false, err
+	}
+
+	defs, err := srcClient.GetDefinitions()
+	if err != nil {
+		return false, err
+	}
+	if err := destClient.ImportDefinitions(defs); err != nil {
+		return false, err
+	}
+
+	queues, err := srcClient.ListQueues()
+	if err != nil {
+		return false, err
+	}
+	remaining := 0
+	for _, q := range queues {
+		if q.Messages == 0 {
+			continue
+		}
+		remaining += q.Messages
+		shovel := rabbitmqapi.Shovel{
+			SrcURI:         amqpURI(src, srcSecret, q.Vhost),
+			SrcQueue:       q.Name,
+			DestURI:        "amqp://",
+			DestQueue:      q.Name,
+			SrcDeleteAfter: "queue-length",
+			AckMode:        "on-confirm",
+		}
+		if err := destClient.CreateOrUpdateShovel(q.Vhost, upgradeShovelPrefix+q.Name, shovel); err != nil {
+			return false, err
+		}
+	}
+	return remaining == 0, nil
+}
+
+// amqpURI returns the AMQP URI of a vhost of the cluster for its default user
+func amqpURI(cluster *rabbitmqv2.RabbitmqCluster, secret *corev1.Secret, vhost string) string {
+	scheme, port, query := "amqp", "5672", ""
+	if cluster.Spec.TLS.SecretName != "" {
+		scheme, port = "amqps", "5671"
+		query = "?cacertfile=/etc/rabbitmq-tls/ca.crt&verify=verify_peer"
+	}
+	userinfo := url.UserPassword(string(secret.Data["username"]), string(secret.Data["password"]))
+	host := net.JoinHostPort(string(secret.Data["host"]), port)
+	return fmt.Sprintf("%s://%s@%s/%s%s", scheme, userinfo.String(), host, url.PathEscape(vhost), query)
+}
+
+// reconcileVersion records the RabbitMQ version the cluster reports and completes an InPlace
+// upgrade once the new image runs on all replicas
+func (r *Reconciler) reconcileVersion(ctx context.Context, instance *rabbitmqv1beta1.RabbitMq, h *helper.Helper, cluster *rabbitmqv2.RabbitmqCluster) error {
+	upgrade := instance.Status.Upgrade
+	rolling := upgrade != nil && upgrade.Phase == rabbitmqv1beta1.UpgradePhaseRollingImage
+	if instance.Status.Version != "" && !rolling {
+		return nil
+	}
+	if rolling && (cluster.Spec.Image != upgrade.ToImage || !isRabbitmqClusterReady(cluster)) {
+		return nil
+	}
+
+	apiClient, _, err := newClusterAPIClient(ctx, h, cluster, r.Transport)
+	if err != nil {
+		return err
+	}
+	overview, err := apiClient.GetOverview()
+	if err != nil {
+		return err
+	}
+	instance.Status.Version = overview.RabbitMQVersion
+
+	if rolling {
+		upgrade.ToVersion = overview.RabbitMQVersion
+		upgrade.Phase = rabbitmqv1beta1.UpgradePhaseCompleted
+		instance.Status.Conditions.MarkTrue(rabbitmqv1beta1.RabbitMqUpgradeReadyCondition, rabbitmqv1beta1.RabbitMqUpgradeReadyMessage)
+		r.GetLogger(ctx).Info(fmt.Sprintf("Upgrade of RabbitMq %s to %s completed", instance.Name, upgrade.ToVersion))
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ package rabbitmq
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,11 +31,11 @@ import (
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	helper "github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	oko_secret "github.com/openstack-k8s-operators/lib-common/modules/common/secret"
-	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 )
 
 const policyFinalizer = "rabbitmqpolicy.openstack.org/finalizer"
@@ -44,13 +45,18 @@ const policyFinalizer = "rabbitmqpolicy.openstack.org/finalizer"
 //nolint:revive
 type RabbitMQPolicyReconciler struct {
 	client.Client
-	Kclient kubernetes.Interface
-	Scheme  *runtime.Scheme
+	Kclient  kubernetes.Interface
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Transport - overrides the HTTP transport of the management API clients,
+	// defaults to the transport of rabbitmqapi.NewClient
+	Transport http.RoundTripper
 }
 
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqpolicies/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqpolicies/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile reconciles a RabbitMQPolicy object
 func (r *RabbitMQPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -122,8 +128,7 @@ func (r *RabbitMQPolicyReconciler) reconcileNormal(ctx context.Context, instance
 	}
 
 	// Get RabbitMQ cluster
-	rabbit := &rabbitmqclusterv2.RabbitmqCluster{}
-	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RabbitmqClusterName, Namespace: instance.Namespace}, rabbit)
+	rabbit, err := getActiveRabbitmqCluster(ctx, r.Client, instance.Spec.RabbitmqClusterName, instance.Namespace)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQPolicyReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQPolicyReadyErrorMessage, err.Error()))
 		return ctrl.Result{}, err
@@ -144,7 +149,7 @@ func (r *RabbitMQPolicyReconciler) reconcileNormal(ctx context.Context, instance
 		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQPolicyReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQPolicyReadyErrorMessage, err.Error()))
 		return ctrl.Result{}, err
 	}
-	apiClient := rabbitmqapi.NewClient(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert)
+	apiClient := rabbitmqapi.NewClientWithTransport(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert, r.Transport)
 
 	// Create or update policy
 	var definition map[string]interface{}
@@ -154,9 +159,13 @@ func (r *RabbitMQPolicyReconciler) reconcileNormal(ctx context.Context, instance
 	}
 	err = apiClient.CreateOrUpdatePolicy(vhostName, policyName, instance.Spec.Pattern, definition, instance.Spec.Priority, instance.Spec.ApplyTo)
 	if err != nil {
-		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQPolicyReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQPolicyReadyErrorMessage, err.Error()))
+		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQPolicyReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQPolicyReadyErrorMessage, recordAPIError(r.Recorder, instance, err)))
 		return ctrl.Result{}, err
 	}
+	// The policy is applied on every reconcile, only record when it becomes ready
+	if !instance.Status.Conditions.IsTrue(rabbitmqv1.RabbitMQPolicyReadyCondition) {
+		recordAPIEvent(r.Recorder, instance, eventReasonPolicyApplied, "Applied RabbitMQ policy %s on vhost %s", policyName, vhostName)
+	}
 
 	instance.Status.Conditions.MarkTrue(rabbitmqv1.RabbitMQPolicyReadyCondition, rabbitmqv1.RabbitMQPolicyReadyMessage)
 	instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
@@ -184,8 +193,7 @@ func (r *RabbitMQPolicyReconciler) reconcileDelete(ctx context.Context, instance
 	}
 
 	// Get RabbitMQ cluster
-	rabbit := &rabbitmqclusterv2.RabbitmqCluster{}
-	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RabbitmqClusterName, Namespace: instance.Namespace}, rabbit)
+	rabbit, err := getActiveRabbitmqCluster(ctx, r.Client, instance.Spec.RabbitmqClusterName, instance.Namespace)
 
 	// If cluster is being deleted or not found, skip cleanup and just remove finalizer
 	if err != nil && !k8s_errors.IsNotFound(err) {
@@ -216,7 +224,7 @@ func (r *RabbitMQPolicyReconciler) reconcileDelete(ctx context.Context, instance
 		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQPolicyReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQPolicyReadyErrorMessage, err.Error()))
 		return ctrl.Result{}, err
 	}
-	apiClient := rabbitmqapi.NewClient(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert)
+	apiClient := rabbitmqapi.NewClientWithTransport(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert, r.Transport)
 
 	// Delete policy from RabbitMQ
 	// Note: DeletePolicy already treats 404 as success
@@ -229,9 +237,11 @@ func (r *RabbitMQPolicyReconciler) reconcileDelete(ctx context.Context, instance
 		// - Controller will retry with exponential backoff for transient issues
 		// - For persistent issues, operator logs will show clear errors
 		// - Admin escape hatch: manually remove finalizer using kubectl patch if needed
+		recordAPIError(r.Recorder, instance, err)
 		log.FromContext(ctx).Error(err, "Failed to delete policy from RabbitMQ, will retry", "policy", policyName, "vhost", vhostName)
 		return ctrl.Result{}, err
 	}
+	recordAPIEvent(r.Recorder, instance, eventReasonPolicyDeleted, "Deleted RabbitMQ policy %s on vhost %s", policyName, vhostName)
 
 	controllerutil.RemoveFinalizer(instance, policyFinalizer)
 	return ctrl.Result{}, nil
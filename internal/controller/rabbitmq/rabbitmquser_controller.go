@@ -21,6 +21,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"net/http"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -35,11 +36,11 @@ import (
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	helper "github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	oko_secret "github.com/openstack-k8s-operators/lib-common/modules/common/secret"
-	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 )
 
 // userFinalizer is the controller-level finalizer for RabbitMQUser resources.
@@ -63,8 +64,12 @@ func generatePassword(length int) (string, error) {
 //nolint:revive
 type RabbitMQUserReconciler struct {
 	client.Client
-	Kclient kubernetes.Interface
-	Scheme  *runtime.Scheme
+	Kclient  kubernetes.Interface
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Transport - overrides the HTTP transport of the management API clients,
+	// defaults to the transport of rabbitmqapi.NewClient
+	Transport http.RoundTripper
 }
 
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqusers,verbs=get;list;watch;create;update;patch;delete
@@ -72,8 +77,10 @@ type RabbitMQUserReconciler struct {
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqusers/finalizers,verbs=update
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqvhosts,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqvhosts/finalizers,verbs=update
+//+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqs,verbs=get;list;watch
 //+kubebuilder:rbac:groups=rabbitmq.com,resources=rabbitmqclusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile reconciles a RabbitMQUser object
 func (r *RabbitMQUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -133,11 +140,32 @@ func (r *RabbitMQUserReconciler) reconcileNormal(ctx context.Context, instance *
 
 	// Username is defaulted by webhook
 	username := instance.Spec.Username
+	clusterNamespace := instance.GetRabbitmqClusterNamespace()
+
+	// Only bind to a RabbitMQ cluster in another namespace if its RabbitMq allows it
+	allowed, err := isNamespaceAllowed(ctx, h, instance.Spec.RabbitmqClusterName, clusterNamespace, instance.Namespace)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQUserReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQUserReadyErrorMessage, err.Error()))
+		return ctrl.Result{}, err
+	}
+	if !allowed {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			rabbitmqv1.RabbitMQUserReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			rabbitmqv1.RabbitMqNamespaceNotAllowedMessage,
+			instance.Namespace,
+			clusterNamespace,
+			instance.Spec.RabbitmqClusterName))
+		return ctrl.Result{RequeueAfter: time.Duration(30) * time.Second}, nil
+	}
 
 	// Handle VhostRef changes - remove finalizer from old vhost if changed
 	// We track the previous vhost CR name in status.VhostRef to detect changes
+	// Finalizers are only set on vhosts in the namespace of the user, the
+	// per-user finalizer name is not unique across namespaces.
 	userFinalizer := rabbitmqv1.UserVhostFinalizerPrefix + instance.Name
-	if instance.Status.VhostRef != "" && instance.Status.VhostRef != instance.Spec.VhostRef {
+	if !instance.IsCrossNamespace() && instance.Status.VhostRef != "" && instance.Status.VhostRef != instance.Spec.VhostRef {
 		// VhostRef changed - remove finalizer from old vhost
 		oldVhost := &rabbitmqv1.RabbitMQVhost{}
 		if err := r.Get(ctx, types.NamespacedName{Name: instance.Status.VhostRef, Namespace: instance.Namespace}, oldVhost); err == nil {
@@ -160,7 +188,7 @@ func (r *RabbitMQUserReconciler) reconcileNormal(ctx context.Context, instance *
 	var vhost *rabbitmqv1.RabbitMQVhost
 	if instance.Spec.VhostRef != "" {
 		vhost = &rabbitmqv1.RabbitMQVhost{}
-		if err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.VhostRef, Namespace: instance.Namespace}, vhost); err != nil {
+		if err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.VhostRef, Namespace: clusterNamespace}, vhost); err != nil {
 			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQUserReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQUserReadyErrorMessage, err.Error()))
 			return ctrl.Result{}, err
 		}
@@ -170,7 +198,7 @@ func (r *RabbitMQUserReconciler) reconcileNormal(ctx context.Context, instance *
 		// Add per-user finalizer to vhost to prevent deletion while this user exists
 		// Design note: Using per-user finalizers (rabbitmquser.rabbitmq.openstack.org/user-<name>)
 		// instead of a shared finalizer avoids the need for reference counting.
-		if controllerutil.AddFinalizer(vhost, userFinalizer) {
+		if !instance.IsCrossNamespace() && controllerutil.AddFinalizer(vhost, userFinalizer) {
 			if err := r.Update(ctx, vhost); err != nil {
 				// Requeue to retry - this ensures the finalizer is eventually added
 				Log.Error(err, "Failed to add finalizer to vhost, requeueing", "vhost", instance.Spec.VhostRef, "finalizer", userFinalizer)
@@ -180,8 +208,7 @@ func (r *RabbitMQUserReconciler) reconcileNormal(ctx context.Context, instance *
 	}
 
 	// Get RabbitMQ cluster
-	rabbit := &rabbitmqclusterv2.RabbitmqCluster{}
-	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RabbitmqClusterName, Namespace: instance.Namespace}, rabbit)
+	rabbit, err := getActiveRabbitmqCluster(ctx, r.Client, instance.Spec.RabbitmqClusterName, clusterNamespace)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQUserReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQUserReadyErrorMessage, err.Error()))
 		return ctrl.Result{}, err
@@ -229,7 +256,7 @@ func (r *RabbitMQUserReconciler) reconcileNormal(ctx context.Context, instance *
 	// Only create/update user in RabbitMQ if secret was just created
 	if op == controllerutil.OperationResultCreated {
 		// Get admin credentials
-		rabbitSecret, _, err := oko_secret.GetSecret(ctx, h, rabbit.Status.DefaultUser.SecretReference.Name, instance.Namespace)
+		rabbitSecret, _, err := oko_secret.GetSecret(ctx, h, rabbit.Status.DefaultUser.SecretReference.Name, clusterNamespace)
 		if err != nil {
 			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQUserReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQUserReadyErrorMessage, err.Error()))
 			return ctrl.Result{}, err
@@ -238,12 +265,12 @@ func (r *RabbitMQUserReconciler) reconcileNormal(ctx context.Context, instance *
 		// Create API client
 		baseURL := getManagementURL(rabbit, rabbitSecret)
 		tlsEnabled := rabbit.Spec.TLS.SecretName != ""
-		caCert, err := getTLSCACert(ctx, h, rabbit, instance.Namespace)
+		caCert, err := getTLSCACert(ctx, h, rabbit, clusterNamespace)
 		if err != nil {
 			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQUserReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQUserReadyErrorMessage, err.Error()))
 			return ctrl.Result{}, err
 		}
-		apiClient := rabbitmqapi.NewClient(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert)
+		apiClient := rabbitmqapi.NewClientWithTransport(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert, r.Transport)
 
 		// Create user
 		tags := instance.Spec.Tags
@@ -252,7 +279,7 @@ func (r *RabbitMQUserReconciler) reconcileNormal(ctx context.Context, instance *
 		}
 		err = apiClient.CreateOrUpdateUser(username, password, tags)
 		if err != nil {
-			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQUserReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQUserReadyErrorMessage, err.Error()))
+			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQUserReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQUserReadyErrorMessage, recordAPIError(r.Recorder, instance, err)))
 			return ctrl.Result{}, err
 		}
 
@@ -265,9 +292,10 @@ func (r *RabbitMQUserReconciler) reconcileNormal(ctx context.Context, instance *
 			instance.Spec.Permissions.Write,
 			instance.Spec.Permissions.Read)
 		if err != nil {
-			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQUserReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQUserReadyErrorMessage, err.Error()))
+			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQUserReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQUserReadyErrorMessage, recordAPIError(r.Recorder, instance, err)))
 			return ctrl.Result{}, err
 		}
+		recordAPIEvent(r.Recorder, instance, eventReasonUserCreated, "Created RabbitMQ user %s with permissions on vhost %s", username, vhostName)
 	}
 
 	instance.Status.SecretName = secretName
@@ -295,7 +323,7 @@ func (r *RabbitMQUserReconciler) reconcileDelete(ctx context.Context, instance *
 	if vhostRef == "" {
 		vhostRef = instance.Spec.VhostRef
 	}
-	if vhostRef != "" {
+	if vhostRef != "" && !instance.IsCrossNamespace() {
 		userFinalizer := rabbitmqv1.UserVhostFinalizerPrefix + instance.Name
 		vhost := &rabbitmqv1.RabbitMQVhost{}
 		if err := r.Get(ctx, types.NamespacedName{Name: vhostRef, Namespace: instance.Namespace}, vhost); err == nil {
@@ -320,11 +348,13 @@ func (r *RabbitMQUserReconciler) reconcileDelete(ctx context.Context, instance *
 		username = instance.Spec.Username
 	}
 
+	clusterNamespace := instance.GetRabbitmqClusterNamespace()
+
 	// Get vhost - default to "/" if VhostRef is empty
 	vhostName := "/"
 	if instance.Spec.VhostRef != "" {
 		vhost := &rabbitmqv1.RabbitMQVhost{}
-		err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.VhostRef, Namespace: instance.Namespace}, vhost)
+		err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.VhostRef, Namespace: clusterNamespace}, vhost)
 		if err != nil && !k8s_errors.IsNotFound(err) {
 			// Log non-NotFound errors but continue with deletion
 			log.FromContext(ctx).Error(err, "Failed to get vhost", "vhost", instance.Spec.VhostRef)
@@ -335,8 +365,7 @@ func (r *RabbitMQUserReconciler) reconcileDelete(ctx context.Context, instance *
 	}
 
 	// Get RabbitMQ cluster
-	rabbit := &rabbitmqclusterv2.RabbitmqCluster{}
-	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RabbitmqClusterName, Namespace: instance.Namespace}, rabbit)
+	rabbit, err := getActiveRabbitmqCluster(ctx, r.Client, instance.Spec.RabbitmqClusterName, clusterNamespace)
 
 	// If cluster is being deleted or not found, skip cleanup and just remove finalizer
 	if err != nil && !k8s_errors.IsNotFound(err) {
@@ -352,7 +381,7 @@ func (r *RabbitMQUserReconciler) reconcileDelete(ctx context.Context, instance *
 
 	// Cluster exists and is not being deleted - perform cleanup
 	// Get admin credentials
-	rabbitSecret, _, err := oko_secret.GetSecret(ctx, h, rabbit.Status.DefaultUser.SecretReference.Name, instance.Namespace)
+	rabbitSecret, _, err := oko_secret.GetSecret(ctx, h, rabbit.Status.DefaultUser.SecretReference.Name, clusterNamespace)
 	if err != nil {
 		// If cluster exists and is healthy, secret should be available
 		// Return error to retry
@@ -362,25 +391,28 @@ func (r *RabbitMQUserReconciler) reconcileDelete(ctx context.Context, instance *
 	// Create API client
 	baseURL := getManagementURL(rabbit, rabbitSecret)
 	tlsEnabled := rabbit.Spec.TLS.SecretName != ""
-	caCert, err := getTLSCACert(ctx, h, rabbit, instance.Namespace)
+	caCert, err := getTLSCACert(ctx, h, rabbit, clusterNamespace)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	apiClient := rabbitmqapi.NewClient(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert)
+	apiClient := rabbitmqapi.NewClientWithTransport(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert, r.Transport)
 
 	// Delete permissions and user from RabbitMQ
 	// The Delete methods already treat 404 as success
 	if err := apiClient.DeletePermissions(vhostName, username); err != nil {
 		// Return error to trigger retry - see rabbitmqpolicy_controller.go for detailed rationale
+		recordAPIError(r.Recorder, instance, err)
 		Log.Error(err, "Failed to delete permissions from RabbitMQ, will retry", "user", username, "vhost", vhostName)
 		return ctrl.Result{}, err
 	}
 
 	if err := apiClient.DeleteUser(username); err != nil {
 		// Return error to trigger retry - see rabbitmqpolicy_controller.go for detailed rationale
+		recordAPIError(r.Recorder, instance, err)
 		Log.Error(err, "Failed to delete user from RabbitMQ, will retry", "user", username)
 		return ctrl.Result{}, err
 	}
+	recordAPIEvent(r.Recorder, instance, eventReasonUserDeleted, "Deleted RabbitMQ user %s", username)
 
 	// Delete secret (use CR name, same as in reconcileNormal)
 	secretName := fmt.Sprintf("rabbitmq-user-%s", instance.Name)
@@ -18,6 +18,7 @@ package rabbitmq
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -30,11 +31,11 @@ import (
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	helper "github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	oko_secret "github.com/openstack-k8s-operators/lib-common/modules/common/secret"
-	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 )
 
 const vhostFinalizer = "rabbitmqvhost.openstack.org/finalizer"
@@ -44,8 +45,12 @@ const vhostFinalizer = "rabbitmqvhost.openstack.org/finalizer"
 //nolint:revive
 type RabbitMQVhostReconciler struct {
 	client.Client
-	Kclient kubernetes.Interface
-	Scheme  *runtime.Scheme
+	Kclient  kubernetes.Interface
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Transport - overrides the HTTP transport of the management API clients,
+	// defaults to the transport of rabbitmqapi.NewClient
+	Transport http.RoundTripper
 }
 
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqvhosts,verbs=get;list;watch;create;update;patch;delete
@@ -54,6 +59,7 @@ type RabbitMQVhostReconciler struct {
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqusers,verbs=get;list;watch
 //+kubebuilder:rbac:groups=rabbitmq.com,resources=rabbitmqclusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile reconciles a RabbitMQVhost object
 func (r *RabbitMQVhostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -110,8 +116,7 @@ func (r *RabbitMQVhostReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 func (r *RabbitMQVhostReconciler) reconcileNormal(ctx context.Context, instance *rabbitmqv1.RabbitMQVhost, h *helper.Helper) (ctrl.Result, error) {
 	// Get RabbitMQ cluster
-	rabbit := &rabbitmqclusterv2.RabbitmqCluster{}
-	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RabbitmqClusterName, Namespace: instance.Namespace}, rabbit)
+	rabbit, err := getActiveRabbitmqCluster(ctx, r.Client, instance.Spec.RabbitmqClusterName, instance.Namespace)
 	if err != nil {
 		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQVhostReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQVhostReadyErrorMessage, err.Error()))
 		return ctrl.Result{}, err
@@ -132,7 +137,7 @@ func (r *RabbitMQVhostReconciler) reconcileNormal(ctx context.Context, instance
 		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQVhostReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQVhostReadyErrorMessage, err.Error()))
 		return ctrl.Result{}, err
 	}
-	apiClient := rabbitmqapi.NewClient(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert)
+	apiClient := rabbitmqapi.NewClientWithTransport(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert, r.Transport)
 
 	// Create vhost
 	vhostName := instance.Spec.Name
@@ -143,9 +148,13 @@ func (r *RabbitMQVhostReconciler) reconcileNormal(ctx context.Context, instance
 	if vhostName != "/" {
 		err = apiClient.CreateOrUpdateVhost(vhostName)
 		if err != nil {
-			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQVhostReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQVhostReadyErrorMessage, err.Error()))
+			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQVhostReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQVhostReadyErrorMessage, recordAPIError(r.Recorder, instance, err)))
 			return ctrl.Result{}, err
 		}
+		// The vhost is applied on every reconcile, only record when it becomes ready
+		if !instance.Status.Conditions.IsTrue(rabbitmqv1.RabbitMQVhostReadyCondition) {
+			recordAPIEvent(r.Recorder, instance, eventReasonVhostCreated, "Created RabbitMQ vhost %s", vhostName)
+		}
 	}
 
 	instance.Status.Conditions.MarkTrue(rabbitmqv1.RabbitMQVhostReadyCondition, rabbitmqv1.RabbitMQVhostReadyMessage)
@@ -203,8 +212,7 @@ func (r *RabbitMQVhostReconciler) reconcileDelete(ctx context.Context, instance
 	}
 
 	// Get RabbitMQ cluster
-	rabbit := &rabbitmqclusterv2.RabbitmqCluster{}
-	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RabbitmqClusterName, Namespace: instance.Namespace}, rabbit)
+	rabbit, err := getActiveRabbitmqCluster(ctx, r.Client, instance.Spec.RabbitmqClusterName, instance.Namespace)
 
 	// If cluster is being deleted or not found, skip cleanup and just remove finalizer
 	if err != nil && !k8s_errors.IsNotFound(err) {
@@ -235,7 +243,7 @@ func (r *RabbitMQVhostReconciler) reconcileDelete(ctx context.Context, instance
 		instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.RabbitMQVhostReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.RabbitMQVhostReadyErrorMessage, err.Error()))
 		return ctrl.Result{}, err
 	}
-	apiClient := rabbitmqapi.NewClient(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert)
+	apiClient := rabbitmqapi.NewClientWithTransport(baseURL, string(rabbitSecret.Data["username"]), string(rabbitSecret.Data["password"]), tlsEnabled, caCert, r.Transport)
 
 	// Delete vhost (skip default)
 	vhostName := instance.Spec.Name
@@ -246,9 +254,11 @@ func (r *RabbitMQVhostReconciler) reconcileDelete(ctx context.Context, instance
 		// DeleteVhost already treats 404 as success
 		if err := apiClient.DeleteVhost(vhostName); err != nil {
 			// Return error to trigger retry - see rabbitmqpolicy_controller.go for detailed rationale
+			recordAPIError(r.Recorder, instance, err)
 			Log.Error(err, "Failed to delete vhost from RabbitMQ, will retry", "vhost", vhostName)
 			return ctrl.Result{}, err
 		}
+		recordAPIEvent(r.Recorder, instance, eventReasonVhostDeleted, "Deleted RabbitMQ vhost %s", vhostName)
 	}
 
 	controllerutil.RemoveFinalizer(instance, vhostFinalizer)
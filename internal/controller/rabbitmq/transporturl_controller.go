@@ -40,6 +40,7 @@ import (
 	helper "github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	object "github.com/openstack-k8s-operators/lib-common/modules/common/object"
 	oko_secret "github.com/openstack-k8s-operators/lib-common/modules/common/secret"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
 	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -78,8 +79,8 @@ func (r *TransportURLReconciler) GetLogger(ctx context.Context) logr.Logger {
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=transporturls/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=transporturls/finalizers,verbs=update
 //+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqs,verbs=get;list;watch
-//+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqusers,verbs=get;list;watch;create;update;patch
-//+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqvhosts,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqusers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=rabbitmq.openstack.org,resources=rabbitmqvhosts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rabbitmq.com,resources=rabbitmqclusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete;
 
@@ -177,6 +178,49 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 	Log := r.GetLogger(ctx)
 	Log.Info("Reconciling Service")
 
+	clusterNamespace := instance.GetRabbitmqClusterNamespace()
+
+	// Only bind to a RabbitMQ cluster in another namespace if its RabbitMq allows it
+	allowed, err := isNamespaceAllowed(ctx, helper, instance.Spec.RabbitmqClusterName, clusterNamespace, instance.Namespace)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			rabbitmqv1.TransportURLReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			rabbitmqv1.TransportURLReadyErrorMessage,
+			err.Error()))
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !allowed {
+		// Changes to the RabbitMq allowedNamespaces trigger a new reconcile
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			rabbitmqv1.TransportURLReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			rabbitmqv1.RabbitMqNamespaceNotAllowedMessage,
+			instance.Namespace,
+			clusterNamespace,
+			instance.Spec.RabbitmqClusterName))
+		return ctrl.Result{}, nil
+	}
+
+	// A cross-namespace TransportURL must not use a RabbitMQUser of the cluster namespace
+	// or fall back to the admin credentials. The webhook rejects this too, but it can be
+	// disabled, so the controller does not trust the spec either.
+	if errs := instance.ValidateCrossNamespaceCredentials(); len(errs) > 0 {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			rabbitmqv1.TransportURLReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			rabbitmqv1.TransportURLCrossNamespaceCredentialsMessage,
+			clusterNamespace,
+			errs.ToAggregate().Error()))
+		return ctrl.Result{}, nil
+	}
+
 	// Get RabbitMQ cluster
 	rabbit, err := getRabbitmqCluster(ctx, helper, instance)
 	if err != nil {
@@ -201,7 +245,7 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 	}
 
 	// Get cluster admin secret for connection details
-	rabbitSecret, _, err := oko_secret.GetSecret(ctx, helper, rabbit.Status.DefaultUser.SecretReference.Name, instance.Namespace)
+	rabbitSecret, _, err := oko_secret.GetSecret(ctx, helper, rabbit.Status.DefaultUser.SecretReference.Name, clusterNamespace)
 	if err != nil {
 		if k8s_errors.IsNotFound(err) {
 			instance.Status.Conditions.Set(condition.FalseCondition(
@@ -259,7 +303,7 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 
 	// Determine credentials and vhost
 	var finalUsername, finalPassword, vhostName string
-	var userRef string
+	var userRef, userSecretName string
 
 	if instance.Spec.UserRef != "" {
 		userRef = instance.Spec.UserRef
@@ -271,17 +315,17 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 		}
 		var vhostRef string
 		if vhostName != "/" {
-			vhostRef = fmt.Sprintf("%s-%s-vhost", instance.Name, vhostName)
+			vhostRef = r.childName(instance, fmt.Sprintf("%s-%s-vhost", instance.Name, vhostName))
 			vhost := &rabbitmqv1.RabbitMQVhost{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      vhostRef,
-					Namespace: instance.Namespace,
+					Namespace: clusterNamespace,
 				},
 				Spec: rabbitmqv1.RabbitMQVhostSpec{RabbitmqClusterName: instance.Spec.RabbitmqClusterName, Name: vhostName},
 			}
 			// Note: During normal reconciliation (not deletion), we return errors rather than
 			// just logging them, as we need these operations to succeed for correct functionality.
-			if err := controllerutil.SetControllerReference(instance, vhost, r.Scheme); err != nil {
+			if err := r.setChildOwnership(instance, vhost); err != nil {
 				instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.TransportURLReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.TransportURLReadyErrorMessage, err.Error()))
 				return ctrl.Result{}, err
 			}
@@ -298,15 +342,15 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 		}
 
 		// Create RabbitMQUser - use username in resource name for blue/green rotation
-		userRef = fmt.Sprintf("%s-%s-user", instance.Name, instance.Spec.Username)
+		userRef = r.childName(instance, fmt.Sprintf("%s-%s-user", instance.Name, instance.Spec.Username))
 		user := &rabbitmqv1.RabbitMQUser{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      userRef,
-				Namespace: instance.Namespace,
+				Namespace: clusterNamespace,
 			},
 			Spec: rabbitmqv1.RabbitMQUserSpec{RabbitmqClusterName: instance.Spec.RabbitmqClusterName, Username: instance.Spec.Username, VhostRef: vhostRef},
 		}
-		if err := controllerutil.SetControllerReference(instance, user, r.Scheme); err != nil {
+		if err := r.setChildOwnership(instance, user); err != nil {
 			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.TransportURLReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.TransportURLReadyErrorMessage, err.Error()))
 			return ctrl.Result{}, err
 		}
@@ -325,11 +369,11 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 		// Remove TransportURL finalizer from previously used users that are no longer referenced
 		// This handles credential rotation and rollback scenarios
 		userList := &rabbitmqv1.RabbitMQUserList{}
-		if err := r.List(ctx, userList, client.InNamespace(instance.Namespace)); err == nil {
+		if err := r.List(ctx, userList, r.childListOptions(instance)...); err == nil {
 			for i := range userList.Items {
 				oldUser := &userList.Items[i]
 				// Check if this user is owned by this TransportURL
-				isOwned := object.CheckOwnerRefExist(instance.GetUID(), oldUser.GetOwnerReferences())
+				isOwned := r.isChildOwned(instance, oldUser)
 				// If owned by this TransportURL but not the current userRef, remove finalizer
 				if isOwned && oldUser.Name != userRef {
 					if controllerutil.RemoveFinalizer(oldUser, rabbitmqv1.TransportURLFinalizer) {
@@ -345,7 +389,7 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 	if userRef != "" {
 		// Wait for RabbitMQUser to be ready
 		rabbitUser := &rabbitmqv1.RabbitMQUser{}
-		if err = r.Get(ctx, types.NamespacedName{Name: userRef, Namespace: instance.Namespace}, rabbitUser); err != nil {
+		if err = r.Get(ctx, types.NamespacedName{Name: userRef, Namespace: clusterNamespace}, rabbitUser); err != nil {
 			if k8s_errors.IsNotFound(err) {
 				instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.TransportURLReadyCondition, condition.RequestedReason, condition.SeverityInfo, rabbitmqv1.TransportURLInProgressMessage))
 				Log.Info(fmt.Sprintf("RabbitMQUser %s not found, waiting for it to be created", userRef))
@@ -361,7 +405,7 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 		}
 
 		// Get credentials from user secret
-		userSecret, _, err := oko_secret.GetSecret(ctx, helper, rabbitUser.Status.SecretName, instance.Namespace)
+		userSecret, _, err := oko_secret.GetSecret(ctx, helper, rabbitUser.Status.SecretName, rabbitUser.Namespace)
 		if err != nil {
 			instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.TransportURLReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.TransportURLReadyErrorMessage, err.Error()))
 			return ctrl.Result{}, err
@@ -369,6 +413,27 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 		finalUsername = string(userSecret.Data["username"])
 		finalPassword = string(userSecret.Data["password"])
 		vhostName = rabbitUser.Status.Vhost
+		userSecretName = userSecret.Name
+
+		// The user secret lives in the cluster namespace, mirror the credentials
+		// into the TransportURL namespace so that consumers can mount them
+		if instance.IsCrossNamespace() {
+			mirror := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("rabbitmq-transport-url-%s-user", instance.Name),
+					Namespace: instance.Namespace,
+				},
+				Data: map[string][]byte{
+					"username": userSecret.Data["username"],
+					"password": userSecret.Data["password"],
+				},
+			}
+			if _, _, err := oko_secret.CreateOrPatchSecret(ctx, helper, instance, mirror); err != nil {
+				instance.Status.Conditions.Set(condition.FalseCondition(rabbitmqv1.TransportURLReadyCondition, condition.ErrorReason, condition.SeverityWarning, rabbitmqv1.TransportURLReadyErrorMessage, err.Error()))
+				return ctrl.Result{}, err
+			}
+			userSecretName = mirror.Name
+		}
 	} else {
 		// Use default cluster admin credentials
 		finalUsername = string(adminUsername)
@@ -381,11 +446,24 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 
 	// Get RabbitMq CR for both secret generation and status update
 	rabbitmqCR := &rabbitmqv1.RabbitMq{}
-	err = r.Get(ctx, types.NamespacedName{Name: instance.Spec.RabbitmqClusterName, Namespace: instance.Namespace}, rabbitmqCR)
+	err = r.Get(ctx, types.NamespacedName{Name: instance.Spec.RabbitmqClusterName, Namespace: clusterNamespace}, rabbitmqCR)
 
 	// Build list of hosts - use ServiceHostnames from status if available, otherwise use host from secret
 	var hosts []string
-	if err == nil && len(rabbitmqCR.Status.ServiceHostnames) > 0 {
+	// The per-pod services only front the cluster of the RabbitMq, not the green one of an upgrade
+	if err == nil && len(rabbitmqCR.Status.ServiceHostnames) > 0 && rabbitmqCR.GetActiveClusterName() == rabbitmqCR.Name {
+		// Keep the current transport URL until the per-pod service hostnames
+		// resolve, clients would fail to connect otherwise
+		if !rabbitmqCR.Status.Conditions.IsTrue(rabbitmqv1.RabbitMqServiceDNSReadyCondition) {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				rabbitmqv1.TransportURLReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				rabbitmqv1.TransportURLWaitingServiceDNSMessage,
+				rabbitmqCR.Name))
+			Log.Info(fmt.Sprintf("Waiting for per-pod service hostnames of %s to resolve", rabbitmqCR.Name))
+			return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+		}
 		hosts = rabbitmqCR.Status.ServiceHostnames
 		Log.Info(fmt.Sprintf("Using per-pod service hostnames: %v", hosts))
 	} else {
@@ -453,6 +531,7 @@ func (r *TransportURLReconciler) reconcileNormal(ctx context.Context, instance *
 	instance.Status.SecretName = secret.Name
 	instance.Status.RabbitmqUsername = finalUsername
 	instance.Status.RabbitmqVhost = vhostName
+	instance.Status.UserSecretName = userSecretName
 
 	instance.Status.Conditions.MarkTrue(rabbitmqv1.TransportURLReadyCondition, rabbitmqv1.TransportURLReadyMessage)
 
@@ -513,6 +592,7 @@ func (r *TransportURLReconciler) createTransportURLSecret(
 // fields to index to reconcile when change
 const (
 	rabbitmqClusterNameField = ".spec.rabbitmqClusterName"
+	rabbitmqClusterRefField  = ".spec.rabbitmqClusterRef"
 	transportURLFinalizer    = "transporturl.rabbitmq.openstack.org"
 )
 
@@ -522,35 +602,41 @@ func (r *TransportURLReconciler) reconcileDelete(ctx context.Context, instance *
 
 	// Remove TransportURL finalizer from all owned users and vhosts
 	userList := &rabbitmqv1.RabbitMQUserList{}
-	if err := r.List(ctx, userList, client.InNamespace(instance.Namespace)); err == nil {
+	if err := r.List(ctx, userList, r.childListOptions(instance)...); err == nil {
 		for i := range userList.Items {
 			user := &userList.Items[i]
 			// Check if this user is owned by this TransportURL
-			isOwned := object.CheckOwnerRefExist(instance.GetUID(), user.GetOwnerReferences())
-			if isOwned {
+			if r.isChildOwned(instance, user) {
 				if controllerutil.RemoveFinalizer(user, rabbitmqv1.TransportURLFinalizer) {
 					if err := r.Update(ctx, user); err != nil {
 						Log.Error(err, "Failed to remove TransportURL finalizer from user", "user", user.Name)
 						return ctrl.Result{}, err
 					}
 				}
+				if err := r.deleteCrossNamespaceChild(ctx, instance, user); err != nil {
+					Log.Error(err, "Failed to delete user", "user", user.Name, "namespace", user.Namespace)
+					return ctrl.Result{}, err
+				}
 			}
 		}
 	}
 
 	vhostList := &rabbitmqv1.RabbitMQVhostList{}
-	if err := r.List(ctx, vhostList, client.InNamespace(instance.Namespace)); err == nil {
+	if err := r.List(ctx, vhostList, r.childListOptions(instance)...); err == nil {
 		for i := range vhostList.Items {
 			vhost := &vhostList.Items[i]
 			// Check if this vhost is owned by this TransportURL
-			isOwned := object.CheckOwnerRefExist(instance.GetUID(), vhost.GetOwnerReferences())
-			if isOwned {
+			if r.isChildOwned(instance, vhost) {
 				if controllerutil.RemoveFinalizer(vhost, rabbitmqv1.TransportURLFinalizer) {
 					if err := r.Update(ctx, vhost); err != nil {
 						Log.Error(err, "Failed to remove TransportURL finalizer from vhost", "vhost", vhost.Name)
 						return ctrl.Result{}, err
 					}
 				}
+				if err := r.deleteCrossNamespaceChild(ctx, instance, vhost); err != nil {
+					Log.Error(err, "Failed to delete vhost", "vhost", vhost.Name, "namespace", vhost.Namespace)
+					return ctrl.Result{}, err
+				}
 			}
 		}
 	}
@@ -560,6 +646,68 @@ func (r *TransportURLReconciler) reconcileDelete(ctx context.Context, instance *
 	return ctrl.Result{}, nil
 }
 
+// childName returns the name of a RabbitMQUser or RabbitMQVhost created for the TransportURL.
+// Children of cross-namespace TransportURLs share the cluster namespace with other tenants
+// and get prefixed with the namespace of the TransportURL to keep them unique.
+func (r *TransportURLReconciler) childName(instance *rabbitmqv1.TransportURL, name string) string {
+	if instance.IsCrossNamespace() {
+		return fmt.Sprintf("%s-%s", instance.Namespace, name)
+	}
+	return name
+}
+
+// setChildOwnership marks a RabbitMQUser or RabbitMQVhost as owned by the TransportURL.
+// Owner references cannot cross namespaces, so cross-namespace children are tracked with
+// labels and removed explicitly in reconcileDelete instead of being garbage collected.
+func (r *TransportURLReconciler) setChildOwnership(instance *rabbitmqv1.TransportURL, obj client.Object) error {
+	if !instance.IsCrossNamespace() {
+		return controllerutil.SetControllerReference(instance, obj, r.Scheme)
+	}
+	obj.SetLabels(util.MergeStringMaps(obj.GetLabels(), r.childLabels(instance)))
+	return nil
+}
+
+// childLabels returns the labels identifying cross-namespace children of the TransportURL
+func (r *TransportURLReconciler) childLabels(instance *rabbitmqv1.TransportURL) map[string]string {
+	return map[string]string{
+		rabbitmqv1.TransportURLNameLabel:      instance.Name,
+		rabbitmqv1.TransportURLNamespaceLabel: instance.Namespace,
+	}
+}
+
+// childListOptions returns the list options to find the children of the TransportURL
+func (r *TransportURLReconciler) childListOptions(instance *rabbitmqv1.TransportURL) []client.ListOption {
+	if !instance.IsCrossNamespace() {
+		return []client.ListOption{client.InNamespace(instance.Namespace)}
+	}
+	return []client.ListOption{
+		client.InNamespace(instance.GetRabbitmqClusterNamespace()),
+		client.MatchingLabels(r.childLabels(instance)),
+	}
+}
+
+// isChildOwned returns true if obj was created for the TransportURL
+func (r *TransportURLReconciler) isChildOwned(instance *rabbitmqv1.TransportURL, obj client.Object) bool {
+	if !instance.IsCrossNamespace() {
+		return object.CheckOwnerRefExist(instance.GetUID(), obj.GetOwnerReferences())
+	}
+	objLabels := obj.GetLabels()
+	return objLabels[rabbitmqv1.TransportURLNameLabel] == instance.Name &&
+		objLabels[rabbitmqv1.TransportURLNamespaceLabel] == instance.Namespace
+}
+
+// deleteCrossNamespaceChild deletes a cross-namespace child, which is not garbage collected
+// together with the TransportURL
+func (r *TransportURLReconciler) deleteCrossNamespaceChild(ctx context.Context, instance *rabbitmqv1.TransportURL, obj client.Object) error {
+	if !instance.IsCrossNamespace() || !obj.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	if err := r.Delete(ctx, obj); err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 var allWatchFields = []string{
 	rabbitmqClusterNameField,
 }
@@ -578,6 +726,17 @@ func (r *TransportURLReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	// index <namespace>/<name> of the cluster referenced by cross-namespace TransportURLs
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rabbitmqv1.TransportURL{}, rabbitmqClusterRefField, func(rawObj client.Object) []string {
+		cr := rawObj.(*rabbitmqv1.TransportURL)
+		if cr.Spec.RabbitmqClusterName == "" || !cr.IsCrossNamespace() {
+			return nil
+		}
+		return []string{types.NamespacedName{Name: cr.Spec.RabbitmqClusterName, Namespace: cr.GetRabbitmqClusterNamespace()}.String()}
+	}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&rabbitmqv1.TransportURL{}).
 		Owns(&corev1.Secret{}).
@@ -625,6 +784,28 @@ func (r *TransportURLReconciler) findObjectsForSrc(ctx context.Context, src clie
 		}
 	}
 
+	// TransportURLs in other namespaces which reference the cluster
+	crList := &rabbitmqv1.TransportURLList{}
+	listOps := &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(rabbitmqClusterRefField, client.ObjectKeyFromObject(src).String()),
+	}
+	if err := r.List(ctx, crList, listOps); err != nil {
+		Log.Error(err, fmt.Sprintf("listing %s for field: %s - %s", crList.GroupVersionKind().Kind, rabbitmqClusterRefField, src.GetNamespace()))
+		return requests
+	}
+	for _, item := range crList.Items {
+		Log.Info(fmt.Sprintf("input source %s/%s changed, reconcile: %s - %s", src.GetNamespace(), src.GetName(), item.GetName(), item.GetNamespace()))
+
+		requests = append(requests,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.GetName(),
+					Namespace: item.GetNamespace(),
+				},
+			},
+		)
+	}
+
 	return requests
 }
 
@@ -634,9 +815,5 @@ func getRabbitmqCluster(
 	h *helper.Helper,
 	instance *rabbitmqv1.TransportURL,
 ) (*rabbitmqclusterv2.RabbitmqCluster, error) {
-	rabbitMqCluster := &rabbitmqclusterv2.RabbitmqCluster{}
-
-	err := h.GetClient().Get(ctx, types.NamespacedName{Name: instance.Spec.RabbitmqClusterName, Namespace: instance.Namespace}, rabbitMqCluster)
-
-	return rabbitMqCluster, err
+	return getActiveRabbitmqCluster(ctx, h.GetClient(), instance.Spec.RabbitmqClusterName, instance.GetRabbitmqClusterNamespace())
 }
@@ -18,8 +18,12 @@ limitations under the License.
 package redis
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/fields"
@@ -43,19 +47,28 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/labels"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/rsh"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
+	"github.com/openstack-k8s-operators/infra-operator/internal/monitoring"
+	"github.com/openstack-k8s-operators/infra-operator/internal/pdb"
 	redis "github.com/openstack-k8s-operators/infra-operator/internal/redis"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 
 	common_rbac "github.com/openstack-k8s-operators/lib-common/modules/common/rbac"
+	oko_secret "github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	commonservice "github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	commonstatefulset "github.com/openstack-k8s-operators/lib-common/modules/common/statefulset"
 )
@@ -78,12 +91,26 @@ var allWatchFields = []string{
 	topologyField,
 }
 
+// replicationRefreshInterval - how often the replication topology gets queried, more often
+// while no master is elected
+const (
+	replicationRefreshInterval = 30 * time.Second
+	replicationRetryInterval   = 5 * time.Second
+)
+
+// clusterJobRetryInterval - how long a failed cluster job is kept for inspection before it gets
+// replaced by a new attempt
+const clusterJobRetryInterval = 2 * time.Minute
+
 // Reconciler reconciles a Redis object
 type Reconciler struct {
 	client.Client
 	Kclient kubernetes.Interface
 	config  *rest.Config
 	Scheme  *runtime.Scheme
+	// RedisCLI - runs redis-cli with the given arguments in the redis container of a pod and
+	// returns its output, defaults to an exec into the pod
+	RedisCLI func(ctx context.Context, pod types.NamespacedName, args []string) (string, error)
 }
 
 // RBAC for redis resources
@@ -94,10 +121,14 @@ type Reconciler struct {
 // RBAC for deployments and their pods
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+// +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
 
 // RBAC for services
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete;
 
+// RBAC for the auth secret
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
 // service account, role, rolebinding
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles,verbs=get;list;watch;create;update
@@ -107,6 +138,19 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups="",resources=pods,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=topology.openstack.org,resources=topologies,verbs=get;list;watch;update
 
+// RBAC to seed new instances with a backup
+// +kubebuilder:rbac:groups=redis.openstack.org,resources=redisrestores,verbs=get;list;watch
+// +kubebuilder:rbac:groups=redis.openstack.org,resources=redisbackups,verbs=get;list;watch
+
+// RBAC for the ServiceMonitor of the exporters
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+
+// RBAC for the job creating and resharding the cluster mode
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+// Required to limit voluntary disruptions of the pods
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+
 // Reconcile - Redis
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, _err error) {
 	Log := r.GetLogger(ctx)
@@ -186,7 +230,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		condition.UnknownCondition(condition.ServiceAccountReadyCondition, condition.InitReason, condition.ServiceAccountReadyInitMessage),
 		condition.UnknownCondition(condition.RoleReadyCondition, condition.InitReason, condition.RoleReadyInitMessage),
 		condition.UnknownCondition(condition.RoleBindingReadyCondition, condition.InitReason, condition.RoleBindingReadyInitMessage),
+		// PodDisruptionBudget
+		condition.UnknownCondition(condition.PDBReadyCondition, condition.InitReason, condition.PDBReadyInitMessage),
 	)
+	if instance.Spec.IsClusterMode() {
+		// slots served by the shards
+		cl.Set(condition.UnknownCondition(redisv1.RedisClusterReadyCondition, condition.InitReason, redisv1.RedisClusterReadyInitMessage))
+	} else {
+		// master elected by the sentinels
+		cl.Set(condition.UnknownCondition(redisv1.RedisMasterReadyCondition, condition.InitReason, redisv1.RedisMasterReadyInitMessage))
+	}
 
 	instance.Status.Conditions.Init(&cl)
 	instance.Status.ObservedGeneration = instance.Generation
@@ -292,6 +345,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	// all cert input checks out so report InputReady
 	instance.Status.Conditions.MarkTrue(condition.TLSInputReadyCondition, condition.InputReadyMessage)
 
+	// Password of the default user
+	if instance.Spec.Auth.Enabled {
+		hash, err := r.ensureAuthSecret(ctx, instance)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.ServiceConfigReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.ServiceConfigReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, fmt.Errorf("error creating auth secret: %w", err)
+		}
+		inputHashEnv["Auth"] = env.SetValue(hash)
+		instance.Status.AuthSecret = redis.AuthSecretName(instance)
+	} else {
+		instance.Status.AuthSecret = ""
+	}
+
 	// Redis config maps
 	err = r.generateConfigMaps(ctx, helper, instance, &inputHashEnv)
 	if err != nil {
@@ -372,6 +443,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	}
 	instance.Status.Conditions.MarkTrue(condition.CreateServiceReadyCondition, condition.CreateServiceReadyMessage)
 
+	// Connection details for the consumers, so that they don't build the URLs themselves
+	err = r.ensureConnectionSecret(ctx, helper, instance)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ServiceConfigReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.ServiceConfigReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, fmt.Errorf("error creating connection secret: %w", err)
+	}
+	instance.Status.SecretName = redis.ConnectionSecretName(instance)
+
 	//
 	// Reconstruct the state of the redis resource based on the deployment and its pods
 	//
@@ -414,14 +498,99 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		instance.Status.LastAppliedTopology = nil
 	}
 
+	// PodDisruptionBudget
+	pdbRes, err := pdb.EnsurePodDisruptionBudget(
+		ctx, helper, &instance.Spec.PodDisruptionBudget, instance.Spec.PodCount(), instance.Name, instance.Namespace, serviceLabels)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.PDBReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.PDBReadyErrorMessage,
+			err.Error()))
+		return pdbRes, err
+	} else if (pdbRes != ctrl.Result{}) {
+		return pdbRes, nil
+	}
+	instance.Status.Conditions.MarkTrue(condition.PDBReadyCondition, condition.PDBReadyMessage)
+
+	// Sentinel settings, not part of the config hash as they get applied live
+	sentinelSettings := redis.SentinelSettingsConfigMap(instance)
+	_, err = controllerutil.CreateOrPatch(ctx, r.Client, sentinelSettings, func() error {
+		sentinelSettings.Data = redis.SentinelSettingsConfigMap(instance).Data
+		return controllerutil.SetControllerReference(instance, sentinelSettings, r.Scheme)
+	})
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ServiceConfigReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.ServiceConfigReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, fmt.Errorf("error creating sentinel settings: %w", err)
+	}
+
+	// A RedisRestore seeds a Redis created after it, the first pod restores the backup when it
+	// bootstraps the cluster. The RedisRestore reports that the cluster mode can't be seeded.
+	var restore *redisv1.RedisRestore
+	var restoreTarget *redisv1.BackupTarget
+	if !instance.Spec.IsClusterMode() {
+		restore, restoreTarget, err = r.findRestore(ctx, instance)
+	}
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.DeploymentReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	if restore != nil && restoreTarget == nil {
+		// The RedisRestore is watched, it reports what it is waiting for
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			redisv1.RedisRestoreWaitingMessage,
+			restore.Name))
+		return ctrl.Result{}, nil
+	}
+
 	// Statefulset
-	ss := commonstatefulset.NewStatefulSet(redis.StatefulSet(instance, hashOfHashes, topology), 5)
+	sts := redis.StatefulSet(instance, hashOfHashes, topology)
+	if restore != nil {
+		redis.ApplyRestore(&sts.Spec.Template, *restoreTarget, restore.Status.Backup)
+		instance.Status.RestoreName = restore.Name
+	}
+	ss := commonstatefulset.NewStatefulSet(sts, 5)
 	sfres, sferr := ss.CreateOrPatch(ctx, helper)
 	if sferr != nil {
 		return sfres, sferr
 	}
 
-	if commonstatefulset.IsReady(ss.GetStatefulSet()) {
+	err = r.reconcileMetrics(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Replication topology, also while pods are not ready to report failovers. The cluster mode
+	// runs no sentinels, its nodes fail over by themselves.
+	requeue := replicationRefreshInterval
+	if !instance.Spec.IsClusterMode() {
+		requeue = r.reconcileReplication(ctx, instance)
+		requeue = min(requeue, r.reconcileSentinelSettings(ctx, instance))
+	}
+
+	rolledOut, err := r.reconcileRollout(ctx, instance, ss.GetStatefulSet())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !rolledOut {
+		requeue = min(requeue, replicationRetryInterval)
+	}
+
+	if rolledOut && commonstatefulset.IsReady(ss.GetStatefulSet()) {
 		instance.Status.Conditions.MarkTrue(condition.DeploymentReadyCondition, condition.DeploymentReadyMessage)
 	} else {
 		instance.Status.Conditions.Set(condition.FalseCondition(
@@ -430,7 +599,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 			condition.SeverityInfo,
 			condition.DeploymentReadyRunningMessage))
 		// It is OK to return success as we are watching for StatefulSet changes
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: requeue}, nil
+	}
+
+	// The cluster gets created and resharded once all nodes are up
+	if instance.Spec.IsClusterMode() {
+		clusterRequeue, err := r.reconcileCluster(ctx, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		requeue = min(requeue, clusterRequeue)
 	}
 
 	// We reached the end of the Reconcile, update the Ready condition based on
@@ -439,7 +617,489 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		instance.Status.Conditions.MarkTrue(
 			condition.ReadyCondition, condition.ReadyMessage)
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// reconcileMetrics creates the ServiceMonitor of the exporters when metrics are enabled and the
+// Prometheus operator is installed, and deletes it otherwise
+func (r *Reconciler) reconcileMetrics(ctx context.Context, instance *redisv1.Redis) error {
+	Log := r.GetLogger(ctx)
+
+	if !instance.Spec.Metrics.Enabled {
+		return monitoring.DeleteServiceMonitor(ctx, r.Client, redis.ServiceMonitorName(instance), instance)
+	}
+
+	sm := monitoring.NewServiceMonitor(redis.ServiceMonitorName(instance), instance.Namespace)
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, sm, func() error {
+		if err := redis.SetServiceMonitorSpec(sm, instance); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(instance, sm, r.Scheme)
+	})
+	if err != nil {
+		if !meta.IsNoMatchError(err) {
+			return err
+		}
+		Log.Info("ServiceMonitor CRD not installed, not creating a ServiceMonitor")
+	}
+	return nil
+}
+
+// reconcileReplication asks the sentinels for the master and the master for its replicas and
+// records them in the status. Writing the status triggers a new reconcile, so the topology only
+// gets refreshed periodically. It returns when the next refresh is due.
+func (r *Reconciler) reconcileReplication(ctx context.Context, instance *redisv1.Redis) time.Duration {
+	Log := r.GetLogger(ctx)
+
+	interval := replicationRefreshInterval
+	if !instance.Status.Conditions.IsTrue(redisv1.RedisMasterReadyCondition) {
+		interval = replicationRetryInterval
+	}
+	previous := instance.Status.Replication
+	if previous != nil {
+		if elapsed := time.Since(previous.LastUpdated.Time); elapsed < interval {
+			return interval - elapsed
+		}
+	}
+
+	replication := &redisv1.ReplicationStatus{
+		LastUpdated: metav1.Now(),
+	}
+	if previous != nil {
+		replication.LastFailoverTime = previous.LastFailoverTime
+	}
+	instance.Status.Replication = replication
+
+	running := []types.NamespacedName{}
+	for _, podName := range redis.PodNames(instance) {
+		pod := &corev1.Pod{}
+		err := r.Get(ctx, types.NamespacedName{Name: podName, Namespace: instance.Namespace}, pod)
+		if err == nil && isContainerRunning(pod, "redis") {
+			running = append(running, types.NamespacedName{Name: podName, Namespace: instance.Namespace})
+		}
+	}
+	if len(running) == 0 {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			redisv1.RedisMasterReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			redisv1.RedisMasterReadyErrorMessage,
+			"no redis pod is running"))
+		return replicationRetryInterval
+	}
+
+	// Every sentinel knows the master, ask them in turn until one answers
+	var masterHost string
+	sentinelErr := fmt.Errorf("the sentinels do not know a master")
+	for _, pod := range running {
+		output, err := r.RedisCLI(ctx, pod, redis.SentinelMasterArgs())
+		if err != nil {
+			sentinelErr = fmt.Errorf("querying the sentinel of %s: %w", pod.Name, err)
+			continue
+		}
+		if masterHost = redis.ParseSentinelMaster(output); masterHost != "" {
+			break
+		}
+	}
+	if masterHost == "" {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			redisv1.RedisMasterReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			redisv1.RedisMasterReadyErrorMessage,
+			sentinelErr.Error()))
+		return replicationRetryInterval
+	}
+	replication.Master = redis.PodNameFromHost(masterHost)
+	if previous != nil && previous.Master != "" && previous.Master != replication.Master {
+		Log.Info("Redis master changed", "from", previous.Master, "to", replication.Master)
+		replication.LastFailoverTime = &replication.LastUpdated
+	}
+
+	master := types.NamespacedName{Name: replication.Master, Namespace: instance.Namespace}
+	output, err := r.RedisCLI(ctx, master, redis.ReplicationInfoArgs())
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			redisv1.RedisMasterReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			redisv1.RedisMasterReadyErrorMessage,
+			fmt.Sprintf("master %s is not reachable: %s", replication.Master, err)))
+		return replicationRetryInterval
+	}
+	role, replicas := redis.ParseReplicationInfo(output)
+	if role != "master" {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			redisv1.RedisMasterReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			redisv1.RedisMasterReadyErrorMessage,
+			fmt.Sprintf("%s has the role %s", replication.Master, role)))
+		return replicationRetryInterval
+	}
+	replication.Replicas = replicas
+
+	output, err = r.RedisCLI(ctx, master, redis.SentinelQuorumArgs())
+	if err != nil {
+		replication.SentinelMessage = err.Error()
+	} else {
+		replication.QuorumReachable = strings.HasPrefix(output, "OK")
+		replication.SentinelMessage = output
+	}
+
+	instance.Status.Conditions.MarkTrue(redisv1.RedisMasterReadyCondition, redisv1.RedisMasterReadyMessage, replication.Master)
+	return replicationRefreshInterval
+}
+
+// reconcileRollout replaces the pods of the OnDelete statefulset that don't run its update
+// revision, one at a time and the master last. The master gets failed over to a replica before
+// its pod is deleted, so that clients only see the switch of the master and not its downtime.
+// It returns whether all pods run the update revision.
+func (r *Reconciler) reconcileRollout(ctx context.Context, instance *redisv1.Redis, sts appsv1.StatefulSet) (bool, error) {
+	Log := r.GetLogger(ctx)
+
+	pods := []corev1.Pod{}
+	for _, podName := range redis.PodNames(instance) {
+		pod := &corev1.Pod{}
+		err := r.Get(ctx, types.NamespacedName{Name: podName, Namespace: instance.Namespace}, pod)
+		if err != nil {
+			if k8s_errors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		pods = append(pods, *pod)
+	}
+
+	action, podName := redis.NextRolloutAction(&sts, pods)
+	switch action {
+	case redis.RolloutDone:
+		return true, nil
+	case redis.RolloutDelete:
+		Log.Info("Replacing pod with the current revision", "pod", podName, "revision", sts.Status.UpdateRevision)
+		pod := &corev1.Pod{}
+		pod.Name = podName
+		pod.Namespace = instance.Namespace
+		err := r.Delete(ctx, pod)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return false, err
+		}
+	case redis.RolloutFailover:
+		// The pod gets replaced once check_redis_endpoints.sh moved the master label, an
+		// ongoing failover is reported as INPROG and left alone
+		output, err := r.RedisCLI(ctx, types.NamespacedName{Name: podName, Namespace: instance.Namespace}, redis.SentinelFailoverArgs())
+		if err == nil && output != "OK" {
+			err = fmt.Errorf("%s", output)
+		}
+		if err != nil && !strings.Contains(err.Error(), "INPROG") {
+			Log.Info(fmt.Sprintf("Failing over master %s before replacing it failed, retrying in %s: %s", podName, replicationRetryInterval, err))
+			return false, nil
+		}
+		Log.Info("Handing the master role over before replacing the pod", "pod", podName)
+	}
+	return false, nil
+}
+
+// reconcileCluster runs the cluster job when the shape of the cluster changed or the cluster
+// stays unhealthy, and records the nodes of the cluster in the status. The job creates the
+// cluster, moves the slots or assigns the slots no node serves, the nodes of removed shards are
+// only deleted by the statefulset once it succeeded. It returns when the nodes should be
+// queried again.
+func (r *Reconciler) reconcileCluster(ctx context.Context, instance *redisv1.Redis) (time.Duration, error) {
+	Log := r.GetLogger(ctx)
+
+	hash, err := redis.ClusterHash(instance)
+	if err != nil {
+		return 0, err
+	}
+	if instance.Status.Hash[redis.ClusterHashName] != hash || redis.ClusterRepairDue(instance, time.Now()) {
+		done, requeue, err := r.runClusterJob(ctx, instance, hash)
+		if err != nil || !done {
+			return requeue, err
+		}
+		Log.Info("Cluster job succeeded", "shards", instance.Spec.ClusterShards(), "replicasPerShard", instance.Spec.ClusterReplicasPerShard())
+		instance.Status.Hash, _ = util.SetHash(instance.Status.Hash, redis.ClusterHashName, hash)
+		if instance.Status.Cluster == nil {
+			instance.Status.Cluster = &redisv1.ClusterStatus{}
+		}
+		instance.Status.Cluster.Shards = instance.Spec.ClusterShards()
+		instance.Status.Cluster.ReplicasPerShard = instance.Spec.ClusterReplicasPerShard()
+		// the nodes changed, query them right away
+		instance.Status.Cluster.LastUpdated = nil
+		instance.Status.Cluster.UnhealthySince = nil
+	}
+	return r.reconcileClusterNodes(ctx, instance), nil
+}
+
+// runClusterJob creates the cluster job for the given shape, replacing the job of a previous
+// shape as the pod template of a job is immutable. A failed job is kept for a while for its logs
+// and then replaced to try again, as is a job that succeeded before the cluster became unhealthy.
+// It returns whether the job succeeded and when to check again.
+func (r *Reconciler) runClusterJob(ctx context.Context, instance *redisv1.Redis, hash string) (bool, time.Duration, error) {
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: redis.ClusterJobName(instance), Namespace: instance.Namespace}, job)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return false, 0, err
+	}
+	if err == nil {
+		// The replaced job is watched, the new one gets created once it is gone
+		if !job.DeletionTimestamp.IsZero() {
+			return false, replicationRetryInterval, nil
+		}
+		replace := job.Annotations[redis.ClusterHashAnnotation] != hash
+		if cluster := instance.Status.Cluster; cluster != nil && cluster.UnhealthySince != nil &&
+			job.Status.Succeeded > 0 && job.CreationTimestamp.Before(cluster.UnhealthySince) {
+			r.GetLogger(ctx).Info("Running the cluster job again to repair the cluster", "state", cluster.State)
+			replace = true
+		}
+		if failedAt := jobFailureTime(job); failedAt != nil && !replace {
+			if wait := clusterJobRetryInterval - time.Since(failedAt.Time); wait > 0 {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					redisv1.RedisClusterReadyCondition,
+					condition.ErrorReason,
+					condition.SeverityWarning,
+					redisv1.RedisClusterReadyErrorMessage,
+					fmt.Sprintf("job %s failed, retrying in %s", job.Name, wait.Round(time.Second))))
+				return false, wait, nil
+			}
+			r.GetLogger(ctx).Info("Retrying the failed cluster job", "job", job.Name)
+			replace = true
+		}
+		if replace {
+			err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+			if err != nil && !k8s_errors.IsNotFound(err) {
+				return false, 0, err
+			}
+			return false, replicationRetryInterval, nil
+		}
+	} else {
+		job = redis.ClusterJob(instance)
+		job.Annotations = map[string]string{redis.ClusterHashAnnotation: hash}
+		if err := controllerutil.SetControllerReference(instance, job, r.Scheme); err != nil {
+			return false, 0, err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			return false, 0, err
+		}
+	}
+
+	// The job is watched, no need to requeue while it runs
+	if job.Status.Succeeded > 0 {
+		return true, 0, nil
+	}
+	instance.Status.Conditions.Set(condition.FalseCondition(
+		redisv1.RedisClusterReadyCondition,
+		condition.RequestedReason,
+		condition.SeverityInfo,
+		redisv1.RedisClusterReadyRunningMessage))
+	return false, replicationRetryInterval, nil
+}
+
+// jobFailureTime - when the job ran out of retries, nil while it did not
+func jobFailureTime(job *batchv1.Job) *metav1.Time {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return &c.LastTransitionTime
+		}
+	}
+	return nil
+}
+
+// reconcileClusterNodes asks a node of the cluster for the state of the cluster and its nodes
+// and records them in the status, periodically like the replication topology. It returns when
+// the next refresh is due.
+func (r *Reconciler) reconcileClusterNodes(ctx context.Context, instance *redisv1.Redis) time.Duration {
+	Log := r.GetLogger(ctx)
+
+	cluster := instance.Status.Cluster
+	interval := replicationRefreshInterval
+	if !instance.Status.Conditions.IsTrue(redisv1.RedisClusterReadyCondition) {
+		interval = replicationRetryInterval
+	}
+	if cluster.LastUpdated != nil {
+		if elapsed := time.Since(cluster.LastUpdated.Time); elapsed < interval {
+			return interval - elapsed
+		}
+	}
+	cluster.LastUpdated = ptr.To(metav1.Now())
+
+	var pod *types.NamespacedName
+	for _, podName := range redis.PodNames(instance) {
+		p := &corev1.Pod{}
+		err := r.Get(ctx, types.NamespacedName{Name: podName, Namespace: instance.Namespace}, p)
+		if err == nil && isContainerRunning(p, "redis") {
+			pod = &types.NamespacedName{Name: podName, Namespace: instance.Namespace}
+			break
+		}
+	}
+	if pod == nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			redisv1.RedisClusterReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			redisv1.RedisClusterReadyWaitingMessage,
+			"a running redis pod"))
+		return replicationRetryInterval
+	}
+
+	info, err := r.RedisCLI(ctx, *pod, redis.ClusterInfoArgs())
+	var output string
+	if err == nil {
+		output, err = r.RedisCLI(ctx, *pod, redis.ClusterNodesArgs())
+	}
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			redisv1.RedisClusterReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			redisv1.RedisClusterReadyErrorMessage,
+			fmt.Sprintf("querying %s: %s", pod.Name, err)))
+		return replicationRetryInterval
+	}
+	cluster.State = redis.ParseClusterState(info)
+	cluster.Nodes = redis.ParseClusterNodes(output)
+	if !redis.ClusterUnhealthy(info) {
+		cluster.UnhealthySince = nil
+	} else if cluster.UnhealthySince == nil {
+		cluster.UnhealthySince = ptr.To(metav1.Now())
+	}
+
+	if cluster.State != "ok" {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			redisv1.RedisClusterReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			redisv1.RedisClusterReadyErrorMessage,
+			fmt.Sprintf("cluster_state is %s", cluster.State)))
+		return replicationRetryInterval
+	}
+	if !instance.Status.Conditions.IsTrue(redisv1.RedisClusterReadyCondition) {
+		Log.Info("Redis cluster serves all slots", "shards", cluster.Shards)
+	}
+	instance.Status.Conditions.MarkTrue(redisv1.RedisClusterReadyCondition, redisv1.RedisClusterReadyMessage, cluster.Shards)
+	return replicationRefreshInterval
+}
+
+// reconcileSentinelSettings applies the settings with SENTINEL SET to every running sentinel
+// that did not get them yet, after a change of the settings as well as after a sentinel
+// (re)started, as it may have read a stale settings config map. It returns when to retry
+// if a sentinel could not be updated.
+func (r *Reconciler) reconcileSentinelSettings(ctx context.Context, instance *redisv1.Redis) time.Duration {
+	Log := r.GetLogger(ctx)
+
+	applied := map[string]string{}
+	for _, podName := range redis.PodNames(instance) {
+		if marker, ok := instance.Status.SentinelPods[podName]; ok {
+			applied[podName] = marker
+		}
+	}
+	instance.Status.SentinelPods = applied
+
+	for _, podName := range redis.PodNames(instance) {
+		pod := &corev1.Pod{}
+		err := r.Get(ctx, types.NamespacedName{Name: podName, Namespace: instance.Namespace}, pod)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			Log.Info(fmt.Sprintf("Could not get %s to update the sentinel settings: %s", podName, err))
+			return replicationRetryInterval
+		}
+		if err != nil || !isContainerRunning(pod, "sentinel") {
+			continue
+		}
+		marker := redis.SentinelPodMarker(pod, instance)
+		if applied[podName] == marker {
+			continue
+		}
+		output, err := r.RedisCLI(ctx, types.NamespacedName{Name: podName, Namespace: instance.Namespace}, redis.SentinelSetArgs(instance))
+		if err == nil && output != "OK" {
+			err = fmt.Errorf("%s", output)
+		}
+		if err != nil {
+			Log.Info(fmt.Sprintf("Updating the sentinel settings of %s failed, retrying in %s: %s", podName, replicationRetryInterval, err))
+			return replicationRetryInterval
+		}
+		applied[podName] = marker
+		Log.Info("Applied the sentinel settings", "pod", podName, "settings", redis.SentinelSettingsEnv(instance))
+	}
+
+	return replicationRefreshInterval
+}
+
+// findRestore returns the unfinished RedisRestore of the instance, if any, and the target of its
+// backup once the backup is known. Only an instance without statefulset gets seeded, the restore
+// stays applied to the statefulset until it finished.
+func (r *Reconciler) findRestore(
+	ctx context.Context,
+	instance *redisv1.Redis,
+) (*redisv1.RedisRestore, *redisv1.BackupTarget, error) {
+	restores := &redisv1.RedisRestoreList{}
+	if err := r.List(ctx, restores, client.InNamespace(instance.Namespace)); err != nil {
+		return nil, nil, err
+	}
+	var restore *redisv1.RedisRestore
+	for i := range restores.Items {
+		item := &restores.Items[i]
+		if item.Spec.RedisName != instance.Name || item.IsReady() || !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if item.Name == instance.Status.RestoreName {
+			restore = item
+			break
+		}
+		// the oldest one wins when several would seed the same instance
+		if restore == nil || item.CreationTimestamp.Before(&restore.CreationTimestamp) {
+			restore = item
+		}
+	}
+	if restore == nil {
+		return nil, nil, nil
+	}
+
+	if restore.Name != instance.Status.RestoreName {
+		sts := &appsv1.StatefulSet{}
+		err := r.Get(ctx, types.NamespacedName{Name: instance.Name + "-redis", Namespace: instance.Namespace}, sts)
+		if err == nil {
+			// Already running, the RedisRestore reports that it can't seed it
+			return nil, nil, nil
+		}
+		if !k8s_errors.IsNotFound(err) {
+			return nil, nil, err
+		}
+	}
+
+	if restore.Status.Backup == nil {
+		return restore, nil, nil
+	}
+	backup := &redisv1.RedisBackup{}
+	err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.BackupName, Namespace: instance.Namespace}, backup)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return restore, nil, nil
+		}
+		return nil, nil, err
+	}
+	return restore, &backup.Spec.Target, nil
+}
+
+// execRedisCLI runs redis-cli with the given arguments in the redis container of the pod and
+// returns its output
+func execRedisCLI(
+	ctx context.Context,
+	kclient kubernetes.Interface,
+	config *rest.Config,
+	pod types.NamespacedName,
+	args []string,
+) (string, error) {
+	var output string
+	err := rsh.ExecInPod(ctx, kclient, config, pod, "redis", redis.CLICommand(args...),
+		func(stdout *bytes.Buffer, stderr *bytes.Buffer) error {
+			if stderr.Len() > 0 {
+				return fmt.Errorf("%s", stderr.String())
+			}
+			output = strings.TrimSpace(stdout.String())
+			return nil
+		},
+	)
+	return output, err
 }
 
 // generateConfigMaps returns the config map resource for a redis instance
@@ -449,7 +1109,12 @@ func (r *Reconciler) generateConfigMaps(
 	instance *redisv1.Redis,
 	envVars *map[string]env.Setter,
 ) error {
-	templateParameters := make(map[string]any)
+	templateParameters := map[string]any{
+		"persistenceConfig": redis.PersistenceConfig(instance),
+		"memoryConfig":      redis.MemoryConfig(instance),
+		"extraConfig":       redis.ExtraConfig(instance),
+		"clusterMode":       instance.Spec.IsClusterMode(),
+	}
 	customData := make(map[string]string)
 
 	cms := []util.Template{
@@ -482,9 +1147,82 @@ func (r *Reconciler) generateConfigMaps(
 	return nil
 }
 
+// generatePassword generates a random password
+func generatePassword(length int) (string, error) {
+	bytes := make([]byte, length)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(bytes)[:length], nil
+}
+
+// ensureAuthSecret creates the secret holding the password of the default user and returns
+// its hash. The password gets generated once.
+func (r *Reconciler) ensureAuthSecret(
+	ctx context.Context,
+	instance *redisv1.Redis,
+) (string, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      redis.AuthSecretName(instance),
+			Namespace: instance.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, secret, func() error {
+		password := string(secret.Data[redisv1.AuthPasswordKey])
+		if password == "" {
+			var err error
+			password, err = generatePassword(32)
+			if err != nil {
+				return err
+			}
+		}
+		secret.Data = redis.AuthSecretData(password)
+		return controllerutil.SetControllerReference(instance, secret, r.Scheme)
+	})
+	if err != nil {
+		return "", err
+	}
+	return oko_secret.Hash(secret)
+}
+
+// ensureConnectionSecret creates the secret holding the sentinel and master URLs for the
+// consumers. It includes the password of the default user, if any.
+func (r *Reconciler) ensureConnectionSecret(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *redisv1.Redis,
+) error {
+	var password string
+	if instance.Status.AuthSecret != "" {
+		authSecret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: instance.Status.AuthSecret, Namespace: instance.Namespace}, authSecret)
+		if err != nil {
+			return err
+		}
+		password = string(authSecret.Data[redisv1.AuthPasswordKey])
+	}
+
+	secrets := []util.Template{{
+		Name:         redis.ConnectionSecretName(instance),
+		Namespace:    instance.Namespace,
+		Type:         util.TemplateTypeNone,
+		InstanceType: instance.Kind,
+		CustomData:   redis.ConnectionData(instance, password),
+		Labels:       map[string]string{},
+	}}
+	return oko_secret.EnsureSecrets(ctx, h, instance, secrets, nil)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.config = mgr.GetConfig()
+	if r.RedisCLI == nil {
+		r.RedisCLI = func(ctx context.Context, pod types.NamespacedName, args []string) (string, error) {
+			return execRedisCLI(ctx, r.Kclient, r.config, pod, args)
+		}
+	}
 
 	// Various CR fields need to be indexed to filter watch events
 	// for the secret changes we want to be notified of
@@ -528,10 +1266,14 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&redisv1.Redis{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&corev1.Service{}).
+		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
+		Owns(&batchv1.Job{}).
 		Watches(
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSrc),
@@ -540,9 +1282,25 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&topologyv1.Topology{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSrc),
 			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&redisv1.RedisRestore{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForRestore)).
 		Complete(r)
 }
 
+// findObjectsForRestore - returns a reconcile request for the Redis instance the RedisRestore seeds
+func (r *Reconciler) findObjectsForRestore(_ context.Context, src client.Object) []reconcile.Request {
+	restore, ok := src.(*redisv1.RedisRestore)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      restore.Spec.RedisName,
+			Namespace: restore.Namespace,
+		},
+	}}
+}
+
 // findObjectsForSrc - returns a reconcile request if the object is referenced by a Redis CR
 func (r *Reconciler) findObjectsForSrc(ctx context.Context, src client.Object) []reconcile.Request {
 	requests := []reconcile.Request{}
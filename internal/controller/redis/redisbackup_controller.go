@@ -0,0 +1,343 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	redis "github.com/openstack-k8s-operators/infra-operator/internal/redis"
+	"github.com/openstack-k8s-operators/lib-common/modules/common"
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	helper "github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+)
+
+// RedisBackupReconciler reconciles a RedisBackup object
+//
+//nolint:revive
+type RedisBackupReconciler struct {
+	client.Client
+	Kclient kubernetes.Interface
+	Scheme  *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisbackups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisbackups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisbackups/finalizers,verbs=update
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redises,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile reconciles a RedisBackup object
+func (r *RedisBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	Log := log.FromContext(ctx)
+
+	instance := &redisv1.RedisBackup{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	h, _ := helper.NewHelper(instance, r.Client, r.Kclient, r.Scheme, Log)
+
+	// Save a copy of the conditions so that we can restore the LastTransitionTime
+	// when a condition's state doesn't change
+	savedConditions := instance.Status.Conditions.DeepCopy()
+
+	// Initialize status conditions
+	cl := condition.CreateList(
+		condition.UnknownCondition(condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage),
+		condition.UnknownCondition(redisv1.RedisBackupReadyCondition, condition.InitReason, redisv1.RedisBackupReadyInitMessage),
+	)
+	instance.Status.Conditions.Init(&cl)
+	instance.Status.ObservedGeneration = instance.Generation
+
+	defer func() {
+		// Restore condition timestamps if they haven't changed
+		condition.RestoreLastTransitionTimes(&instance.Status.Conditions, savedConditions)
+
+		if instance.Status.Conditions.IsUnknown(condition.ReadyCondition) {
+			instance.Status.Conditions.Set(instance.Status.Conditions.Mirror(condition.ReadyCondition))
+		}
+		if err := h.PatchInstance(ctx, instance); err != nil {
+			Log.Error(err, "Failed to patch instance")
+		}
+	}()
+
+	// The jobs are owned by the RedisBackup and get garbage collected, the backups are kept
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileNormal(ctx, instance)
+}
+
+func (r *RedisBackupReconciler) reconcileNormal(ctx context.Context, instance *redisv1.RedisBackup) (ctrl.Result, error) {
+	Log := log.FromContext(ctx)
+
+	// The Redis instance is watched, no need to requeue while it is missing
+	instanceRedis := &redisv1.Redis{}
+	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RedisName, Namespace: instance.Namespace}, instanceRedis)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.RequestedReason, condition.SeverityInfo,
+				redisv1.RedisBackupReadyWaitingMessage, fmt.Sprintf("Redis %s", instance.Spec.RedisName)))
+			return ctrl.Result{}, nil
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisBackupReadyErrorMessage, err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	// The backup job dumps the master elected by the sentinels, the cluster mode has one per shard
+	if instanceRedis.Spec.IsClusterMode() {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisBackupReadyErrorMessage, fmt.Sprintf("Redis %s runs in cluster mode", instanceRedis.Name)))
+		return ctrl.Result{}, nil
+	}
+
+	// The credentials are provided by the user, the job could not start without them
+	if s3 := instance.Spec.Target.S3; s3 != nil {
+		err := r.Get(ctx, types.NamespacedName{Name: s3.SecretName, Namespace: instance.Namespace}, &corev1.Secret{})
+		if err != nil {
+			if k8s_errors.IsNotFound(err) {
+				instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.RequestedReason, condition.SeverityWarning,
+					redisv1.RedisBackupReadyWaitingMessage, fmt.Sprintf("secret %s", s3.SecretName)))
+				return ctrl.Result{}, nil
+			}
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+				redisv1.RedisBackupReadyErrorMessage, err.Error()))
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.ensureJobs(ctx, instance, instanceRedis); err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisBackupReadyErrorMessage, err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	// The jobs are watched, their results get collected once they finish
+	jobs := &batchv1.JobList{}
+	err = r.List(ctx, jobs, client.InNamespace(instance.Namespace), client.MatchingLabels(redis.BackupLabels(instance)))
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisBackupReadyErrorMessage, err.Error()))
+		return ctrl.Result{}, err
+	}
+	var latest, succeeded *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if latest == nil || latest.CreationTimestamp.Before(&job.CreationTimestamp) {
+			latest = job
+		}
+		if job.Status.Succeeded > 0 && job.Status.CompletionTime != nil &&
+			(succeeded == nil || succeeded.Status.CompletionTime.Before(job.Status.CompletionTime)) {
+			succeeded = job
+		}
+	}
+
+	if succeeded != nil {
+		record, err := r.backupRecord(ctx, succeeded)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+				redisv1.RedisBackupReadyErrorMessage, err.Error()))
+			return ctrl.Result{}, nil
+		}
+		if record != nil && (instance.Status.LastBackup == nil || instance.Status.LastBackup.Location != record.Location) {
+			Log.Info("Backup taken", "location", record.Location, "size", record.Size)
+			instance.Status.LastBackup = record
+		}
+	}
+
+	if latest != nil && isJobFailed(latest) {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisBackupReadyErrorMessage, fmt.Sprintf("job %s failed", latest.Name)))
+		return ctrl.Result{}, nil
+	}
+	if instance.Status.LastBackup == nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisBackupReadyCondition, condition.RequestedReason, condition.SeverityInfo,
+			redisv1.RedisBackupReadyRunningMessage))
+		return ctrl.Result{}, nil
+	}
+
+	instance.Status.Conditions.MarkTrue(redisv1.RedisBackupReadyCondition, redisv1.RedisBackupReadyMessage, instance.Status.LastBackup.Location)
+	instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+
+	return ctrl.Result{}, nil
+}
+
+// ensureJobs creates the cron job of a schedule, or the job taking a single backup. The single
+// backup job is created once, its pod template is immutable.
+func (r *RedisBackupReconciler) ensureJobs(ctx context.Context, instance *redisv1.RedisBackup, instanceRedis *redisv1.Redis) error {
+	if instance.Spec.Schedule != "" {
+		desired := redis.BackupCronJob(instance, instanceRedis)
+		cronJob := &batchv1.CronJob{
+			ObjectMeta: desired.ObjectMeta,
+		}
+		_, err := controllerutil.CreateOrPatch(ctx, r.Client, cronJob, func() error {
+			cronJob.Labels = desired.Labels
+			cronJob.Spec = desired.Spec
+			return controllerutil.SetControllerReference(instance, cronJob, r.Scheme)
+		})
+		return err
+	}
+
+	// The schedule got removed
+	cronJob := &batchv1.CronJob{}
+	err := r.Get(ctx, types.NamespacedName{Name: redis.BackupJobName(instance), Namespace: instance.Namespace}, cronJob)
+	if err == nil {
+		if err := r.Delete(ctx, cronJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !k8s_errors.IsNotFound(err) {
+			return err
+		}
+	} else if !k8s_errors.IsNotFound(err) {
+		return err
+	}
+
+	job := &batchv1.Job{}
+	err = r.Get(ctx, types.NamespacedName{Name: redis.BackupJobName(instance), Namespace: instance.Namespace}, job)
+	if err == nil || !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	job = redis.BackupJob(instance, instanceRedis)
+	if err := controllerutil.SetControllerReference(instance, job, r.Scheme); err != nil {
+		return err
+	}
+	return r.Create(ctx, job)
+}
+
+// backupRecord returns the backup reported by the succeeded pod of the job, nil if the pod is
+// already gone
+func (r *RedisBackupReconciler) backupRecord(ctx context.Context, job *batchv1.Job) (*redisv1.BackupRecord, error) {
+	pods := &corev1.PodList{}
+	err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == "backup" && status.State.Terminated != nil {
+				return redis.ParseBackupRecord(status.State.Terminated.Message)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// isJobFailed - whether the job ran out of retries
+func isJobFailed(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RedisBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// index redisName
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &redisv1.RedisBackup{}, redisNameField, func(rawObj client.Object) []string {
+		cr := rawObj.(*redisv1.RedisBackup)
+		return []string{cr.Spec.RedisName}
+	}); err != nil {
+		return err
+	}
+
+	// The jobs of a schedule are owned by the cron job, they are mapped by their labels
+	isBackupJob := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetLabels()[common.AppSelector] == redis.BackupAppLabel
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&redisv1.RedisBackup{}).
+		Owns(&batchv1.CronJob{}).
+		Watches(&batchv1.Job{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForJob),
+			builder.WithPredicates(isBackupJob)).
+		Watches(&redisv1.Redis{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForRedis),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// findObjectsForJob - returns a reconcile request for the RedisBackup the job belongs to
+func (r *RedisBackupReconciler) findObjectsForJob(_ context.Context, src client.Object) []reconcile.Request {
+	name, ok := src.GetLabels()[common.OwnerSelector]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: src.GetNamespace(),
+		},
+	}}
+}
+
+// findObjectsForRedis - returns a reconcile request for each RedisBackup of the Redis instance
+func (r *RedisBackupReconciler) findObjectsForRedis(ctx context.Context, src client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	crList := &redisv1.RedisBackupList{}
+	listOps := &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(redisNameField, src.GetName()),
+		Namespace:     src.GetNamespace(),
+	}
+	if err := r.List(ctx, crList, listOps); err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("listing RedisBackups of Redis %s - %s", src.GetName(), src.GetNamespace()))
+		return requests
+	}
+
+	for _, item := range crList.Items {
+		requests = append(requests,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.GetName(),
+					Namespace: item.GetNamespace(),
+				},
+			},
+		)
+	}
+	return requests
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	helper "github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+)
+
+// backupNameField - index of the RedisRestores by the RedisBackup they restore
+const backupNameField = ".spec.backupName"
+
+// RedisRestoreReconciler reconciles a RedisRestore object. The Redis controller applies the
+// restore to the statefulset of a new Redis instance, this one resolves the backup and reports
+// when the instance got seeded.
+//
+//nolint:revive
+type RedisRestoreReconciler struct {
+	client.Client
+	Kclient kubernetes.Interface
+	Scheme  *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisrestores,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisrestores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisrestores/finalizers,verbs=update
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisbackups,verbs=get;list;watch
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redises,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+
+// Reconcile reconciles a RedisRestore object
+func (r *RedisRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	Log := log.FromContext(ctx)
+
+	instance := &redisv1.RedisRestore{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// A finished restore stays finished, the Redis instance must not get seeded again when it
+	// loses its master later on
+	if instance.IsReady() || !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	h, _ := helper.NewHelper(instance, r.Client, r.Kclient, r.Scheme, Log)
+
+	// Save a copy of the conditions so that we can restore the LastTransitionTime
+	// when a condition's state doesn't change
+	savedConditions := instance.Status.Conditions.DeepCopy()
+
+	// Initialize status conditions
+	cl := condition.CreateList(
+		condition.UnknownCondition(condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage),
+		condition.UnknownCondition(redisv1.RedisRestoreReadyCondition, condition.InitReason, redisv1.RedisRestoreReadyInitMessage),
+	)
+	instance.Status.Conditions.Init(&cl)
+	instance.Status.ObservedGeneration = instance.Generation
+
+	defer func() {
+		// Restore condition timestamps if they haven't changed
+		condition.RestoreLastTransitionTimes(&instance.Status.Conditions, savedConditions)
+
+		if instance.Status.Conditions.IsUnknown(condition.ReadyCondition) {
+			instance.Status.Conditions.Set(instance.Status.Conditions.Mirror(condition.ReadyCondition))
+		}
+		if err := h.PatchInstance(ctx, instance); err != nil {
+			Log.Error(err, "Failed to patch instance")
+		}
+	}()
+
+	return r.reconcileNormal(ctx, instance)
+}
+
+func (r *RedisRestoreReconciler) reconcileNormal(ctx context.Context, instance *redisv1.RedisRestore) (ctrl.Result, error) {
+	Log := log.FromContext(ctx)
+
+	// The backup is resolved once, newer backups taken while the restore runs don't change it
+	if instance.Status.Backup == nil {
+		backup := &redisv1.RedisBackup{}
+		err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.BackupName, Namespace: instance.Namespace}, backup)
+		if err != nil {
+			if k8s_errors.IsNotFound(err) {
+				instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.RequestedReason, condition.SeverityInfo,
+					redisv1.RedisRestoreReadyWaitingMessage, fmt.Sprintf("RedisBackup %s", instance.Spec.BackupName)))
+				return ctrl.Result{}, nil
+			}
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+				redisv1.RedisRestoreReadyErrorMessage, err.Error()))
+			return ctrl.Result{}, err
+		}
+		if backup.Status.LastBackup == nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.RequestedReason, condition.SeverityInfo,
+				redisv1.RedisRestoreReadyWaitingMessage, fmt.Sprintf("a backup of RedisBackup %s", instance.Spec.BackupName)))
+			return ctrl.Result{}, nil
+		}
+		instance.Status.Backup = backup.Status.LastBackup.DeepCopy()
+		Log.Info("Restoring backup", "location", instance.Status.Backup.Location)
+	}
+
+	// The Redis instance is watched, the restore gets applied once it is created
+	instanceRedis := &redisv1.Redis{}
+	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RedisName, Namespace: instance.Namespace}, instanceRedis)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.RequestedReason, condition.SeverityInfo,
+				redisv1.RedisRestoreReadyWaitingMessage, fmt.Sprintf("Redis %s", instance.Spec.RedisName)))
+			return ctrl.Result{}, nil
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisRestoreReadyErrorMessage, err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	// The backup holds the keys of a single master, the shards of the cluster mode can't be seeded with it
+	if instanceRedis.Spec.IsClusterMode() {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisRestoreReadyErrorMessage, fmt.Sprintf("Redis %s runs in cluster mode", instanceRedis.Name)))
+		return ctrl.Result{}, nil
+	}
+
+	if instanceRedis.Status.RestoreName != instance.Name {
+		sts := &appsv1.StatefulSet{}
+		err := r.Get(ctx, types.NamespacedName{Name: instanceRedis.Name + "-redis", Namespace: instance.Namespace}, sts)
+		if err == nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+				redisv1.RedisRestoreRunningRedisMessage, instanceRedis.Name))
+			return ctrl.Result{}, nil
+		}
+		if !k8s_errors.IsNotFound(err) {
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+				redisv1.RedisRestoreReadyErrorMessage, err.Error()))
+			return ctrl.Result{}, err
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.RequestedReason, condition.SeverityInfo,
+			redisv1.RedisRestoreReadyWaitingMessage, fmt.Sprintf("Redis %s to create its pods", instanceRedis.Name)))
+		return ctrl.Result{}, nil
+	}
+
+	// The first pod seeds the cluster before the sentinels elect it as master
+	if !instanceRedis.Status.Conditions.IsTrue(redisv1.RedisMasterReadyCondition) {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisRestoreReadyCondition, condition.RequestedReason, condition.SeverityInfo,
+			redisv1.RedisRestoreReadyRunningMessage))
+		return ctrl.Result{}, nil
+	}
+
+	Log.Info("Restored backup", "redis", instanceRedis.Name, "location", instance.Status.Backup.Location)
+	instance.Status.Conditions.MarkTrue(redisv1.RedisRestoreReadyCondition, redisv1.RedisRestoreReadyMessage,
+		instanceRedis.Name, instance.Status.Backup.Location)
+	instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RedisRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// index redisName
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &redisv1.RedisRestore{}, redisNameField, func(rawObj client.Object) []string {
+		cr := rawObj.(*redisv1.RedisRestore)
+		return []string{cr.Spec.RedisName}
+	}); err != nil {
+		return err
+	}
+	// index backupName
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &redisv1.RedisRestore{}, backupNameField, func(rawObj client.Object) []string {
+		cr := rawObj.(*redisv1.RedisRestore)
+		return []string{cr.Spec.BackupName}
+	}); err != nil {
+		return err
+	}
+
+	// The status of the Redis instance reports the restore and the master election
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&redisv1.RedisRestore{}).
+		Watches(&redisv1.Redis{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, src client.Object) []reconcile.Request {
+				return r.findObjectsForField(ctx, redisNameField, src)
+			})).
+		Watches(&redisv1.RedisBackup{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, src client.Object) []reconcile.Request {
+				return r.findObjectsForField(ctx, backupNameField, src)
+			})).
+		Complete(r)
+}
+
+// findObjectsForField - returns a reconcile request for each RedisRestore referencing the object
+// in the given field
+func (r *RedisRestoreReconciler) findObjectsForField(ctx context.Context, field string, src client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	crList := &redisv1.RedisRestoreList{}
+	listOps := &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(field, src.GetName()),
+		Namespace:     src.GetNamespace(),
+	}
+	if err := r.List(ctx, crList, listOps); err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("listing RedisRestores for field %s: %s - %s", field, src.GetName(), src.GetNamespace()))
+		return requests
+	}
+
+	for _, item := range crList.Items {
+		requests = append(requests,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.GetName(),
+					Namespace: item.GetNamespace(),
+				},
+			},
+		)
+	}
+	return requests
+}
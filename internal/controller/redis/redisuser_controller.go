@@ -0,0 +1,409 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	redis "github.com/openstack-k8s-operators/infra-operator/internal/redis"
+	"github.com/openstack-k8s-operators/lib-common/modules/common"
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	helper "github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	oko_secret "github.com/openstack-k8s-operators/lib-common/modules/common/secret"
+)
+
+// userFinalizer is the controller-level finalizer for RedisUser resources, the ACL user
+// gets deleted from the redis servers before the RedisUser goes away
+const userFinalizer = "redisuser.openstack.org/finalizer"
+
+// redisNameField - index of the RedisUsers by the Redis instance they belong to
+const redisNameField = ".spec.redisName"
+
+// RedisUserReconciler reconciles a RedisUser object
+//
+//nolint:revive
+type RedisUserReconciler struct {
+	client.Client
+	Kclient kubernetes.Interface
+	config  *rest.Config
+	Scheme  *runtime.Scheme
+	// RedisCLI - runs redis-cli with the given arguments in the redis container of a pod and
+	// returns its output, defaults to an exec into the pod
+	RedisCLI func(ctx context.Context, pod types.NamespacedName, args []string) (string, error)
+}
+
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisusers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisusers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redisusers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=redis.openstack.org,resources=redises,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile reconciles a RedisUser object
+func (r *RedisUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	Log := log.FromContext(ctx)
+
+	instance := &redisv1.RedisUser{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	h, _ := helper.NewHelper(instance, r.Client, r.Kclient, r.Scheme, Log)
+
+	// Save a copy of the conditions so that we can restore the LastTransitionTime
+	// when a condition's state doesn't change
+	savedConditions := instance.Status.Conditions.DeepCopy()
+
+	// Initialize status conditions
+	cl := condition.CreateList(
+		condition.UnknownCondition(condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage),
+		condition.UnknownCondition(redisv1.RedisUserReadyCondition, condition.InitReason, redisv1.RedisUserReadyInitMessage),
+	)
+	instance.Status.Conditions.Init(&cl)
+	instance.Status.ObservedGeneration = instance.Generation
+
+	defer func() {
+		// Restore condition timestamps if they haven't changed
+		condition.RestoreLastTransitionTimes(&instance.Status.Conditions, savedConditions)
+
+		if instance.Status.Conditions.IsUnknown(condition.ReadyCondition) {
+			instance.Status.Conditions.Set(instance.Status.Conditions.Mirror(condition.ReadyCondition))
+		}
+		if err := h.PatchInstance(ctx, instance); err != nil {
+			Log.Error(err, "Failed to patch instance")
+		}
+	}()
+
+	// Handle deletion
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, instance)
+	}
+
+	// Add finalizer if not being deleted
+	if controllerutil.AddFinalizer(instance, userFinalizer) {
+		// Finalizer was added, update will trigger reconcile
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileNormal(ctx, instance, h)
+}
+
+func (r *RedisUserReconciler) reconcileNormal(ctx context.Context, instance *redisv1.RedisUser, h *helper.Helper) (ctrl.Result, error) {
+	Log := log.FromContext(ctx)
+
+	username := instance.GetUsername()
+	if username == "default" {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisUserReadyErrorMessage, "the default user is managed by the Redis instance"))
+		return ctrl.Result{}, nil
+	}
+
+	// The Redis instance is watched, no need to requeue while it is missing
+	instanceRedis := &redisv1.Redis{}
+	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RedisName, Namespace: instance.Namespace}, instanceRedis)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.RequestedReason, condition.SeverityInfo,
+				redisv1.RedisUserReadyWaitingMessage, fmt.Sprintf("Redis %s", instance.Spec.RedisName)))
+			return ctrl.Result{}, nil
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisUserReadyErrorMessage, err.Error()))
+		return ctrl.Result{}, err
+	}
+	if !instanceRedis.Spec.Auth.Enabled {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisUserAuthDisabledMessage, instanceRedis.Name))
+		return ctrl.Result{}, nil
+	}
+
+	// The username changed, remove the previous ACL user
+	if instance.Status.Username != "" && instance.Status.Username != username {
+		if _, err := r.runOnPods(ctx, instanceRedis, redis.ACLDelUserArgs(instance.Status.Username), nil, nil); err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+				redisv1.RedisUserReadyErrorMessage, err.Error()))
+			return ctrl.Result{}, err
+		}
+		Log.Info("Deleted the previous ACL user", "user", instance.Status.Username)
+		instance.Status.Username = ""
+		instance.Status.AppliedPods = nil
+	}
+
+	// Get or generate the password, it is kept when the username changes
+	secretName := redis.UserSecretName(instance)
+	var password string
+	userSecret, _, err := oko_secret.GetSecret(ctx, h, secretName, instance.Namespace)
+	if err == nil {
+		password = string(userSecret.Data[redisv1.RedisUserPasswordKey])
+	} else if !k8s_errors.IsNotFound(err) {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisUserReadyErrorMessage, err.Error()))
+		return ctrl.Result{}, err
+	}
+	if password == "" {
+		password, err = generatePassword(32)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+				redisv1.RedisUserReadyErrorMessage, err.Error()))
+			return ctrl.Result{}, err
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: instance.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Data = map[string][]byte{
+			redisv1.RedisUserUsernameKey: []byte(username),
+			redisv1.RedisUserPasswordKey: []byte(password),
+			redisv1.RedisUserURLKey:      []byte(redis.ConnectionURL(instanceRedis, username, password)),
+		}
+		return controllerutil.SetControllerReference(instance, secret, r.Scheme)
+	})
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisUserReadyErrorMessage, err.Error()))
+		return ctrl.Result{}, err
+	}
+	instance.Status.SecretName = secretName
+
+	// ACL users are not replicated, they get set on every redis server. SETUSER resets the
+	// user first, it only runs again on pods that (re)started since or with changed rules.
+	applied := map[string]string{}
+	for _, podName := range redis.PodNames(instanceRedis) {
+		if marker, ok := instance.Status.AppliedPods[podName]; ok {
+			applied[podName] = marker
+		}
+	}
+	instance.Status.AppliedPods = applied
+	pending, err := r.runOnPods(ctx, instanceRedis, redis.ACLSetUserArgs(instance, password), func(output string) error {
+		if output != "OK" {
+			return fmt.Errorf("ACL SETUSER %s failed: %s", username, output)
+		}
+		return nil
+	}, applied)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.ErrorReason, condition.SeverityWarning,
+			redisv1.RedisUserReadyErrorMessage, err.Error()))
+		return ctrl.Result{}, err
+	}
+	instance.Status.Username = username
+	if len(pending) > 0 {
+		// The redis pods are watched, the user gets applied once they are running
+		instance.Status.Conditions.Set(condition.FalseCondition(redisv1.RedisUserReadyCondition, condition.RequestedReason, condition.SeverityInfo,
+			redisv1.RedisUserReadyWaitingMessage, fmt.Sprintf("pods %s", strings.Join(pending, ", "))))
+		return ctrl.Result{}, nil
+	}
+
+	instance.Status.Conditions.MarkTrue(redisv1.RedisUserReadyCondition, redisv1.RedisUserReadyMessage)
+	instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+
+	return ctrl.Result{}, nil
+}
+
+func (r *RedisUserReconciler) reconcileDelete(ctx context.Context, instance *redisv1.RedisUser) (ctrl.Result, error) {
+	Log := log.FromContext(ctx)
+
+	username := instance.Status.Username
+	if username == "" {
+		// The user was never applied
+		controllerutil.RemoveFinalizer(instance, userFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	instanceRedis := &redisv1.Redis{}
+	err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.RedisName, Namespace: instance.Namespace}, instanceRedis)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if k8s_errors.IsNotFound(err) || !instanceRedis.DeletionTimestamp.IsZero() {
+		// Redis doesn't exist or is being deleted - nothing to clean up
+		controllerutil.RemoveFinalizer(instance, userFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	// Pods that are not running lose the user anyway when they start
+	if _, err := r.runOnPods(ctx, instanceRedis, redis.ACLDelUserArgs(username), nil, nil); err != nil {
+		Log.Error(err, "Failed to delete the ACL user, will retry", "user", username)
+		return ctrl.Result{}, err
+	}
+	Log.Info("Deleted the ACL user", "user", username)
+
+	// The user secret is owned by the RedisUser and gets garbage collected
+	controllerutil.RemoveFinalizer(instance, userFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// runOnPods runs redis-cli with the given arguments in every running redis pod of the instance
+// and checks the output with the given function, if any. It returns the pods that are not running.
+// With applied, pods whose redis.UserPodMarker is unchanged are skipped and the others recorded.
+func (r *RedisUserReconciler) runOnPods(
+	ctx context.Context,
+	instance *redisv1.Redis,
+	args []string,
+	check func(output string) error,
+	applied map[string]string,
+) ([]string, error) {
+	pending := []string{}
+	for _, podName := range redis.PodNames(instance) {
+		pod := &corev1.Pod{}
+		err := r.Get(ctx, types.NamespacedName{Name: podName, Namespace: instance.Namespace}, pod)
+		if err != nil {
+			if k8s_errors.IsNotFound(err) {
+				pending = append(pending, podName)
+				continue
+			}
+			return nil, err
+		}
+		if !isContainerRunning(pod, "redis") {
+			pending = append(pending, podName)
+			continue
+		}
+		marker := redis.UserPodMarker(pod, args)
+		if applied != nil && applied[podName] == marker {
+			continue
+		}
+		output, err := r.RedisCLI(ctx, types.NamespacedName{Name: podName, Namespace: instance.Namespace}, args)
+		if err != nil {
+			return nil, fmt.Errorf("running %s in %s: %w", args[0]+" "+args[1], podName, err)
+		}
+		if check != nil {
+			if err := check(output); err != nil {
+				return nil, fmt.Errorf("%s: %w", podName, err)
+			}
+		}
+		if applied != nil {
+			applied[podName] = marker
+		}
+	}
+	return pending, nil
+}
+
+// isContainerRunning - whether the given container of the pod is running
+func isContainerRunning(pod *corev1.Pod, container string) bool {
+	if !pod.DeletionTimestamp.IsZero() {
+		return false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RedisUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.config = mgr.GetConfig()
+	if r.RedisCLI == nil {
+		r.RedisCLI = func(ctx context.Context, pod types.NamespacedName, args []string) (string, error) {
+			return execRedisCLI(ctx, r.Kclient, r.config, pod, args)
+		}
+	}
+
+	// index redisName
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &redisv1.RedisUser{}, redisNameField, func(rawObj client.Object) []string {
+		cr := rawObj.(*redisv1.RedisUser)
+		return []string{cr.Spec.RedisName}
+	}); err != nil {
+		return err
+	}
+
+	// Only the redis pods are relevant, their restarts drop the ACL users
+	isRedisPod := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetLabels()[common.AppSelector] == "redis"
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&redisv1.RedisUser{}).
+		Owns(&corev1.Secret{}).
+		Watches(&redisv1.Redis{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForRedis),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForPod),
+			builder.WithPredicates(isRedisPod)).
+		Complete(r)
+}
+
+// findObjectsForRedis - returns a reconcile request for each RedisUser of the Redis instance
+func (r *RedisUserReconciler) findObjectsForRedis(ctx context.Context, src client.Object) []reconcile.Request {
+	return r.findObjectsForRedisName(ctx, src.GetName(), src.GetNamespace())
+}
+
+// findObjectsForPod - returns a reconcile request for each RedisUser of the Redis instance the pod belongs to
+func (r *RedisUserReconciler) findObjectsForPod(ctx context.Context, src client.Object) []reconcile.Request {
+	redisName, ok := src.GetLabels()[common.OwnerSelector]
+	if !ok {
+		return nil
+	}
+	return r.findObjectsForRedisName(ctx, redisName, src.GetNamespace())
+}
+
+func (r *RedisUserReconciler) findObjectsForRedisName(ctx context.Context, name string, namespace string) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	crList := &redisv1.RedisUserList{}
+	listOps := &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(redisNameField, name),
+		Namespace:     namespace,
+	}
+	if err := r.List(ctx, crList, listOps); err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("listing RedisUsers of Redis %s - %s", name, namespace))
+		return requests
+	}
+
+	for _, item := range crList.Items {
+		requests = append(requests,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.GetName(),
+					Namespace: item.GetNamespace(),
+				},
+			},
+		)
+	}
+	return requests
+}
@@ -0,0 +1,22 @@
+package memcached
+
+import (
+	"fmt"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+)
+
+// AuthSecretName returns the name of the secret holding the SASL credentials
+func AuthSecretName(m *memcachedv1.Memcached) string {
+	return m.Name + memcachedv1.AuthSecretSuffix
+}
+
+// AuthSecretData returns the content of the auth secret, the SASL password
+// database memcached reads has one username:password line per user
+func AuthSecretData(username, password string) map[string][]byte {
+	return map[string][]byte{
+		memcachedv1.AuthUsernameKey: []byte(username),
+		memcachedv1.AuthPasswordKey: []byte(password),
+		MemcachedSASLDBKey:          fmt.Appendf(nil, "%s:%s\n", username, password),
+	}
+}
@@ -0,0 +1,54 @@
+package memcached
+
+import (
+	"math"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	"k8s.io/utils/ptr"
+)
+
+// autoscalingTolerance - deviation from the target memory utilization which does not
+// change the replicas, the same as the default of the HorizontalPodAutoscaler
+const autoscalingTolerance = 0.1
+
+// Replicas returns the number of replicas to serve. With autoscaling this is the last
+// decision of the autoscaler, or spec.replicas before the first one, within the bounds.
+func Replicas(m *memcachedv1.Memcached) int32 {
+	replicas := ptr.Deref(m.Spec.Replicas, 1)
+	as := m.Spec.Autoscaling
+	if as == nil {
+		return replicas
+	}
+	if m.Status.Autoscaling != nil && m.Status.Autoscaling.DesiredReplicas > 0 {
+		replicas = m.Status.Autoscaling.DesiredReplicas
+	}
+	return min(max(replicas, as.MinReplicas), as.MaxReplicas)
+}
+
+// MemoryUtilization returns the percentage of the cache memory in use
+func (s *ExporterStats) MemoryUtilization() int32 {
+	if s.LimitBytes == 0 {
+		return 0
+	}
+	return int32(s.CurrentBytes * 100 / s.LimitBytes)
+}
+
+// DesiredReplicas returns the number of replicas which brings the memory utilization to
+// its target. Evictions above the target rate per replica add a replica even when the
+// memory utilization looks fine, e.g. because of slab imbalance.
+func DesiredReplicas(
+	as *memcachedv1.AutoscalingSection,
+	current int32,
+	utilization int32,
+	evictionRate float64,
+) int32 {
+	desired := current
+	ratio := float64(utilization) / float64(as.TargetMemoryUtilization)
+	if math.Abs(ratio-1) > autoscalingTolerance {
+		desired = int32(math.Ceil(float64(current) * ratio))
+	}
+	if as.TargetEvictionRate != nil && evictionRate > float64(*as.TargetEvictionRate) && desired <= current {
+		desired = current + 1
+	}
+	return min(max(desired, as.MinReplicas), as.MaxReplicas)
+}
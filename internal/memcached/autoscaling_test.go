@@ -0,0 +1,56 @@
+package memcached
+
+import (
+	"testing"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	"k8s.io/utils/ptr"
+)
+
+func TestDesiredReplicas(t *testing.T) {
+	as := &memcachedv1.AutoscalingSection{
+		MinReplicas:             2,
+		MaxReplicas:             6,
+		TargetMemoryUtilization: 80,
+		TargetEvictionRate:      ptr.To[int32](10),
+	}
+	tests := []struct {
+		name         string
+		current      int32
+		utilization  int32
+		evictionRate float64
+		want         int32
+	}{
+		{"within tolerance", 3, 85, 0, 3},
+		{"memory pressure", 3, 100, 0, 4},
+		{"low utilization", 4, 20, 0, 2},
+		{"bounded by maxReplicas", 5, 100, 0, 6},
+		{"evictions above target", 3, 80, 12.5, 4},
+		{"evictions below target", 3, 80, 5, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DesiredReplicas(as, tt.current, tt.utilization, tt.evictionRate); got != tt.want {
+				t.Errorf("DesiredReplicas() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplicas(t *testing.T) {
+	m := &memcachedv1.Memcached{}
+	m.Spec.Replicas = ptr.To[int32](1)
+	if got := Replicas(m); got != 1 {
+		t.Errorf("Replicas() without autoscaling = %d, want 1", got)
+	}
+
+	m.Spec.Autoscaling = &memcachedv1.AutoscalingSection{MinReplicas: 2, MaxReplicas: 4}
+	if got := Replicas(m); got != 2 {
+		t.Errorf("Replicas() before the first decision = %d, want 2", got)
+	}
+
+	m.Status.Autoscaling = &memcachedv1.AutoscalingStatus{DesiredReplicas: 3}
+	if got := Replicas(m); got != 3 {
+		t.Errorf("Replicas() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,62 @@
+package memcached
+
+import (
+	"fmt"
+	"path"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+)
+
+const (
+	// CertHashName - key of the hash of the reloadable certificates in the status
+	CertHashName = "certs"
+	// CertReloadMountDir - the TLS secret mounted without subPath, so that the
+	// kubelet updates the files when the certificate gets renewed
+	CertReloadMountDir = "/var/lib/config-data/tls-reload"
+	// CertReloadNotPropagated - exit code of the reload command while the
+	// mounted secret does not contain the renewed certificate yet
+	CertReloadNotPropagated = 2
+)
+
+// CertReloadSupported - memcached loads a renewed certificate with the
+// refresh_certs text command, which is not available when only the binary
+// protocol is enabled for SASL authentication
+func CertReloadSupported(m *memcachedv1.Memcached) bool {
+	return m.Spec.TLS.Enabled() && !m.Spec.Auth.Enabled
+}
+
+// CertPath returns where memcached reads its certificate from. Without reload
+// support this is the copy kolla makes at startup, otherwise the non-subPath
+// mount, so that refresh_certs picks up the renewed file the kubelet wrote there.
+func CertPath(m *memcachedv1.Memcached) string {
+	if CertReloadSupported(m) {
+		return path.Join(CertReloadMountDir, tls.CertKey)
+	}
+	return "/etc/pki/tls/certs/memcached.crt"
+}
+
+// KeyPath returns where memcached reads its private key from, see CertPath
+func KeyPath(m *memcachedv1.Memcached) string {
+	if CertReloadSupported(m) {
+		return path.Join(CertReloadMountDir, tls.PrivateKey)
+	}
+	return "/etc/pki/tls/private/memcached.key"
+}
+
+// CertReloadCommand returns the command which waits for the renewed
+// certificate and key to show up in CertReloadMountDir and then sends
+// refresh_certs over the localhost listener. certSum and keySum are the
+// sha256 sums of the renewed files.
+func CertReloadCommand(certSum string, keySum string) []string {
+	script := fmt.Sprintf(`set -e
+cd %[1]s
+printf '%%s  %[2]s\n%%s  %[3]s\n' %[4]s %[5]s | sha256sum --check --status || exit %[6]d
+exec 3<>/dev/tcp/127.0.0.1/%[7]d
+printf 'refresh_certs\r\n' >&3
+read -t 5 -r reply <&3
+[[ "$reply" == OK* ]] || { echo "refresh_certs failed: $reply" >&2; exit 1; }
+`, CertReloadMountDir, tls.CertKey, tls.PrivateKey, certSum, keySum, CertReloadNotPropagated, MemcachedPort)
+
+	return []string{"/bin/bash", "-c", script}
+}
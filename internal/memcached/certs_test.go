@@ -0,0 +1,71 @@
+package memcached
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// reloadableMount returns the mount the file is read from when the kubelet
+// updates it in place, which is never the case for subPath mounts
+func reloadableMount(mounts []corev1.VolumeMount, file string) *corev1.VolumeMount {
+	for i, vm := range mounts {
+		if vm.SubPath == "" && path.Dir(file) == vm.MountPath {
+			return &mounts[i]
+		}
+	}
+	return nil
+}
+
+func TestCertReload(t *testing.T) {
+	m := &memcachedv1.Memcached{}
+	m.Name = "memcached"
+	m.Spec.Replicas = ptr.To[int32](1)
+	m.Spec.TLS.SecretName = ptr.To("cert-memcached-svc")
+
+	sfs := StatefulSet(m, "hash", nil)
+	mounts := sfs.Spec.Template.Spec.Containers[0].VolumeMounts
+	for _, file := range []string{CertPath(m), KeyPath(m)} {
+		vm := reloadableMount(mounts, file)
+		if vm == nil {
+			t.Fatalf("%s is not on a mount the kubelet updates", file)
+		}
+		if vm.Name != MemcachedCertPrefix+"-tls-certs" {
+			t.Errorf("%s is read from volume %s", file, vm.Name)
+		}
+	}
+	if CertPath(m) != path.Join(CertReloadMountDir, tls.CertKey) || KeyPath(m) != path.Join(CertReloadMountDir, tls.PrivateKey) {
+		t.Errorf("memcached reads %s and %s which the reload command does not check", CertPath(m), KeyPath(m))
+	}
+	sc := sfs.Spec.Template.Spec.SecurityContext
+	if sc == nil || sc.FSGroup == nil || *sc.FSGroup != MemcachedUID {
+		t.Errorf("the mounted certificate is not readable for the memcached user")
+	}
+
+	script := CertReloadCommand("certsum", "keysum")[2]
+	if !strings.Contains(script, "cd "+CertReloadMountDir+"\n") {
+		t.Errorf("reload command does not check the files in %s", CertReloadMountDir)
+	}
+	for _, vm := range mounts {
+		if vm.SubPath != "" && strings.Contains(script, vm.MountPath) {
+			t.Errorf("reload command writes to the read-only subPath mount %s", vm.MountPath)
+		}
+	}
+	if strings.Contains(script, "cp ") {
+		t.Errorf("reload command must only send refresh_certs")
+	}
+
+	m.Spec.Auth.Enabled = true
+	if CertPath(m) != "/etc/pki/tls/certs/memcached.crt" || KeyPath(m) != "/etc/pki/tls/private/memcached.key" {
+		t.Errorf("without reload support memcached reads %s and %s", CertPath(m), KeyPath(m))
+	}
+	sfs = StatefulSet(m, "hash", nil)
+	if reloadableMount(sfs.Spec.Template.Spec.Containers[0].VolumeMounts, path.Join(CertReloadMountDir, tls.CertKey)) != nil {
+		t.Errorf("reload mount must not be added with SASL authentication")
+	}
+}
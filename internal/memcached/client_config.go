@@ -0,0 +1,51 @@
+package memcached
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+)
+
+// ClientConfigSecretName returns the name of the secret holding the client configuration
+func ClientConfigSecretName(m *memcachedv1.Memcached) string {
+	return m.Name + memcachedv1.ClientConfigSecretSuffix
+}
+
+// OsloCacheConfig renders the oslo.cache [cache] section for the client configuration.
+// With SASL the credentials from the auth secret get included.
+func OsloCacheConfig(config memcachedv1.MemcachedClientConfig, username, password string) string {
+	var b strings.Builder
+	b.WriteString("[cache]\n")
+	b.WriteString("enabled = true\n")
+	fmt.Fprintf(&b, "backend = %s\n", config.Backend)
+	fmt.Fprintf(&b, "memcache_servers = %s\n", strings.Join(config.Servers, ","))
+	if config.TLS.Enabled {
+		b.WriteString("tls_enabled = true\n")
+		fmt.Fprintf(&b, "tls_cafile = %s\n", config.TLS.CAFile)
+		if config.TLS.CertFile != "" {
+			fmt.Fprintf(&b, "tls_certfile = %s\n", config.TLS.CertFile)
+			fmt.Fprintf(&b, "tls_keyfile = %s\n", config.TLS.KeyFile)
+		}
+	}
+	if config.Auth.Enabled {
+		b.WriteString("memcache_sasl_enabled = true\n")
+		fmt.Fprintf(&b, "memcache_username = %s\n", username)
+		fmt.Fprintf(&b, "memcache_password = %s\n", password)
+	}
+	return b.String()
+}
+
+// ClientConfigData returns the content of the client config secret
+func ClientConfigData(m *memcachedv1.Memcached, username, password string) (map[string]string, error) {
+	config := m.GetMemcachedClientConfig()
+	descriptor, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		memcachedv1.ClientConfigOsloCacheKey: OsloCacheConfig(config, username, password),
+		memcachedv1.ClientConfigJSONKey:      string(descriptor),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package memcached
+
+import (
+	"strings"
+	"testing"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+)
+
+func TestOsloCacheConfig(t *testing.T) {
+	m := &memcachedv1.Memcached{}
+	m.Status.ServerList = []string{"memcached-0.memcached.openstack.svc:11211"}
+	m.Status.ServerListWithInet = []string{"inet:[memcached-0.memcached.openstack.svc]:11211"}
+
+	got := OsloCacheConfig(m.GetMemcachedClientConfig(), "", "")
+	want := "[cache]\n" +
+		"enabled = true\n" +
+		"backend = dogpile.cache.memcached\n" +
+		"memcache_servers = inet:[memcached-0.memcached.openstack.svc]:11211\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	m.Status.TLSSupport = true
+	m.Status.AuthSecret = "memcached-auth"
+	got = OsloCacheConfig(m.GetMemcachedClientConfig(), "cache", "secret")
+	for _, line := range []string{
+		"backend = oslo_cache.memcache_pool\n",
+		"memcache_servers = memcached-0.memcached.openstack.svc:11211\n",
+		"tls_enabled = true\n",
+		"tls_cafile = /etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem\n",
+		"memcache_sasl_enabled = true\n",
+		"memcache_username = cache\n",
+		"memcache_password = secret\n",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("%q not found in %q", line, got)
+		}
+	}
+}
@@ -21,7 +21,11 @@ const (
 	MemcachedPort int32 = 11211
 	// MemcachedTLSPort -
 	MemcachedTLSPort int32 = 11212
+	// MemcachedMetricsPort - port of the memcached_exporter sidecar
+	MemcachedMetricsPort int32 = 9150
 	// MemcachedUID -
 	// https://github.com/openstack/kolla/blob/master/kolla/common/users.py
 	MemcachedUID int64 = 42457
+	// MemcachedSASLDBKey - key of the SASL password database in the auth secret
+	MemcachedSASLDBKey = "memcached-sasl-db"
 )
@@ -0,0 +1,191 @@
+package memcached
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+)
+
+// exporterContainer returns the memcached_exporter sidecar. With TLS enabled it
+// connects to the TLS port using the service certificate as client certificate.
+func exporterContainer(m *memcachedv1.Memcached) corev1.Container {
+	args := []string{
+		fmt.Sprintf("--web.listen-address=:%d", MemcachedMetricsPort),
+	}
+	volumeMounts := []corev1.VolumeMount{}
+
+	if m.Spec.TLS.Enabled() {
+		svc := tls.Service{
+			SecretName: *m.Spec.TLS.SecretName,
+		}
+		args = append(args,
+			fmt.Sprintf("--memcached.address=127.0.0.1:%d", MemcachedTLSPort),
+			"--memcached.tls.enable",
+			fmt.Sprintf("--memcached.tls.cert-file=%s/%s.crt", tls.DefaultCertMountDir, MemcachedCertPrefix),
+			fmt.Sprintf("--memcached.tls.key-file=%s/%s.key", tls.DefaultKeyMountDir, MemcachedCertPrefix),
+			fmt.Sprintf("--memcached.tls.server-name=%s.%s.svc", m.Name, m.Namespace),
+		)
+		volumeMounts = append(volumeMounts, svc.CreateVolumeMounts(MemcachedCertPrefix)...)
+		if m.Spec.TLS.CaBundleSecretName != "" {
+			args = append(args, "--memcached.tls.ca-file="+tls.DownstreamTLSCABundlePath)
+			volumeMounts = append(volumeMounts, m.Spec.TLS.CreateVolumeMounts(nil)...)
+		}
+	} else {
+		args = append(args, fmt.Sprintf("--memcached.address=127.0.0.1:%d", MemcachedPort))
+	}
+
+	return corev1.Container{
+		Image: m.Spec.ExporterImage,
+		Name:  "memcached-exporter",
+		Args:  args,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:  ptr.To(MemcachedUID),
+			RunAsGroup: ptr.To(MemcachedUID),
+		},
+		VolumeMounts: volumeMounts,
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: MemcachedMetricsPort,
+			Name:          "metrics",
+		}},
+	}
+}
+
+// SetServiceMonitorSpec sets the spec of the ServiceMonitor scraping the metrics
+// port of the headless service of the Memcached
+func SetServiceMonitorSpec(sm *unstructured.Unstructured, m *memcachedv1.Memcached) error {
+	spec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				"cr":  m.Name,
+				"app": m.Name,
+			},
+		},
+		"endpoints": []interface{}{
+			map[string]interface{}{
+				"port":     "metrics",
+				"interval": m.Spec.Metrics.ScrapeInterval,
+			},
+		},
+	}
+	return unstructured.SetNestedMap(sm.Object, spec, "spec")
+}
+
+// ExporterStats holds the counters of a memcached_exporter needed for the status summary
+// and the autoscaler
+type ExporterStats struct {
+	GetHits            int64
+	GetMisses          int64
+	Evictions          int64
+	CurrentConnections int64
+	MaxConnections     int64
+	CurrentBytes       int64
+	LimitBytes         int64
+}
+
+// Add sums the counters of other into s
+func (s *ExporterStats) Add(other ExporterStats) {
+	s.GetHits += other.GetHits
+	s.GetMisses += other.GetMisses
+	s.Evictions += other.Evictions
+	s.CurrentConnections += other.CurrentConnections
+	s.MaxConnections += other.MaxConnections
+	s.CurrentBytes += other.CurrentBytes
+	s.LimitBytes += other.LimitBytes
+}
+
+// HitRatio returns the percentage of get commands which hit, empty without gets
+func (s *ExporterStats) HitRatio() string {
+	gets := s.GetHits + s.GetMisses
+	if gets == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(s.GetHits)*100/float64(gets), 'f', 1, 64) + "%"
+}
+
+// ParseExporterMetrics reads the counters of ExporterStats from the Prometheus text
+// format served by memcached_exporter
+func ParseExporterMetrics(r io.Reader) (ExporterStats, error) {
+	stats := ExporterStats{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return stats, fmt.Errorf("invalid value in metric %q: %w", line, err)
+		}
+
+		name, labels, _ := strings.Cut(fields[0], "{")
+		switch name {
+		case "memcached_commands_total":
+			if !strings.Contains(labels, `command="get"`) {
+				continue
+			}
+			if strings.Contains(labels, `status="hit"`) {
+				stats.GetHits += int64(value)
+			} else if strings.Contains(labels, `status="miss"`) {
+				stats.GetMisses += int64(value)
+			}
+		case "memcached_items_evicted_total":
+			stats.Evictions += int64(value)
+		case "memcached_current_connections":
+			stats.CurrentConnections += int64(value)
+		case "memcached_max_connections":
+			stats.MaxConnections += int64(value)
+		case "memcached_current_bytes":
+			stats.CurrentBytes += int64(value)
+		case "memcached_limit_bytes":
+			stats.LimitBytes += int64(value)
+		}
+	}
+	return stats, scanner.Err()
+}
+
+// Scraper returns the stats served by the exporter at url
+type Scraper func(ctx context.Context, url string) (ExporterStats, error)
+
+// DefaultScraper fetches the stats from the exporter over HTTP
+func DefaultScraper(ctx context.Context, url string) (ExporterStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return ExporterStats{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ExporterStats{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ExporterStats{}, fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	return ParseExporterMetrics(resp.Body)
+}
+
+// ExporterURLs returns the metrics endpoints of the exporters of all replicas
+func ExporterURLs(m *memcachedv1.Memcached) []string {
+	urls := []string{}
+	for i := int32(0); i < Replicas(m); i++ {
+		urls = append(urls, fmt.Sprintf("http://%s-%d.%s.%s.svc:%d/metrics", m.Name, i, m.Name, m.Namespace, MemcachedMetricsPort))
+	}
+	return urls
+}
@@ -0,0 +1,67 @@
+package memcached
+
+import (
+	"strings"
+	"testing"
+)
+
+const exporterOutput = `# HELP memcached_commands_total Total number of all requests broken down by command (get, set, etc.) and status.
+# TYPE memcached_commands_total counter
+memcached_commands_total{command="get",status="hit"} 90
+memcached_commands_total{command="get",status="miss"} 10
+memcached_commands_total{command="set",status="hit"} 55
+# HELP memcached_current_connections Current number of open connections.
+# TYPE memcached_current_connections gauge
+memcached_current_connections 12
+# HELP memcached_current_bytes Current number of bytes used to store items.
+# TYPE memcached_current_bytes gauge
+memcached_current_bytes 4.194304e+07
+# HELP memcached_items_evicted_total Total number of valid items removed from cache to free memory for new items.
+# TYPE memcached_items_evicted_total counter
+memcached_items_evicted_total 3
+# HELP memcached_max_connections Maximum number of clients allowed.
+# TYPE memcached_max_connections gauge
+memcached_max_connections 8192
+# HELP memcached_limit_bytes Number of bytes this server is allowed to use for storage.
+# TYPE memcached_limit_bytes gauge
+memcached_limit_bytes 6.7108864e+07
+memcached_up 1
+`
+
+func TestParseExporterMetrics(t *testing.T) {
+	stats, err := ParseExporterMetrics(strings.NewReader(exporterOutput))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ExporterStats{
+		GetHits:            90,
+		GetMisses:          10,
+		Evictions:          3,
+		CurrentConnections: 12,
+		MaxConnections:     8192,
+		CurrentBytes:       41943040,
+		LimitBytes:         67108864,
+	}
+	if stats != want {
+		t.Errorf("got %+v, want %+v", stats, want)
+	}
+
+	stats.Add(ExporterStats{GetHits: 10, GetMisses: 90})
+	if got := stats.HitRatio(); got != "50.0%" {
+		t.Errorf("HitRatio() = %q, want 50.0%%", got)
+	}
+}
+
+func TestHitRatioWithoutGets(t *testing.T) {
+	stats := ExporterStats{}
+	if got := stats.HitRatio(); got != "" {
+		t.Errorf("HitRatio() = %q, want empty", got)
+	}
+}
+
+func TestParseExporterMetricsInvalidValue(t *testing.T) {
+	_, err := ParseExporterMetrics(strings.NewReader("memcached_current_connections abc\n"))
+	if err == nil {
+		t.Fatal("expected an error for an invalid value")
+	}
+}
@@ -0,0 +1,59 @@
+package memcached
+
+import (
+	"fmt"
+	"strings"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// ExtstoreVolumeName - name of the volumeClaimTemplate holding the extstore file
+	ExtstoreVolumeName = "extstore"
+	// ExtstoreMountPath - where the extstore PVC gets mounted
+	ExtstoreMountPath = "/var/lib/memcached/extstore"
+)
+
+// ExtstoreSizeMB returns the size of the extstore file, 90% of the PVC to
+// leave room for the filesystem overhead
+func ExtstoreSizeMB(m *memcachedv1.Memcached) int64 {
+	if m.Spec.Extstore == nil {
+		return 0
+	}
+	size, err := resource.ParseQuantity(m.Spec.Extstore.StorageRequest)
+	if err != nil {
+		return 0
+	}
+	return size.Value() / (1024 * 1024) * 9 / 10
+}
+
+// ServerOptions returns the memcached command line options for the tunables of the spec
+func ServerOptions(m *memcachedv1.Memcached) string {
+	opts := []string{}
+	if m.Spec.Threads != nil {
+		opts = append(opts, fmt.Sprintf("-t %d", *m.Spec.Threads))
+	}
+	if m.Spec.ItemSizeMax != nil {
+		opts = append(opts, fmt.Sprintf("-I %d", m.Spec.ItemSizeMax.Value()))
+	}
+	if m.Spec.Eviction.Disabled {
+		opts = append(opts, "-M")
+	}
+	if m.Spec.Verbosity > 0 {
+		opts = append(opts, "-"+strings.Repeat("v", int(m.Spec.Verbosity)))
+	}
+
+	extOpts := []string{}
+	if m.Spec.Eviction.LRUCrawler != nil && !*m.Spec.Eviction.LRUCrawler {
+		extOpts = append(extOpts, "no_lru_crawler")
+	}
+	if m.Spec.Extstore != nil {
+		extOpts = append(extOpts, fmt.Sprintf("ext_path=%s/extstore:%dM", ExtstoreMountPath, ExtstoreSizeMB(m)))
+	}
+	if len(extOpts) > 0 {
+		opts = append(opts, "-o "+strings.Join(extOpts, ","))
+	}
+
+	return strings.Join(opts, " ")
+}
@@ -0,0 +1,54 @@
+package memcached
+
+import (
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
+)
+
+// PoolName returns the name of the StatefulSet and headless service of the pool
+func PoolName(m *memcachedv1.Memcached, pool string) string {
+	return m.Name + "-" + pool
+}
+
+// PoolMemcached returns a Memcached describing the servers of the pool, to render their config,
+// service and server lists. It shares the settings of m except for the size, and has no pools,
+// per-pod services, metrics or autoscaling of its own.
+func PoolMemcached(m *memcachedv1.Memcached, pool memcachedv1.MemcachedPool) *memcachedv1.Memcached {
+	p := m.DeepCopy()
+	p.Name = PoolName(m, pool.Name)
+	p.Spec.Replicas = ptr.To(ptr.Deref(pool.Replicas, 1))
+	if pool.CacheSize != nil {
+		p.Spec.CacheSize = *pool.CacheSize
+	}
+	if pool.Resources != nil {
+		p.Spec.Resources = *pool.Resources
+	}
+	p.Spec.Pools = nil
+	p.Spec.PodOverride = nil
+	p.Spec.Autoscaling = nil
+	p.Spec.Metrics = memcachedv1.MetricsSection{}
+	p.Spec.Resize = memcachedv1.ResizeSection{Strategy: memcachedv1.ResizeStrategyImmediate}
+	p.Status = memcachedv1.MemcachedStatus{ListedReplicas: *p.Spec.Replicas}
+	return p
+}
+
+// PoolStatefulSet returns the StatefulSet of the pool. Its pods use the service account and
+// the SASL credentials of m.
+func PoolStatefulSet(
+	m *memcachedv1.Memcached,
+	pool memcachedv1.MemcachedPool,
+	configHash string,
+	topology *topologyv1.Topology,
+) *appsv1.StatefulSet {
+	p := PoolMemcached(m, pool)
+	sfs := StatefulSet(p, configHash, topology)
+	sfs.Spec.Template.Spec.ServiceAccountName = m.RbacResourceName()
+	for _, v := range sfs.Spec.Template.Spec.Volumes {
+		if v.Secret != nil && v.Secret.SecretName == AuthSecretName(p) {
+			v.Secret.SecretName = AuthSecretName(m)
+		}
+	}
+	return sfs
+}
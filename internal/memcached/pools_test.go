@@ -0,0 +1,42 @@
+package memcached
+
+import (
+	"testing"
+
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	"k8s.io/utils/ptr"
+)
+
+func TestPoolStatefulSet(t *testing.T) {
+	m := &memcachedv1.Memcached{}
+	m.Name = "memcached"
+	m.Spec.Replicas = ptr.To[int32](3)
+	m.Spec.CacheSize = 1024
+	m.Spec.Auth.Enabled = true
+	m.Spec.Metrics.Enabled = true
+	pool := memcachedv1.MemcachedPool{Name: "sessions", Replicas: ptr.To[int32](2), CacheSize: ptr.To[int32](256)}
+
+	p := PoolMemcached(m, pool)
+	if p.Name != "memcached-sessions" || *p.Spec.Replicas != 2 || p.Spec.CacheSize != 256 {
+		t.Errorf("unexpected pool %s with %d replicas of %dMB", p.Name, *p.Spec.Replicas, p.Spec.CacheSize)
+	}
+	if p.Spec.Metrics.Enabled || p.Status.ListedReplicas != 2 {
+		t.Errorf("pool must not have metrics and list all its replicas")
+	}
+
+	sfs := PoolStatefulSet(m, pool, "hash", nil)
+	if sfs.Name != "memcached-sessions" || *sfs.Spec.Replicas != 2 {
+		t.Errorf("unexpected StatefulSet %s with %d replicas", sfs.Name, *sfs.Spec.Replicas)
+	}
+	if sfs.Spec.Template.Spec.ServiceAccountName != "memcached-memcached" {
+		t.Errorf("pool uses service account %s", sfs.Spec.Template.Spec.ServiceAccountName)
+	}
+	if len(sfs.Spec.Template.Spec.Containers) != 1 {
+		t.Errorf("pool must not have an exporter sidecar")
+	}
+	for _, v := range sfs.Spec.Template.Spec.Volumes {
+		if v.Name == "sasl" && v.Secret.SecretName != "memcached-auth" {
+			t.Errorf("pool uses auth secret %s", v.Secret.SecretName)
+		}
+	}
+}
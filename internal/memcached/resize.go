@@ -0,0 +1,17 @@
+package memcached
+
+import (
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+)
+
+// StatefulSetReplicas returns the number of pods to run. While a staged scale
+// down drains the removed servers, their pods are kept until the grace period
+// is over.
+func StatefulSetReplicas(m *memcachedv1.Memcached) *int32 {
+	replicas := Replicas(m)
+	if m.Status.Resize != nil && m.Status.Resize.Phase == memcachedv1.ResizePhaseDraining &&
+		m.Status.Resize.From > replicas {
+		replicas = m.Status.Resize.From
+	}
+	return &replicas
+}
@@ -1,11 +1,15 @@
 package memcached
 
 import (
+	"fmt"
+
 	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
 	common "github.com/openstack-k8s-operators/lib-common/modules/common"
 	labels "github.com/openstack-k8s-operators/lib-common/modules/common/labels"
 	service "github.com/openstack-k8s-operators/lib-common/modules/common/service"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // HeadlessService exposes all memcached replicas for a memcached CR
@@ -26,6 +30,10 @@ func HeadlessService(m *memcachedv1.Memcached) *corev1.Service {
 		ports = []corev1.ServicePort{{Name: "memcached-tls", Protocol: "TCP", Port: MemcachedTLSPort}}
 	}
 
+	if m.Spec.Metrics.Enabled {
+		ports = append(ports, corev1.ServicePort{Name: "metrics", Protocol: "TCP", Port: MemcachedMetricsPort})
+	}
+
 	details := &service.GenericServiceDetails{
 		Name:      m.GetName(),
 		Namespace: m.GetNamespace(),
@@ -41,3 +49,38 @@ func HeadlessService(m *memcachedv1.Memcached) *corev1.Service {
 	svc := service.GenericService(details)
 	return svc
 }
+
+// PerPodServiceName returns the name of the service of the pod with the given index
+func PerPodServiceName(m *memcachedv1.Memcached, index int) string {
+	return fmt.Sprintf("%s-%d", m.GetName(), index)
+}
+
+// PerPodService exposes a single memcached replica, the service type and annotations
+// come from the override of the pod in spec.podOverride
+func PerPodService(m *memcachedv1.Memcached, index int) *corev1.Service {
+	// no "app" label, the ServiceMonitor only selects the headless service
+	labels := labels.GetLabels(m, "memcached", map[string]string{
+		common.OwnerSelector: "infra-operator",
+		"cr":                 m.GetName(),
+		common.AppSelector:   m.GetName(),
+	})
+	podName := PerPodServiceName(m, index)
+
+	ports := []corev1.ServicePort{
+		{Name: "memcached-tls", Protocol: "TCP", Port: MemcachedTLSPort, TargetPort: intstr.FromInt32(MemcachedTLSPort)},
+		{Name: "memcached", Protocol: "TCP", Port: MemcachedPort, TargetPort: intstr.FromInt32(MemcachedPort)},
+	}
+	if m.Spec.TLS.MTLS.SslVerifyMode == "Require" {
+		ports = ports[:1]
+	}
+
+	return service.GenericService(&service.GenericServiceDetails{
+		Name:      podName,
+		Namespace: m.GetNamespace(),
+		Labels:    labels,
+		Selector: map[string]string{
+			appsv1.StatefulSetPodNameLabel: podName,
+		},
+		Ports: ports,
+	})
+}
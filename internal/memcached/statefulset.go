@@ -8,6 +8,7 @@ import (
 	labels "github.com/openstack-k8s-operators/lib-common/modules/common/labels"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
@@ -54,7 +55,7 @@ func StatefulSet(
 		},
 		Spec: appsv1.StatefulSetSpec{
 			ServiceName: m.Name,
-			Replicas:    m.Spec.Replicas,
+			Replicas:    StatefulSetReplicas(m),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: matchls,
 			},
@@ -104,6 +105,21 @@ func StatefulSet(
 			},
 		},
 	}
+	if m.Spec.Metrics.Enabled {
+		sfs.Spec.Template.Spec.Containers = append(sfs.Spec.Template.Spec.Containers, exporterContainer(m))
+	}
+	if m.Spec.Extstore != nil {
+		sfs.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			extstoreVolumeClaimTemplate(m),
+		}
+	}
+	if m.Spec.Extstore != nil || CertReloadSupported(m) {
+		// make the PVC writable and the reloadable certificate readable
+		// for the memcached user
+		sfs.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
+			FSGroup: ptr.To(MemcachedUID),
+		}
+	}
 	if m.Spec.NodeSelector != nil {
 		sfs.Spec.Template.Spec.NodeSelector = *m.Spec.NodeSelector
 	}
@@ -123,3 +139,29 @@ func StatefulSet(
 	}
 	return sfs
 }
+
+func extstoreVolumeClaimTemplate(m *memcachedv1.Memcached) corev1.PersistentVolumeClaim {
+	// the webhook validates the request, an invalid one results in a PVC
+	// without storage request which the API server rejects
+	requests := corev1.ResourceList{}
+	if size, err := resource.ParseQuantity(m.Spec.Extstore.StorageRequest); err == nil {
+		requests[corev1.ResourceStorage] = size
+	}
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ExtstoreVolumeName,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteOnce,
+			},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: requests,
+			},
+		},
+	}
+	if m.Spec.Extstore.StorageClass != "" {
+		pvc.Spec.StorageClassName = ptr.To(m.Spec.Extstore.StorageClass)
+	}
+	return pvc
+}
@@ -6,6 +6,7 @@ import (
 	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -49,6 +50,29 @@ func getVolumes(m *memcachedv1.Memcached) []corev1.Volume {
 		},
 	}
 
+	if m.Spec.Auth.Enabled {
+		configData := vols[1].VolumeSource.ConfigMap
+		configData.Items = append(configData.Items, corev1.KeyToPath{
+			Key:  "sasl.conf",
+			Path: "etc/sasl2/memcached.conf",
+		})
+		vols = append(vols, corev1.Volume{
+			Name: "sasl",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: AuthSecretName(m),
+					Items: []corev1.KeyToPath{
+						{
+							Key:  MemcachedSASLDBKey,
+							Path: MemcachedSASLDBKey,
+						},
+					},
+					DefaultMode: ptr.To[int32](0o400),
+				},
+			},
+		})
+	}
+
 	if m.Spec.TLS.Enabled() {
 		svc := tls.Service{
 			SecretName: *m.Spec.TLS.SecretName,
@@ -76,6 +100,21 @@ func getVolumeMounts(m *memcachedv1.Memcached) []corev1.VolumeMount {
 		Name:      "kolla-config",
 	}}
 
+	if m.Spec.Extstore != nil {
+		vm = append(vm, corev1.VolumeMount{
+			MountPath: ExtstoreMountPath,
+			Name:      ExtstoreVolumeName,
+		})
+	}
+
+	if m.Spec.Auth.Enabled {
+		vm = append(vm, corev1.VolumeMount{
+			MountPath: "/var/lib/config-data/sasl",
+			ReadOnly:  true,
+			Name:      "sasl",
+		})
+	}
+
 	if m.Spec.TLS.Enabled() {
 		svc := tls.Service{
 			SecretName: *m.Spec.TLS.SecretName,
@@ -84,6 +123,13 @@ func getVolumeMounts(m *memcachedv1.Memcached) []corev1.VolumeMount {
 			CaMount:    nil,
 		}
 		vm = append(vm, svc.CreateVolumeMounts(MemcachedCertPrefix)...)
+		if CertReloadSupported(m) {
+			vm = append(vm, corev1.VolumeMount{
+				MountPath: CertReloadMountDir,
+				ReadOnly:  true,
+				Name:      MemcachedCertPrefix + "-tls-certs",
+			})
+		}
 		if m.Spec.TLS.CaBundleSecretName != "" {
 			vm = append(vm, m.Spec.TLS.CreateVolumeMounts(nil)...)
 		}
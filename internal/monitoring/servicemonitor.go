@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package monitoring provides the Prometheus operator resources scraping the exporters
+package monitoring
+
+import (
+	"context"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServiceMonitorGVK - Prometheus operator ServiceMonitor scraping the exporters
+var ServiceMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+// NewServiceMonitor returns an empty ServiceMonitor with the given name
+func NewServiceMonitor(name string, namespace string) *unstructured.Unstructured {
+	sm := &unstructured.Unstructured{}
+	sm.SetGroupVersionKind(ServiceMonitorGVK)
+	sm.SetName(name)
+	sm.SetNamespace(namespace)
+	return sm
+}
+
+// DeleteServiceMonitor deletes the ServiceMonitor with the given name if the owner controls it,
+// a ServiceMonitor of another service with the same name is left alone. A missing
+// ServiceMonitor CRD means there is nothing to delete.
+func DeleteServiceMonitor(ctx context.Context, c client.Client, name string, owner metav1.Object) error {
+	sm := NewServiceMonitor(name, owner.GetNamespace())
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: owner.GetNamespace()}, sm)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(sm, owner) {
+		return nil
+	}
+	err = c.Delete(ctx, sm)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDeleteServiceMonitor(t *testing.T) {
+	owner := &corev1.ConfigMap{}
+	owner.Name = "redis"
+	owner.Namespace = "openstack"
+	owner.UID = "owner"
+	other := owner.DeepCopy()
+	other.UID = "other"
+
+	owned := NewServiceMonitor("owned", "openstack")
+	owned.SetOwnerReferences([]metav1.OwnerReference{{Name: "redis", UID: owner.UID, Controller: ptr.To(true)}})
+	foreign := NewServiceMonitor("foreign", "openstack")
+	foreign.SetOwnerReferences([]metav1.OwnerReference{{Name: "redis", UID: other.UID, Controller: ptr.To(true)}})
+	c := fake.NewClientBuilder().WithObjects(owned, foreign).Build()
+
+	ctx := context.Background()
+	for _, name := range []string{"owned", "foreign", "missing"} {
+		if err := DeleteServiceMonitor(ctx, c, name, owner); err != nil {
+			t.Fatalf("deleting %s: %v", name, err)
+		}
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "owned", Namespace: "openstack"}, NewServiceMonitor("", "")); !k8s_errors.IsNotFound(err) {
+		t.Errorf("the owned ServiceMonitor was not deleted: %v", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "foreign", Namespace: "openstack"}, NewServiceMonitor("", "")); err != nil {
+		t.Errorf("the ServiceMonitor of another owner was deleted: %v", err)
+	}
+}
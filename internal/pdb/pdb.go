@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pdb reconciles the PodDisruptionBudgets of the stateful infra services
+package pdb
+
+import (
+	"context"
+	"time"
+
+	commonv1 "github.com/openstack-k8s-operators/infra-operator/apis/common/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	common_pdb "github.com/openstack-k8s-operators/lib-common/modules/common/pdb"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// EnsurePodDisruptionBudget - creates or updates the PodDisruptionBudget of the
+// pods matching labels, or deletes it if it is not needed
+func EnsurePodDisruptionBudget(
+	ctx context.Context,
+	h *helper.Helper,
+	p *commonv1.PodDisruptionBudgetSection,
+	replicas int32,
+	name string,
+	namespace string,
+	labels map[string]string,
+) (ctrl.Result, error) {
+	maxUnavailable := p.GetMaxUnavailable(replicas)
+	if maxUnavailable == nil {
+		return ctrl.Result{}, common_pdb.DeletePDBWithName(ctx, h, name, namespace)
+	}
+
+	pdbInstance := common_pdb.NewPDB(
+		common_pdb.MaxUnavailablePodDisruptionBudget(name, namespace, *maxUnavailable, labels),
+		time.Duration(5)*time.Second,
+	)
+	return pdbInstance.CreateOrPatch(ctx, h)
+}
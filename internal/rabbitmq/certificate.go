@@ -0,0 +1,80 @@
+package rabbitmq
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CertificateGVK - cert-manager Certificate requested for the per-pod services
+var CertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// PerPodServiceName returns the name of the service of the pod with the given index
+func PerPodServiceName(name string, index int) string {
+	return fmt.Sprintf("%s-server-%d", name, index)
+}
+
+// PerPodServiceHostnames returns the hostnames of the per-pod services of a cluster
+func PerPodServiceHostnames(name string, namespace string, replicas int) []string {
+	hostnames := []string{}
+	for i := 0; i < replicas; i++ {
+		hostnames = append(hostnames, fmt.Sprintf("%s.%s.svc", PerPodServiceName(name, i), namespace))
+	}
+	return hostnames
+}
+
+// CertificateDNSNames returns the SANs of the certificate requested for the per-pod services,
+// the cluster service, the cluster nodes and each per-pod service hostname
+func CertificateDNSNames(name string, namespace string, replicas int) []string {
+	dnsNames := []string{
+		fmt.Sprintf("%s.%s.svc", name, namespace),
+		fmt.Sprintf("*.%s-nodes.%s.svc", name, namespace),
+	}
+	return append(dnsNames, PerPodServiceHostnames(name, namespace, replicas)...)
+}
+
+// NewCertificate returns an empty cert-manager Certificate with the given name
+func NewCertificate(name string, namespace string) *unstructured.Unstructured {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(CertificateGVK)
+	cert.SetName(name)
+	cert.SetNamespace(namespace)
+	return cert
+}
+
+// SetCertificateSpec sets the spec of a cert-manager Certificate
+func SetCertificateSpec(
+	cert *unstructured.Unstructured,
+	secretName string,
+	dnsNames []string,
+	issuerName string,
+	issuerKind string,
+) error {
+	names := make([]interface{}, 0, len(dnsNames))
+	for _, n := range dnsNames {
+		names = append(names, n)
+	}
+
+	spec := map[string]interface{}{
+		"secretName": secretName,
+		"commonName": dnsNames[0],
+		"dnsNames":   names,
+		"usages": []interface{}{
+			"key encipherment",
+			"digital signature",
+			"server auth",
+			"client auth",
+		},
+		"issuerRef": map[string]interface{}{
+			"name":  issuerName,
+			"kind":  issuerKind,
+			"group": CertificateGVK.Group,
+		},
+	}
+	return unstructured.SetNestedMap(cert.Object, spec, "spec")
+}
@@ -0,0 +1,45 @@
+package rabbitmq
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCertificateDNSNames(t *testing.T) {
+	want := []string{
+		"rabbitmq.openstack.svc",
+		"*.rabbitmq-nodes.openstack.svc",
+		"rabbitmq-server-0.openstack.svc",
+		"rabbitmq-server-1.openstack.svc",
+	}
+	got := CertificateDNSNames("rabbitmq", "openstack", 2)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CertificateDNSNames() = %v, want %v", got, want)
+	}
+}
+
+func TestSetCertificateSpec(t *testing.T) {
+	cert := NewCertificate("rabbitmq-svc", "openstack")
+	dnsNames := CertificateDNSNames("rabbitmq", "openstack", 1)
+	if err := SetCertificateSpec(cert, "rabbitmq-svc-cert", dnsNames, "rootca-internal", "Issuer"); err != nil {
+		t.Fatalf("SetCertificateSpec() error = %v", err)
+	}
+
+	if cert.GetKind() != "Certificate" || cert.GetAPIVersion() != "cert-manager.io/v1" {
+		t.Errorf("unexpected kind %s %s", cert.GetAPIVersion(), cert.GetKind())
+	}
+	secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+	if secretName != "rabbitmq-svc-cert" {
+		t.Errorf("secretName = %q, want %q", secretName, "rabbitmq-svc-cert")
+	}
+	gotNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	if !reflect.DeepEqual(gotNames, dnsNames) {
+		t.Errorf("dnsNames = %v, want %v", gotNames, dnsNames)
+	}
+	issuer, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "name")
+	if issuer != "rootca-internal" {
+		t.Errorf("issuerRef.name = %q, want %q", issuer, "rootca-internal")
+	}
+}
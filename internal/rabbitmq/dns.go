@@ -0,0 +1,41 @@
+package rabbitmq
+
+import (
+	"slices"
+
+	networkv1 "github.com/openstack-k8s-operators/infra-operator/apis/network/v1beta1"
+)
+
+// UnregisteredHostnames returns the hostnames which are not registered with all their
+// addresses in any of the DNSData. Hostnames without addresses are never registered.
+func UnregisteredHostnames(hostnames []string, addresses map[string][]string, dnsData []networkv1.DNSData) []string {
+	unregistered := []string{}
+	for _, host := range hostnames {
+		registered := false
+		for _, data := range dnsData {
+			if isRegistered(host, addresses[host], data.Spec.Hosts) {
+				registered = true
+				break
+			}
+		}
+		if !registered {
+			unregistered = append(unregistered, host)
+		}
+	}
+	return unregistered
+}
+
+func isRegistered(host string, addrs []string, hosts []networkv1.DNSHost) bool {
+	if len(addrs) == 0 {
+		return false
+	}
+	for _, addr := range addrs {
+		idx := slices.IndexFunc(hosts, func(h networkv1.DNSHost) bool {
+			return h.IP == addr && slices.Contains(h.Hostnames, host)
+		})
+		if idx < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package rabbitmq
+
+import (
+	"reflect"
+	"testing"
+
+	networkv1 "github.com/openstack-k8s-operators/infra-operator/apis/network/v1beta1"
+)
+
+func TestUnregisteredHostnames(t *testing.T) {
+	hostnames := []string{
+		"rabbitmq-server-0.openstack.svc",
+		"rabbitmq-server-1.openstack.svc",
+		"rabbitmq-server-2.openstack.svc",
+	}
+	addresses := map[string][]string{
+		"rabbitmq-server-0.openstack.svc": {"192.0.2.11"},
+		"rabbitmq-server-1.openstack.svc": {"192.0.2.12"},
+	}
+	dnsData := func(hosts ...networkv1.DNSHost) networkv1.DNSData {
+		data := networkv1.DNSData{}
+		data.Spec.Hosts = hosts
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		dnsData []networkv1.DNSData
+		want    []string
+	}{
+		{
+			name:    "No DNSData",
+			dnsData: []networkv1.DNSData{},
+			want:    hostnames,
+		},
+		{
+			name: "single DNSData",
+			dnsData: []networkv1.DNSData{
+				dnsData(networkv1.DNSHost{IP: "192.0.2.11", Hostnames: []string{hostnames[0]}}),
+			},
+			want: hostnames[1:],
+		},
+		{
+			name: "multiple DNSData",
+			dnsData: []networkv1.DNSData{
+				dnsData(networkv1.DNSHost{IP: "192.0.2.11", Hostnames: []string{hostnames[0]}}),
+				dnsData(networkv1.DNSHost{IP: "192.0.2.12", Hostnames: []string{hostnames[1]}}),
+			},
+			want: hostnames[2:],
+		},
+		{
+			name: "stale address",
+			dnsData: []networkv1.DNSData{
+				dnsData(
+					networkv1.DNSHost{IP: "192.0.2.11", Hostnames: []string{hostnames[0]}},
+					networkv1.DNSHost{IP: "192.0.2.99", Hostnames: []string{hostnames[1]}},
+				),
+			},
+			want: hostnames[1:],
+		},
+		{
+			name: "hostname without address",
+			dnsData: []networkv1.DNSData{
+				dnsData(networkv1.DNSHost{IP: "192.0.2.13", Hostnames: []string{hostnames[2]}}),
+			},
+			want: hostnames,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnregisteredHostnames(hostnames, addresses, tt.dnsData)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnregisteredHostnames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package redis
+
+import (
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AuthSecretName returns the name of the secret holding the password of the default user
+func AuthSecretName(r *redisv1.Redis) string {
+	return r.Name + redisv1.AuthSecretSuffix
+}
+
+// AuthSecretData returns the content of the auth secret
+func AuthSecretData(password string) map[string][]byte {
+	return map[string][]byte{
+		redisv1.AuthPasswordKey: []byte(password),
+	}
+}
+
+// authEnvVars returns the REDIS_PASSWORD variable the scripts use to render requirepass and
+// masterauth and to authenticate redis-cli, none if authentication is disabled
+func authEnvVars(r *redisv1.Redis) []corev1.EnvVar {
+	if !r.Spec.Auth.Enabled {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name: "REDIS_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: AuthSecretName(r),
+				},
+				Key: redisv1.AuthPasswordKey,
+			},
+		},
+	}}
+}
@@ -0,0 +1,243 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	common "github.com/openstack-k8s-operators/lib-common/modules/common"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/clusterdns"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// BackupAppLabel - value of the service label of the backup pods
+	BackupAppLabel = "redis-backup"
+	// BackupVolumeName - name of the volume the backups get written to or restored from
+	BackupVolumeName = "redis-backup"
+	// BackupMountPath - where backup_redis.sh and the restore in start_redis_replication.sh
+	// find the backups, a scratch directory for S3 targets
+	BackupMountPath = "/var/lib/redis-backup"
+	// BackupJobHistory - how many finished backup jobs a schedule keeps
+	BackupJobHistory = 3
+)
+
+// BackupJobName returns the name of the job, or the cron job, taking the backups
+func BackupJobName(b *redisv1.RedisBackup) string {
+	return b.Name + "-redis-backup"
+}
+
+// BackupLabels returns the labels of the backup jobs and their pods
+func BackupLabels(b *redisv1.RedisBackup) map[string]string {
+	return map[string]string{
+		common.AppSelector:   BackupAppLabel,
+		common.OwnerSelector: b.Name,
+	}
+}
+
+// backupTargetEnv returns the variables describing an S3 target, the scripts use a volume
+// claim when they are not set
+func backupTargetEnv(target redisv1.BackupTarget) []corev1.EnvVar {
+	if target.S3 == nil {
+		return nil
+	}
+	secretKey := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: target.S3.SecretName,
+				},
+				Key: key,
+			},
+		}
+	}
+	return []corev1.EnvVar{{
+		Name:  "S3_ENDPOINT",
+		Value: target.S3.Endpoint,
+	}, {
+		Name:  "S3_BUCKET",
+		Value: target.S3.Bucket,
+	}, {
+		Name:  "S3_REGION",
+		Value: target.S3.Region,
+	}, {
+		Name:      "AWS_ACCESS_KEY_ID",
+		ValueFrom: secretKey("AWS_ACCESS_KEY_ID"),
+	}, {
+		Name:      "AWS_SECRET_ACCESS_KEY",
+		ValueFrom: secretKey("AWS_SECRET_ACCESS_KEY"),
+	}}
+}
+
+// backupVolume returns the volume claim of the target, or a scratch directory for S3 targets
+func backupVolume(target redisv1.BackupTarget, readOnly bool) corev1.Volume {
+	vol := corev1.Volume{
+		Name: BackupVolumeName,
+	}
+	if target.PVC != nil {
+		vol.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: target.PVC.ClaimName,
+			ReadOnly:  readOnly,
+		}
+	} else {
+		vol.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+	return vol
+}
+
+// backupPodTemplate returns the pod running backup_redis.sh against the Redis instance
+func backupPodTemplate(b *redisv1.RedisBackup, r *redisv1.Redis) corev1.PodTemplateSpec {
+	scriptsPerms := int32(0o755)
+	env := []corev1.EnvVar{{
+		Name:  "SVC_FQDN",
+		Value: r.Name + "-redis." + r.Namespace + ".svc." + clusterdns.GetDNSClusterDomain(),
+	}, {
+		Name:  "BACKUP_NAME",
+		Value: b.Name,
+	}}
+	env = append(env, authEnvVars(r)...)
+	env = append(env, backupTargetEnv(b.Spec.Target)...)
+
+	volumes := []corev1.Volume{
+		{
+			Name: "operator-scripts",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: r.Name + "-scripts",
+					},
+					Items: []corev1.KeyToPath{
+						{
+							Key:  "backup_redis.sh",
+							Path: "backup_redis.sh",
+						},
+						{
+							Key:  "common.sh",
+							Path: "common.sh",
+						},
+					},
+					DefaultMode: &scriptsPerms,
+				},
+			},
+		},
+		backupVolume(b.Spec.Target, false),
+	}
+	volumes = append(volumes, getTLSVolumes(r)...)
+
+	volumeMounts := []corev1.VolumeMount{{
+		MountPath: "/var/lib/operator-scripts",
+		ReadOnly:  true,
+		Name:      "operator-scripts",
+	}, {
+		MountPath: BackupMountPath,
+		Name:      BackupVolumeName,
+	}}
+	volumeMounts = append(volumeMounts, getTLSVolumeMounts(r)...)
+
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: BackupLabels(b),
+		},
+		Spec: corev1.PodSpec{
+			// the scripts read the namespace and the token of the service account
+			ServiceAccountName: r.RbacResourceName(),
+			RestartPolicy:      corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:         "backup",
+				Image:        r.Spec.ContainerImage,
+				Command:      []string{"/var/lib/operator-scripts/backup_redis.sh"},
+				Env:          env,
+				VolumeMounts: volumeMounts,
+			}},
+			Volumes: volumes,
+		},
+	}
+}
+
+func backupJobSpec(b *redisv1.RedisBackup, r *redisv1.Redis) batchv1.JobSpec {
+	return batchv1.JobSpec{
+		BackoffLimit: ptr.To[int32](2),
+		Template:     backupPodTemplate(b, r),
+	}
+}
+
+// BackupJob returns the job taking a single backup
+func BackupJob(b *redisv1.RedisBackup, r *redisv1.Redis) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BackupJobName(b),
+			Namespace: b.Namespace,
+			Labels:    BackupLabels(b),
+		},
+		Spec: backupJobSpec(b, r),
+	}
+}
+
+// BackupCronJob returns the cron job taking the scheduled backups
+func BackupCronJob(b *redisv1.RedisBackup, r *redisv1.Redis) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BackupJobName(b),
+			Namespace: b.Namespace,
+			Labels:    BackupLabels(b),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   b.Spec.Schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To[int32](BackupJobHistory),
+			FailedJobsHistoryLimit:     ptr.To[int32](BackupJobHistory),
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: BackupLabels(b),
+				},
+				Spec: backupJobSpec(b, r),
+			},
+		},
+	}
+}
+
+// ParseBackupRecord parses the termination message backup_redis.sh reports the backup with
+func ParseBackupRecord(message string) (*redisv1.BackupRecord, error) {
+	record := &redisv1.BackupRecord{}
+	if err := json.Unmarshal([]byte(message), record); err != nil {
+		return nil, fmt.Errorf("invalid backup report %q: %w", message, err)
+	}
+	if record.Location == "" || record.Checksum == "" {
+		return nil, fmt.Errorf("incomplete backup report %q", message)
+	}
+	return record, nil
+}
+
+// ApplyRestore makes the first redis pod of the statefulset pod template seed a new cluster with
+// the backup before it bootstraps
+func ApplyRestore(template *corev1.PodTemplateSpec, target redisv1.BackupTarget, backup *redisv1.BackupRecord) {
+	for i := range template.Spec.Containers {
+		c := &template.Spec.Containers[i]
+		if c.Name != "redis" {
+			continue
+		}
+		// the containers share the backing array of their environment
+		c.Env = append(slices.Clone(c.Env), corev1.EnvVar{
+			Name:  "RESTORE_LOCATION",
+			Value: backup.Location,
+		}, corev1.EnvVar{
+			Name:  "RESTORE_CHECKSUM",
+			Value: backup.Checksum,
+		})
+		c.Env = append(c.Env, backupTargetEnv(target)...)
+		if target.PVC != nil {
+			c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+				MountPath: BackupMountPath,
+				ReadOnly:  true,
+				Name:      BackupVolumeName,
+			})
+		}
+	}
+	if target.PVC != nil {
+		template.Spec.Volumes = append(template.Spec.Volumes, backupVolume(target, true))
+	}
+}
@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"testing"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func envValue(env []corev1.EnvVar, name string) string {
+	for _, e := range env {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+func TestBackupJob(t *testing.T) {
+	r := &redisv1.Redis{}
+	r.Name = "redis"
+	r.Namespace = "openstack"
+	r.Spec.Replicas = ptr.To[int32](3)
+	b := &redisv1.RedisBackup{}
+	b.Name = "nightly"
+	b.Namespace = "openstack"
+	b.Spec.Target.S3 = &redisv1.BackupS3Target{
+		Endpoint:   "http://minio.minio.svc:9000",
+		Bucket:     "backups",
+		Region:     "us-east-1",
+		SecretName: "s3-creds",
+	}
+
+	job := BackupJob(b, r)
+	if job.Name != "nightly-redis-backup" {
+		t.Errorf("job name = %s", job.Name)
+	}
+	pod := job.Spec.Template.Spec
+	env := pod.Containers[0].Env
+	if got := envValue(env, "S3_BUCKET"); got != "backups" {
+		t.Errorf("S3_BUCKET = %s, want backups", got)
+	}
+	if got := envValue(env, "BACKUP_NAME"); got != "nightly" {
+		t.Errorf("BACKUP_NAME = %s, want nightly", got)
+	}
+	for _, vol := range pod.Volumes {
+		if vol.Name == BackupVolumeName && vol.EmptyDir == nil {
+			t.Errorf("S3 backups need a scratch directory, got %v", vol.VolumeSource)
+		}
+	}
+
+	b.Spec.Schedule = "0 3 * * *"
+	b.Spec.Target = redisv1.BackupTarget{PVC: &redisv1.BackupPVCTarget{ClaimName: "backups"}}
+	cronJob := BackupCronJob(b, r)
+	if cronJob.Spec.Schedule != "0 3 * * *" {
+		t.Errorf("schedule = %s", cronJob.Spec.Schedule)
+	}
+	pod = cronJob.Spec.JobTemplate.Spec.Template.Spec
+	if got := envValue(pod.Containers[0].Env, "S3_ENDPOINT"); got != "" {
+		t.Errorf("S3_ENDPOINT = %s for a volume claim target", got)
+	}
+	for _, vol := range pod.Volumes {
+		if vol.Name == BackupVolumeName && (vol.PersistentVolumeClaim == nil || vol.PersistentVolumeClaim.ClaimName != "backups") {
+			t.Errorf("backup volume = %v, want the claim backups", vol.VolumeSource)
+		}
+	}
+}
+
+func TestParseBackupRecord(t *testing.T) {
+	record, err := ParseBackupRecord(`{"location":"nightly/20250101030000.rdb","size":1024,"checksum":"abc","time":"2025-01-01T03:00:00Z"}`)
+	if err != nil {
+		t.Fatalf("ParseBackupRecord() error = %v", err)
+	}
+	if record.Location != "nightly/20250101030000.rdb" || record.Size != 1024 || record.Checksum != "abc" {
+		t.Errorf("ParseBackupRecord() = %+v", record)
+	}
+	if _, err := ParseBackupRecord("could not connect"); err == nil {
+		t.Error("ParseBackupRecord() accepted an invalid report")
+	}
+	if _, err := ParseBackupRecord(`{"size":1024}`); err == nil {
+		t.Error("ParseBackupRecord() accepted an incomplete report")
+	}
+}
+
+func TestApplyRestore(t *testing.T) {
+	r := &redisv1.Redis{}
+	r.Name = "redis"
+	r.Namespace = "openstack"
+	r.Spec.Replicas = ptr.To[int32](3)
+	sts := StatefulSet(r, "hash", nil)
+	target := redisv1.BackupTarget{PVC: &redisv1.BackupPVCTarget{ClaimName: "backups"}}
+	backup := &redisv1.BackupRecord{Location: "nightly/20250101030000.rdb", Checksum: "abc"}
+
+	ApplyRestore(&sts.Spec.Template, target, backup)
+
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		location := envValue(c.Env, "RESTORE_LOCATION")
+		switch c.Name {
+		case "redis":
+			if location != backup.Location || envValue(c.Env, "RESTORE_CHECKSUM") != "abc" {
+				t.Errorf("redis container restores %q", location)
+			}
+		case "sentinel":
+			if location != "" {
+				t.Errorf("sentinel container restores %q", location)
+			}
+		}
+	}
+	found := false
+	for _, vol := range sts.Spec.Template.Spec.Volumes {
+		if vol.Name == BackupVolumeName {
+			found = vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ReadOnly
+		}
+	}
+	if !found {
+		t.Error("the backup claim is not mounted read-only")
+	}
+}
@@ -0,0 +1,264 @@
+package redis
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	common "github.com/openstack-k8s-operators/lib-common/modules/common"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/clusterdns"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// ClusterAppLabel - value of the service label of the cluster job pods
+	ClusterAppLabel = "redis-cluster"
+	// ClusterHashName - status hash of the last successful cluster job
+	ClusterHashName = "cluster"
+	// ClusterHashAnnotation - annotation of the cluster job with the hash of the shape it applies
+	ClusterHashAnnotation = "redis.openstack.org/cluster-hash"
+	// ClusterSlots - number of hash slots a healthy cluster serves
+	ClusterSlots = 16384
+	// ClusterRepairDelay - how long the cluster may be unhealthy before the cluster job runs
+	// again, longer than the nodes take to fail over a master by themselves
+	ClusterRepairDelay = time.Minute
+)
+
+// Replicas returns the number of pods of the StatefulSet. In cluster mode the pods of removed
+// shards are kept until the cluster job moved their slots away and made them leave the cluster.
+func Replicas(r *redisv1.Redis) int32 {
+	replicas := r.Spec.PodCount()
+	if r.Spec.IsClusterMode() && r.Status.Cluster != nil {
+		replicas = max(replicas, r.Status.Cluster.Shards*(1+r.Status.Cluster.ReplicasPerShard))
+	}
+	return replicas
+}
+
+// podFQDN returns the name of the pod in the headless service
+func podFQDN(r *redisv1.Redis, pod string) string {
+	return fmt.Sprintf("%s.%s-redis.%s.svc", pod, r.Name, r.Namespace)
+}
+
+// ClusterNodeHosts returns the host:port of every node of the cluster mode, without the nodes
+// of removed shards
+func ClusterNodeHosts(r *redisv1.Redis) []string {
+	hosts := []string{}
+	for i := int32(0); i < r.Spec.PodCount(); i++ {
+		pod := fmt.Sprintf("%s-redis-%d", r.Name, i)
+		hosts = append(hosts, fmt.Sprintf("%s:%d", podFQDN(r, pod), redisv1.RedisPort))
+	}
+	return hosts
+}
+
+// ClusterHash returns the hash of the shape of the cluster mode, the cluster job runs when it
+// changes
+func ClusterHash(r *redisv1.Redis) (string, error) {
+	return util.ObjectHash([]int32{r.Spec.ClusterShards(), r.Spec.ClusterReplicasPerShard()})
+}
+
+// ClusterJobName returns the name of the job creating and resharding the cluster
+func ClusterJobName(r *redisv1.Redis) string {
+	return r.Name + "-redis-cluster"
+}
+
+// ClusterJob returns the job running cluster_redis.sh. It creates the cluster, joins the nodes
+// of new shards, moves the slots of removed shards away and distributes the slots evenly.
+// The nodes of removed shards leave the cluster once their slots moved away.
+func ClusterJob(r *redisv1.Redis) *batchv1.Job {
+	scriptsPerms := int32(0o755)
+	clusterDomain := clusterdns.GetDNSClusterDomain()
+	nodes := []string{}
+	removed := []string{}
+	for i := int32(0); i < Replicas(r); i++ {
+		host := podFQDN(r, fmt.Sprintf("%s-redis-%d", r.Name, i)) + "." + clusterDomain
+		if i < r.Spec.PodCount() {
+			nodes = append(nodes, host)
+		} else {
+			removed = append(removed, host)
+		}
+	}
+
+	env := []corev1.EnvVar{{
+		Name:  "SVC_FQDN",
+		Value: r.Name + "-redis." + r.Namespace + ".svc." + clusterDomain,
+	}, {
+		Name:  "CLUSTER_SHARDS",
+		Value: strconv.Itoa(int(r.Spec.ClusterShards())),
+	}, {
+		Name:  "CLUSTER_REPLICAS_PER_SHARD",
+		Value: strconv.Itoa(int(r.Spec.ClusterReplicasPerShard())),
+	}, {
+		Name:  "CLUSTER_NODES",
+		Value: strings.Join(nodes, " "),
+	}, {
+		Name:  "CLUSTER_REMOVED_NODES",
+		Value: strings.Join(removed, " "),
+	}}
+	env = append(env, authEnvVars(r)...)
+
+	volumes := []corev1.Volume{{
+		Name: "operator-scripts",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: r.Name + "-scripts",
+				},
+				Items: []corev1.KeyToPath{
+					{
+						Key:  "cluster_redis.sh",
+						Path: "cluster_redis.sh",
+					},
+					{
+						Key:  "common.sh",
+						Path: "common.sh",
+					},
+				},
+				DefaultMode: &scriptsPerms,
+			},
+		},
+	}}
+	volumes = append(volumes, getTLSVolumes(r)...)
+
+	volumeMounts := []corev1.VolumeMount{{
+		MountPath: "/var/lib/operator-scripts",
+		ReadOnly:  true,
+		Name:      "operator-scripts",
+	}}
+	volumeMounts = append(volumeMounts, getTLSVolumeMounts(r)...)
+
+	labels := map[string]string{
+		common.AppSelector:   ClusterAppLabel,
+		common.OwnerSelector: r.Name,
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterJobName(r),
+			Namespace: r.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](3),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					// common.sh reads the namespace and the token of the service account
+					ServiceAccountName: r.RbacResourceName(),
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:         "cluster",
+						Image:        r.Spec.ContainerImage,
+						Command:      []string{"/var/lib/operator-scripts/cluster_redis.sh"},
+						Env:          env,
+						VolumeMounts: volumeMounts,
+					}},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+// ClusterInfoArgs returns the redis-cli arguments that print the state of the cluster
+func ClusterInfoArgs() []string {
+	return []string{"CLUSTER", "INFO"}
+}
+
+// ClusterNodesArgs returns the redis-cli arguments that list the nodes of the cluster
+func ClusterNodesArgs() []string {
+	return []string{"CLUSTER", "NODES"}
+}
+
+// ParseClusterState returns the cluster_state from the CLUSTER INFO reply
+func ParseClusterState(output string) string {
+	return parseClusterInfo(output, "cluster_state")
+}
+
+// ClusterUnhealthy - whether the CLUSTER INFO reply shows a failed cluster or slots no node
+// serves, e.g. after an interrupted resharding or the loss of a master without replica
+func ClusterUnhealthy(output string) bool {
+	return ParseClusterState(output) != "ok" ||
+		parseClusterInfo(output, "cluster_slots_assigned") != strconv.Itoa(ClusterSlots)
+}
+
+// ClusterRepairDue - whether the cluster job has to run again without a change of the shape,
+// because the cluster is unhealthy for longer than ClusterRepairDelay
+func ClusterRepairDue(r *redisv1.Redis, now time.Time) bool {
+	return r.Status.Cluster != nil && r.Status.Cluster.UnhealthySince != nil &&
+		now.Sub(r.Status.Cluster.UnhealthySince.Time) > ClusterRepairDelay
+}
+
+// parseClusterInfo returns the value of the given field of the CLUSTER INFO reply
+func parseClusterInfo(output string, field string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if value, found := strings.CutPrefix(strings.TrimSpace(line), field+":"); found {
+			return value
+		}
+	}
+	return ""
+}
+
+// ParseClusterNodes returns the nodes from the CLUSTER NODES reply, the masters with the slots
+// they serve and the replicas with the pod of their master
+//
+//	<id> <ip:port@cport[,hostname]> <flags> <master> <ping-sent> <pong-recv> <config-epoch> <link-state> <slot> ...
+func ParseClusterNodes(output string) []redisv1.ClusterNodeStatus {
+	type node struct {
+		status   redisv1.ClusterNodeStatus
+		masterID string
+	}
+	nodes := []node{}
+	pods := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			continue
+		}
+		flags := strings.Split(fields[2], ",")
+		if slices.Contains(flags, "handshake") || slices.Contains(flags, "noaddr") {
+			continue
+		}
+		// the nodes announce the FQDN of their pod as hostname
+		host := fields[1]
+		if _, hostname, found := strings.Cut(host, ","); found {
+			host = hostname
+		}
+		n := node{
+			status: redisv1.ClusterNodeStatus{
+				Pod:     PodNameFromHost(host),
+				ID:      fields[0],
+				Role:    "master",
+				Slots:   strings.Join(fields[8:], " "),
+				Failing: slices.Contains(flags, "fail") || slices.Contains(flags, "fail?"),
+			},
+		}
+		if slices.Contains(flags, "slave") {
+			n.status.Role = "replica"
+			n.masterID = fields[3]
+		}
+		pods[n.status.ID] = n.status.Pod
+		nodes = append(nodes, n)
+	}
+
+	result := []redisv1.ClusterNodeStatus{}
+	for _, n := range nodes {
+		if n.masterID != "" {
+			n.status.Master = pods[n.masterID]
+		}
+		result = append(result, n.status)
+	}
+	// the order of the reply changes, the status does not
+	slices.SortFunc(result, func(a, b redisv1.ClusterNodeStatus) int {
+		return cmp.Or(cmp.Compare(len(a.Pod), len(b.Pod)), cmp.Compare(a.Pod, b.Pod))
+	})
+	return result
+}
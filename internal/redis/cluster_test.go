@@ -0,0 +1,170 @@
+package redis
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func clusterRedis() *redisv1.Redis {
+	r := &redisv1.Redis{}
+	r.Name = "redis"
+	r.Namespace = "openstack"
+	r.Spec.Replicas = ptr.To[int32](1)
+	r.Spec.Mode = redisv1.RedisModeCluster
+	r.Spec.Cluster.Shards = 3
+	r.Spec.Cluster.ReplicasPerShard = ptr.To[int32](1)
+	return r
+}
+
+func TestParseClusterNodes(t *testing.T) {
+	output := strings.Join([]string{
+		"07c37dfe 10.0.0.2:6379@16379,redis-redis-1.redis-redis.openstack.svc.cluster.local master - 0 1426238317239 2 connected 5461-10922",
+		"67ed2db8 10.0.0.1:6379@16379,redis-redis-0.redis-redis.openstack.svc.cluster.local myself,master - 0 0 1 connected 0-5460",
+		"292f8b36 10.0.0.4:6379@16379,redis-redis-3.redis-redis.openstack.svc.cluster.local slave 67ed2db8 0 1426238316232 1 connected",
+		"6ec23923 10.0.0.11:6379@16379,redis-redis-10.redis-redis.openstack.svc.cluster.local master,fail - 1426238316232 0 3 disconnected 10923-16383",
+		"824fe116 10.0.0.9:6379@16379 handshake - 0 0 0 connected",
+	}, "\r\n")
+	nodes := ParseClusterNodes(output)
+	want := []redisv1.ClusterNodeStatus{
+		{Pod: "redis-redis-0", ID: "67ed2db8", Role: "master", Slots: "0-5460"},
+		{Pod: "redis-redis-1", ID: "07c37dfe", Role: "master", Slots: "5461-10922"},
+		{Pod: "redis-redis-3", ID: "292f8b36", Role: "replica", Master: "redis-redis-0"},
+		{Pod: "redis-redis-10", ID: "6ec23923", Role: "master", Slots: "10923-16383", Failing: true},
+	}
+	if !slices.Equal(nodes, want) {
+		t.Errorf("got %+v, want %+v", nodes, want)
+	}
+}
+
+func TestParseClusterState(t *testing.T) {
+	if state := ParseClusterState("cluster_enabled:1\r\ncluster_state:ok\r\ncluster_slots_assigned:16384\r\n"); state != "ok" {
+		t.Errorf("state = %q, want ok", state)
+	}
+	if state := ParseClusterState(""); state != "" {
+		t.Errorf("state = %q, want empty", state)
+	}
+}
+
+func TestClusterUnhealthy(t *testing.T) {
+	for info, want := range map[string]bool{
+		"cluster_state:ok\r\ncluster_slots_assigned:16384\r\n":   false,
+		"cluster_state:fail\r\ncluster_slots_assigned:16384\r\n": true,
+		"cluster_state:ok\r\ncluster_slots_assigned:10923\r\n":   true,
+		"cluster_state:fail\r\ncluster_slots_assigned:0\r\n":     true,
+	} {
+		if got := ClusterUnhealthy(info); got != want {
+			t.Errorf("ClusterUnhealthy(%q) = %t, want %t", info, got, want)
+		}
+	}
+}
+
+func TestClusterRepairDue(t *testing.T) {
+	r := clusterRedis()
+	now := time.Now()
+	if ClusterRepairDue(r, now) {
+		t.Errorf("repair due before the cluster got created")
+	}
+	r.Status.Cluster = &redisv1.ClusterStatus{Shards: 3, ReplicasPerShard: 1, State: "ok"}
+	if ClusterRepairDue(r, now) {
+		t.Errorf("repair due for a healthy cluster")
+	}
+	// the shape did not change, a failing cluster gets the time to fail over by itself
+	r.Status.Cluster.State = "fail"
+	r.Status.Cluster.UnhealthySince = ptr.To(metav1.NewTime(now.Add(-ClusterRepairDelay / 2)))
+	if ClusterRepairDue(r, now) {
+		t.Errorf("repair due before the nodes could fail over")
+	}
+	r.Status.Cluster.UnhealthySince = ptr.To(metav1.NewTime(now.Add(-2 * ClusterRepairDelay)))
+	if !ClusterRepairDue(r, now) {
+		t.Errorf("no repair for a cluster failing for %s", 2*ClusterRepairDelay)
+	}
+}
+
+func TestClusterJob(t *testing.T) {
+	r := clusterRedis()
+	if n := Replicas(r); n != 6 {
+		t.Fatalf("replicas = %d, want 6", n)
+	}
+
+	env := ClusterJob(r).Spec.Template.Spec.Containers[0].Env
+	if nodes := strings.Fields(envValue(env, "CLUSTER_NODES")); len(nodes) != 6 ||
+		!strings.HasPrefix(nodes[5], "redis-redis-5.redis-redis.openstack.svc.") {
+		t.Errorf("CLUSTER_NODES = %v", nodes)
+	}
+	if removed := envValue(env, "CLUSTER_REMOVED_NODES"); removed != "" {
+		t.Errorf("CLUSTER_REMOVED_NODES = %q, want none", removed)
+	}
+
+	// Scaling down keeps the nodes of the removed shard until the job succeeded
+	r.Status.Cluster = &redisv1.ClusterStatus{Shards: 3, ReplicasPerShard: 1}
+	r.Spec.Cluster.ReplicasPerShard = ptr.To[int32](0)
+	if n := Replicas(r); n != 6 {
+		t.Errorf("replicas while resharding = %d, want 6", n)
+	}
+	env = ClusterJob(r).Spec.Template.Spec.Containers[0].Env
+	if nodes := strings.Fields(envValue(env, "CLUSTER_NODES")); len(nodes) != 3 {
+		t.Errorf("CLUSTER_NODES = %v, want 3 nodes", nodes)
+	}
+	if removed := strings.Fields(envValue(env, "CLUSTER_REMOVED_NODES")); len(removed) != 3 {
+		t.Errorf("CLUSTER_REMOVED_NODES = %v, want 3 nodes", removed)
+	}
+	if shards := envValue(env, "CLUSTER_SHARDS"); shards != "3" {
+		t.Errorf("CLUSTER_SHARDS = %s", shards)
+	}
+	if hosts := ClusterNodeHosts(r); len(hosts) != 3 || hosts[0] != "redis-redis-0.redis-redis.openstack.svc:6379" {
+		t.Errorf("cluster node hosts = %v", hosts)
+	}
+
+	// The hash only follows the shape
+	before, _ := ClusterHash(r)
+	r.Spec.ContainerImage = "redis:new"
+	if after, _ := ClusterHash(r); after != before {
+		t.Errorf("hash changed with the image")
+	}
+}
+
+func TestClusterModeResources(t *testing.T) {
+	r := clusterRedis()
+	r.Spec.Metrics.Enabled = true
+
+	sts := StatefulSet(r, "hash", nil)
+	if *sts.Spec.Replicas != 6 {
+		t.Errorf("statefulset replicas = %d, want 6", *sts.Spec.Replicas)
+	}
+	containers := sts.Spec.Template.Spec.Containers
+	names := []string{}
+	for _, c := range containers {
+		names = append(names, c.Name)
+	}
+	if !slices.Equal(names, []string{"redis", "redis-exporter"}) {
+		t.Fatalf("containers = %v", names)
+	}
+	if !slices.Contains(containers[0].Command, "/var/lib/operator-scripts/start_redis_cluster.sh") {
+		t.Errorf("redis command = %v", containers[0].Command)
+	}
+
+	if _, found := Service(r).Spec.Selector[MasterLabel]; found {
+		t.Errorf("the service of the cluster mode selects the master")
+	}
+	ports := []string{}
+	for _, p := range HeadlessService(r).Spec.Ports {
+		ports = append(ports, p.Name)
+	}
+	if !slices.Equal(ports, []string{"redis", "cluster-bus", "metrics"}) {
+		t.Errorf("headless service ports = %v", ports)
+	}
+
+	data := ConnectionData(r, "")
+	if data[redisv1.ConnectionClusterURLKey] != "redis://redis.openstack.svc:6379" {
+		t.Errorf("cluster url = %s", data[redisv1.ConnectionClusterURLKey])
+	}
+	if _, found := data[redisv1.ConnectionSentinelURLKey]; found {
+		t.Errorf("cluster mode has a sentinel url")
+	}
+}
@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+)
+
+// ConnectionSecretName returns the name of the secret holding the connection details
+func ConnectionSecretName(r *redisv1.Redis) string {
+	return r.Name + redisv1.ConnectionSecretSuffix
+}
+
+// SentinelHosts returns the host:port of the sentinel of every pod
+func SentinelHosts(r *redisv1.Redis) []string {
+	hosts := []string{}
+	for _, pod := range PodNames(r) {
+		hosts = append(hosts, fmt.Sprintf("%s.%s-redis.%s.svc:%d", pod, r.Name, r.Namespace, redisv1.SentinelPort))
+	}
+	return hosts
+}
+
+// scheme returns the URL scheme, with an s suffix when TLS is enabled
+func scheme(r *redisv1.Redis, base string) string {
+	if r.Spec.TLS.Enabled() {
+		return base + "s"
+	}
+	return base
+}
+
+// masterURL returns the URL of the service that points to the current master, or to all nodes
+// in cluster mode
+func masterURL(r *redisv1.Redis, user *url.Userinfo) string {
+	u := url.URL{
+		Scheme: scheme(r, "redis"),
+		User:   user,
+		Host:   fmt.Sprintf("%s.%s.svc:%d", r.Name, r.Namespace, redisv1.RedisPort),
+	}
+	return u.String()
+}
+
+// sentinelURL returns the redis+sentinel:// URL that lists all sentinels and the monitored master
+func sentinelURL(r *redisv1.Redis, user *url.Userinfo) string {
+	u := url.URL{
+		Scheme: scheme(r, "redis") + "+sentinel",
+		User:   user,
+		Host:   strings.Join(SentinelHosts(r), ","),
+		Path:   "/" + redisv1.SentinelMasterName,
+	}
+	return u.String()
+}
+
+// ConnectionData returns the content of the connection secret. The password of the default
+// user is only included when authentication is enabled.
+func ConnectionData(r *redisv1.Redis, password string) map[string]string {
+	var user *url.Userinfo
+	if r.Spec.Auth.Enabled {
+		user = url.UserPassword("", password)
+	}
+	data := map[string]string{
+		redisv1.ConnectionSentinelURLKey:    sentinelURL(r, user),
+		redisv1.ConnectionMasterURLKey:      masterURL(r, user),
+		redisv1.ConnectionSentinelHostsKey:  strings.Join(SentinelHosts(r), ","),
+		redisv1.ConnectionSentinelMasterKey: redisv1.SentinelMasterName,
+	}
+	if r.Spec.IsClusterMode() {
+		// the service selects all nodes, any of them redirects to the node serving a key
+		data = map[string]string{
+			redisv1.ConnectionClusterURLKey:   masterURL(r, user),
+			redisv1.ConnectionClusterNodesKey: strings.Join(ClusterNodeHosts(r), ","),
+		}
+	}
+	if r.Spec.TLS.Enabled() {
+		data[redisv1.ConnectionCAPathKey] = tls.DownstreamTLSCABundlePath
+	}
+	if r.Spec.Auth.Enabled {
+		data[redisv1.ConnectionPasswordKey] = password
+	}
+	return data
+}
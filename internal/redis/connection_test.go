@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"maps"
+	"testing"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+	"k8s.io/utils/ptr"
+)
+
+func TestConnectionData(t *testing.T) {
+	r := &redisv1.Redis{}
+	r.Name = "redis"
+	r.Namespace = "openstack"
+	r.Spec.Replicas = ptr.To[int32](2)
+
+	hosts := "redis-redis-0.redis-redis.openstack.svc:26379,redis-redis-1.redis-redis.openstack.svc:26379"
+	want := map[string]string{
+		redisv1.ConnectionSentinelURLKey:    "redis+sentinel://" + hosts + "/redis",
+		redisv1.ConnectionMasterURLKey:      "redis://redis.openstack.svc:6379",
+		redisv1.ConnectionSentinelHostsKey:  hosts,
+		redisv1.ConnectionSentinelMasterKey: "redis",
+	}
+	if got := ConnectionData(r, ""); !maps.Equal(got, want) {
+		t.Errorf("ConnectionData() = %v, want %v", got, want)
+	}
+
+	r.Spec.TLS.SecretName = ptr.To("redis-tls")
+	r.Spec.Auth.Enabled = true
+	want = map[string]string{
+		redisv1.ConnectionSentinelURLKey:    "rediss+sentinel://:secret@" + hosts + "/redis",
+		redisv1.ConnectionMasterURLKey:      "rediss://:secret@redis.openstack.svc:6379",
+		redisv1.ConnectionSentinelHostsKey:  hosts,
+		redisv1.ConnectionSentinelMasterKey: "redis",
+		redisv1.ConnectionCAPathKey:         tls.DownstreamTLSCABundlePath,
+		redisv1.ConnectionPasswordKey:       "secret",
+	}
+	if got := ConnectionData(r, "secret"); !maps.Equal(got, want) {
+		t.Errorf("ConnectionData() with TLS and auth = %v, want %v", got, want)
+	}
+}
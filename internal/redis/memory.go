@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// MaxMemoryBytes returns the maxmemory of the redis servers in bytes, 0 for no limit. The webhook
+// validates the setting, an invalid one results in no limit.
+func MaxMemoryBytes(r *redisv1.Redis) int64 {
+	limit := r.Spec.Resources.Limits.Memory()
+	value := r.Spec.MaxMemory
+	if value == "" {
+		if limit.IsZero() {
+			return 0
+		}
+		value = redisv1.DefaultMaxMemory
+	}
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.ParseInt(percent, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return limit.Value() * p / 100
+	}
+	size, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0
+	}
+	return size.Value()
+}
+
+// MemoryConfig returns the redis.conf directives limiting the memory of the dataset
+func MemoryConfig(r *redisv1.Redis) string {
+	return strings.Join([]string{
+		fmt.Sprintf("maxmemory %d", MaxMemoryBytes(r)),
+		fmt.Sprintf("maxmemory-policy %s", cmp.Or(r.Spec.MaxMemoryPolicy, "noeviction")),
+	}, "\n")
+}
+
+// ExtraConfig returns the additional redis.conf directives sorted by name, so that the config
+// hash only changes when they do
+func ExtraConfig(r *redisv1.Redis) string {
+	lines := []string{}
+	for _, key := range slices.Sorted(maps.Keys(r.Spec.ExtraConfig)) {
+		lines = append(lines, fmt.Sprintf("%s %s", key, r.Spec.ExtraConfig[key]))
+	}
+	return strings.Join(lines, "\n")
+}
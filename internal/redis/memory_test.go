@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"testing"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestMaxMemoryBytes(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxMemory string
+		limit     string
+		want      int64
+	}{
+		{name: "no limit", want: 0},
+		{name: "default percentage of the limit", limit: "1Gi", want: 805306368},
+		{name: "percentage", maxMemory: "50%", limit: "1Gi", want: 536870912},
+		{name: "quantity", maxMemory: "256Mi", limit: "1Gi", want: 268435456},
+		{name: "quantity without limit", maxMemory: "256Mi", want: 268435456},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &redisv1.Redis{}
+			r.Spec.MaxMemory = tt.maxMemory
+			if tt.limit != "" {
+				r.Spec.Resources.Limits = corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse(tt.limit),
+				}
+			}
+			if got := MaxMemoryBytes(r); got != tt.want {
+				t.Errorf("MaxMemoryBytes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtraConfig(t *testing.T) {
+	r := &redisv1.Redis{}
+	if got := ExtraConfig(r); got != "" {
+		t.Errorf("ExtraConfig() = %q, want no directives", got)
+	}
+	r.Spec.ExtraConfig = map[string]string{
+		"hz":                   "20",
+		"appendfsync":          "always",
+		"latency-tracking":     "no",
+		"lazyfree-lazy-expire": "yes",
+	}
+	want := "appendfsync always\nhz 20\nlatency-tracking no\nlazyfree-lazy-expire yes"
+	if got := ExtraConfig(r); got != want {
+		t.Errorf("ExtraConfig() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"fmt"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	common "github.com/openstack-k8s-operators/lib-common/modules/common"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/clusterdns"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// RedisMetricsPort - port of the redis_exporter scraping the redis server
+	RedisMetricsPort int32 = 9121
+	// SentinelMetricsPort - port of the redis_exporter scraping the sentinel
+	SentinelMetricsPort int32 = 9355
+)
+
+// exporterContainer returns a redis_exporter sidecar scraping the server on port. With TLS
+// enabled it connects to the FQDN of the pod, which the service certificate is valid for,
+// and presents the certificate as client certificate. The exporter reads REDIS_PASSWORD.
+func exporterContainer(r *redisv1.Redis, name string, port int32, metricsName string, metricsPort int32) corev1.Container {
+	args := []string{
+		fmt.Sprintf("--web.listen-address=:%d", metricsPort),
+	}
+	env := []corev1.EnvVar{}
+	volumeMounts := []corev1.VolumeMount{}
+
+	if r.Spec.TLS.Enabled() {
+		env = append(env, corev1.EnvVar{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		}, corev1.EnvVar{
+			Name:  "SVC_FQDN",
+			Value: r.Name + "-redis." + r.Namespace + ".svc." + clusterdns.GetDNSClusterDomain(),
+		})
+		args = append(args,
+			fmt.Sprintf("--redis.addr=rediss://$(POD_NAME).$(SVC_FQDN):%d", port),
+			fmt.Sprintf("--tls-client-cert-file=%s/%s.crt", tls.DefaultCertMountDir, RedisCertPrefix),
+			fmt.Sprintf("--tls-client-key-file=%s/%s.key", tls.DefaultKeyMountDir, RedisCertPrefix),
+		)
+		if r.Spec.TLS.CaBundleSecretName != "" {
+			args = append(args, "--tls-ca-cert-file="+tls.DownstreamTLSCABundlePath)
+		}
+		volumeMounts = append(volumeMounts, getTLSVolumeMounts(r)...)
+	} else {
+		args = append(args, fmt.Sprintf("--redis.addr=redis://localhost:%d", port))
+	}
+	env = append(env, authEnvVars(r)...)
+
+	return corev1.Container{
+		Image:        r.Spec.ExporterImage,
+		Name:         name,
+		Args:         args,
+		Env:          env,
+		VolumeMounts: volumeMounts,
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: metricsPort,
+			Name:          metricsName,
+		}},
+	}
+}
+
+// exporterContainers returns the sidecars exporting the replication metrics of the redis server
+// and the master election metrics of the sentinel, the cluster mode runs no sentinel
+func exporterContainers(r *redisv1.Redis) []corev1.Container {
+	containers := []corev1.Container{
+		exporterContainer(r, "redis-exporter", 6379, "metrics", RedisMetricsPort),
+	}
+	if !r.Spec.IsClusterMode() {
+		containers = append(containers,
+			exporterContainer(r, "sentinel-exporter", 26379, "sentinel-metrics", SentinelMetricsPort))
+	}
+	return containers
+}
+
+// metricsServicePorts returns the ports of the exporters on the headless service
+func metricsServicePorts(r *redisv1.Redis) []corev1.ServicePort {
+	ports := []corev1.ServicePort{
+		{Name: "metrics", Protocol: "TCP", Port: RedisMetricsPort},
+	}
+	if !r.Spec.IsClusterMode() {
+		ports = append(ports, corev1.ServicePort{Name: "sentinel-metrics", Protocol: "TCP", Port: SentinelMetricsPort})
+	}
+	return ports
+}
+
+// ServiceMonitorName returns the name of the ServiceMonitor, the name of the headless service it
+// scrapes, so that it doesn't collide with the ServiceMonitor of a Memcached with the same name
+func ServiceMonitorName(r *redisv1.Redis) string {
+	return r.Name + "-redis"
+}
+
+// SetServiceMonitorSpec sets the spec of the ServiceMonitor scraping the metrics ports of the
+// headless service of the Redis. The service of the master shares its labels but has no metrics
+// ports, so every pod is scraped once.
+func SetServiceMonitorSpec(sm *unstructured.Unstructured, r *redisv1.Redis) error {
+	endpoints := []interface{}{}
+	for _, port := range metricsServicePorts(r) {
+		endpoints = append(endpoints, map[string]interface{}{
+			"port":     port.Name,
+			"interval": r.Spec.Metrics.ScrapeInterval,
+		})
+	}
+	spec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				common.AppSelector:   "redis",
+				common.OwnerSelector: r.Name,
+			},
+		},
+		"endpoints": endpoints,
+	}
+	return unstructured.SetNestedMap(sm.Object, spec, "spec")
+}
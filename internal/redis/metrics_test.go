@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"slices"
+	"testing"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	"github.com/openstack-k8s-operators/infra-operator/internal/monitoring"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/tls"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+)
+
+func TestExporterContainers(t *testing.T) {
+	r := &redisv1.Redis{}
+	r.Name = "redis"
+	r.Namespace = "openstack"
+	r.Spec.Replicas = ptr.To[int32](3)
+	r.Spec.ExporterImage = "redis-exporter:latest"
+
+	sts := StatefulSet(r, "hash", nil)
+	if n := len(sts.Spec.Template.Spec.Containers); n != 2 {
+		t.Fatalf("got %d containers without metrics, want 2", n)
+	}
+	if n := len(HeadlessService(r).Spec.Ports); n != 2 {
+		t.Errorf("got %d service ports without metrics, want 2", n)
+	}
+
+	r.Spec.Metrics.Enabled = true
+	r.Spec.Auth.Enabled = true
+	sts = StatefulSet(r, "hash", nil)
+	containers := sts.Spec.Template.Spec.Containers
+	if n := len(containers); n != 4 {
+		t.Fatalf("got %d containers with metrics, want 4", n)
+	}
+	exporter := containers[2]
+	if exporter.Image != "redis-exporter:latest" {
+		t.Errorf("image = %s", exporter.Image)
+	}
+	if !slices.Contains(exporter.Args, "--redis.addr=redis://localhost:6379") {
+		t.Errorf("redis exporter args = %v", exporter.Args)
+	}
+	if !slices.Contains(containers[3].Args, "--redis.addr=redis://localhost:26379") {
+		t.Errorf("sentinel exporter args = %v", containers[3].Args)
+	}
+	if !slices.ContainsFunc(exporter.Env, func(e corev1.EnvVar) bool { return e.Name == "REDIS_PASSWORD" }) {
+		t.Errorf("exporter misses the password, env = %v", exporter.Env)
+	}
+	if n := len(HeadlessService(r).Spec.Ports); n != 4 {
+		t.Errorf("got %d service ports with metrics, want 4", n)
+	}
+
+	r.Spec.TLS.SecretName = ptr.To("redis-tls")
+	r.Spec.TLS.CaBundleSecretName = "combined-ca-bundle"
+	exporter = StatefulSet(r, "hash", nil).Spec.Template.Spec.Containers[2]
+	if !slices.Contains(exporter.Args, "--redis.addr=rediss://$(POD_NAME).$(SVC_FQDN):6379") {
+		t.Errorf("TLS exporter args = %v", exporter.Args)
+	}
+	if !slices.Contains(exporter.Args, "--tls-ca-cert-file="+tls.DownstreamTLSCABundlePath) {
+		t.Errorf("TLS exporter misses the CA bundle, args = %v", exporter.Args)
+	}
+	if len(exporter.VolumeMounts) == 0 {
+		t.Errorf("TLS exporter misses the certificate mounts")
+	}
+}
+
+func TestServiceMonitorSpec(t *testing.T) {
+	r := &redisv1.Redis{}
+	r.Name = "redis"
+	r.Namespace = "openstack"
+	r.Spec.Metrics.ScrapeInterval = "15s"
+
+	sm := monitoring.NewServiceMonitor(ServiceMonitorName(r), r.Namespace)
+	if err := SetServiceMonitorSpec(sm, r); err != nil {
+		t.Fatal(err)
+	}
+	endpoints, _, err := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ports := []string{}
+	for _, e := range endpoints {
+		endpoint := e.(map[string]interface{})
+		if endpoint["interval"] != "15s" {
+			t.Errorf("interval = %v, want 15s", endpoint["interval"])
+		}
+		ports = append(ports, endpoint["port"].(string))
+	}
+	if !slices.Equal(ports, []string{"metrics", "sentinel-metrics"}) {
+		t.Errorf("endpoint ports = %v", ports)
+	}
+}
@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// DataVolumeName - name of the volume claim template holding the persisted data
+	DataVolumeName = "redis-data"
+	// DataMountPath - working directory of redis-server where it writes the RDB and AOF files
+	DataMountPath = "/var/lib/redis"
+)
+
+// PersistenceConfig returns the redis.conf directives for the persistence mode
+func PersistenceConfig(r *redisv1.Redis) string {
+	switch r.Spec.Persistence.Mode {
+	case redisv1.PersistenceModeRDB:
+		return strings.Join([]string{
+			fmt.Sprintf("save %s", r.Spec.Persistence.SnapshotSchedule),
+			"appendonly no",
+		}, "\n")
+	case redisv1.PersistenceModeAOF:
+		return strings.Join([]string{
+			`save ""`,
+			"appendonly yes",
+			"appendfsync everysec",
+			"aof-use-rdb-preamble yes",
+		}, "\n")
+	default:
+		return "appendonly no"
+	}
+}
+
+func dataVolumeClaimTemplate(r *redisv1.Redis) corev1.PersistentVolumeClaim {
+	// the webhook validates the request, an invalid one results in a PVC
+	// without storage request which the API server rejects
+	requests := corev1.ResourceList{}
+	if size, err := resource.ParseQuantity(r.Spec.Persistence.StorageRequest); err == nil {
+		requests[corev1.ResourceStorage] = size
+	}
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DataVolumeName,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteOnce,
+			},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: requests,
+			},
+		},
+	}
+	if r.Spec.Persistence.StorageClass != "" {
+		pvc.Spec.StorageClassName = ptr.To(r.Spec.Persistence.StorageClass)
+	}
+	return pvc
+}
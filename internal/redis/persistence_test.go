@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"testing"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	"k8s.io/utils/ptr"
+)
+
+func TestPersistenceConfig(t *testing.T) {
+	r := &redisv1.Redis{}
+	r.Spec.Persistence.SnapshotSchedule = "900 1"
+	tests := map[string]string{
+		redisv1.PersistenceModeNone: "appendonly no",
+		redisv1.PersistenceModeRDB:  "save 900 1\nappendonly no",
+		redisv1.PersistenceModeAOF:  "save \"\"\nappendonly yes\nappendfsync everysec\naof-use-rdb-preamble yes",
+	}
+	for mode, want := range tests {
+		r.Spec.Persistence.Mode = mode
+		if got := PersistenceConfig(r); got != want {
+			t.Errorf("PersistenceConfig() for %s = %q, want %q", mode, got, want)
+		}
+	}
+}
+
+func TestStatefulSetVolumeClaimTemplates(t *testing.T) {
+	r := &redisv1.Redis{}
+	r.Name = "redis"
+	r.Spec.Replicas = ptr.To[int32](3)
+
+	sts := StatefulSet(r, "hash", nil)
+	if len(sts.Spec.VolumeClaimTemplates) != 0 {
+		t.Errorf("unexpected volume claim templates without persistence")
+	}
+
+	r.Spec.Persistence = redisv1.PersistenceSection{
+		Mode:           redisv1.PersistenceModeAOF,
+		StorageClass:   "local",
+		StorageRequest: "5Gi",
+	}
+	sts = StatefulSet(r, "hash", nil)
+	if len(sts.Spec.VolumeClaimTemplates) != 1 {
+		t.Fatalf("expected one volume claim template, got %d", len(sts.Spec.VolumeClaimTemplates))
+	}
+	pvc := sts.Spec.VolumeClaimTemplates[0]
+	if pvc.Name != DataVolumeName || *pvc.Spec.StorageClassName != "local" ||
+		pvc.Spec.Resources.Requests.Storage().String() != "5Gi" {
+		t.Errorf("unexpected volume claim template %+v", pvc)
+	}
+
+	mounted := false
+	for _, vm := range sts.Spec.Template.Spec.Containers[0].VolumeMounts {
+		if vm.Name == DataVolumeName && vm.MountPath == DataMountPath {
+			mounted = true
+		}
+	}
+	if !mounted {
+		t.Errorf("the data volume is not mounted into the redis container")
+	}
+}
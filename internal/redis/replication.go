@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+)
+
+// SentinelMasterArgs returns the redis-cli arguments that ask the local sentinel for the master
+func SentinelMasterArgs() []string {
+	return []string{"-p", strconv.Itoa(redisv1.SentinelPort),
+		"SENTINEL", "GET-MASTER-ADDR-BY-NAME", redisv1.SentinelMasterName}
+}
+
+// SentinelQuorumArgs returns the redis-cli arguments that check whether the sentinels can
+// authorize a failover
+func SentinelQuorumArgs() []string {
+	return []string{"-p", strconv.Itoa(redisv1.SentinelPort),
+		"SENTINEL", "CKQUORUM", redisv1.SentinelMasterName}
+}
+
+// SentinelFailoverArgs returns the redis-cli arguments that make the local sentinel fail the
+// master over to a replica
+func SentinelFailoverArgs() []string {
+	return []string{"-p", strconv.Itoa(redisv1.SentinelPort),
+		"SENTINEL", "FAILOVER", redisv1.SentinelMasterName}
+}
+
+// ReplicationInfoArgs returns the redis-cli arguments that print the replication state
+func ReplicationInfoArgs() []string {
+	return []string{"INFO", "replication"}
+}
+
+// PodNameFromHost returns the pod name of a host announced by the sentinels, the first label
+// of the pod FQDN
+func PodNameFromHost(host string) string {
+	return strings.SplitN(host, ".", 2)[0]
+}
+
+// ParseSentinelMaster returns the host of the master from the GET-MASTER-ADDR-BY-NAME reply,
+// empty when the sentinel does not know a master
+func ParseSentinelMaster(output string) string {
+	lines := strings.Fields(output)
+	if len(lines) != 2 {
+		return ""
+	}
+	return lines[0]
+}
+
+// ParseReplicationInfo returns the role and the replicas from the INFO replication reply. The
+// replicas are only listed by a master.
+func ParseReplicationInfo(output string) (string, []redisv1.ReplicaStatus) {
+	var role string
+	var masterOffset int64
+	replicas := []redisv1.ReplicaStatus{}
+	offsets := []int64{}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		switch {
+		case key == "role":
+			role = value
+		case key == "master_repl_offset":
+			masterOffset, _ = strconv.ParseInt(value, 10, 64)
+		case strings.HasPrefix(key, "slave") && strings.Contains(value, "="):
+			// slave0:ip=<fqdn>,port=6379,state=online,offset=1234,lag=0
+			replica := redisv1.ReplicaStatus{}
+			var offset int64
+			for _, field := range strings.Split(value, ",") {
+				k, v, _ := strings.Cut(field, "=")
+				switch k {
+				case "ip":
+					replica.Pod = PodNameFromHost(v)
+				case "state":
+					replica.State = v
+				case "offset":
+					offset, _ = strconv.ParseInt(v, 10, 64)
+				case "lag":
+					replica.LagSeconds, _ = strconv.ParseInt(v, 10, 64)
+				}
+			}
+			replicas = append(replicas, replica)
+			offsets = append(offsets, offset)
+		}
+	}
+	for i := range replicas {
+		replicas[i].OffsetLag = max(masterOffset-offsets[i], 0)
+	}
+	return role, replicas
+}
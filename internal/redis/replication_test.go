@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"slices"
+	"testing"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+)
+
+func TestParseSentinelMaster(t *testing.T) {
+	host := ParseSentinelMaster("redis-redis-1.redis-redis.openstack.svc.cluster.local\n6379\n")
+	if host != "redis-redis-1.redis-redis.openstack.svc.cluster.local" {
+		t.Errorf("ParseSentinelMaster() = %s", host)
+	}
+	if pod := PodNameFromHost(host); pod != "redis-redis-1" {
+		t.Errorf("PodNameFromHost() = %s, want redis-redis-1", pod)
+	}
+	if host := ParseSentinelMaster("\n"); host != "" {
+		t.Errorf("ParseSentinelMaster() without master = %s", host)
+	}
+}
+
+func TestParseReplicationInfo(t *testing.T) {
+	output := "# Replication\r\n" +
+		"role:master\r\n" +
+		"connected_slaves:2\r\n" +
+		"slave0:ip=redis-redis-1.redis-redis.openstack.svc.cluster.local,port=6379,state=online,offset=1000,lag=0\r\n" +
+		"slave1:ip=redis-redis-2.redis-redis.openstack.svc.cluster.local,port=6379,state=wait_bgsave,offset=0,lag=3\r\n" +
+		"master_failover_state:no-failover\r\n" +
+		"master_repl_offset:1200\r\n"
+
+	role, replicas := ParseReplicationInfo(output)
+	if role != "master" {
+		t.Errorf("role = %s, want master", role)
+	}
+	want := []redisv1.ReplicaStatus{
+		{Pod: "redis-redis-1", State: "online", LagSeconds: 0, OffsetLag: 200},
+		{Pod: "redis-redis-2", State: "wait_bgsave", LagSeconds: 3, OffsetLag: 1200},
+	}
+	if !slices.Equal(replicas, want) {
+		t.Errorf("replicas = %+v, want %+v", replicas, want)
+	}
+
+	role, replicas = ParseReplicationInfo("# Replication\r\nrole:slave\r\nmaster_host:redis-redis-0\r\n")
+	if role != "slave" || len(replicas) != 0 {
+		t.Errorf("unexpected role %s and replicas %+v of a replica", role, replicas)
+	}
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"cmp"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MasterLabel - label check_redis_endpoints.sh sets on the pod of the master, the service of the
+// Redis selects it
+const MasterLabel = "redis/master"
+
+// RolloutAction - the next step of replacing the pods of the OnDelete statefulset
+type RolloutAction int
+
+const (
+	// RolloutDone - all pods run the current revision
+	RolloutDone RolloutAction = iota
+	// RolloutWait - a replaced pod is not ready yet
+	RolloutWait
+	// RolloutDelete - delete the pod, the statefulset recreates it with the current revision
+	RolloutDelete
+	// RolloutFailover - hand the master role of the pod over before deleting it
+	RolloutFailover
+)
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// NextRolloutAction returns the next step to bring the pods of the statefulset to the update
+// revision and the pod it applies to. Pods get replaced one at a time, failed pods first as they
+// don't serve anyway, then the replicas and the master last once a replica took over its role.
+func NextRolloutAction(sts *appsv1.StatefulSet, pods []corev1.Pod) (RolloutAction, string) {
+	// The update revision is only current once the statefulset controller saw the spec
+	if sts.Status.ObservedGeneration != sts.Generation || sts.Status.UpdateRevision == "" {
+		return RolloutWait, ""
+	}
+
+	outdated := []corev1.Pod{}
+	waiting := false
+	for _, pod := range pods {
+		if !pod.DeletionTimestamp.IsZero() {
+			return RolloutWait, pod.Name
+		}
+		if pod.Labels[appsv1.ControllerRevisionHashLabelKey] == sts.Status.UpdateRevision {
+			waiting = waiting || !isPodReady(&pod)
+			continue
+		}
+		if !isPodReady(&pod) {
+			return RolloutDelete, pod.Name
+		}
+		outdated = append(outdated, pod)
+	}
+	if waiting || sts.Spec.Replicas != nil && int(*sts.Spec.Replicas) > len(pods) {
+		return RolloutWait, ""
+	}
+	if len(outdated) == 0 {
+		return RolloutDone, ""
+	}
+
+	// Replicas first, the highest ordinal first like a rolling update
+	slices.SortFunc(outdated, func(a, b corev1.Pod) int {
+		return cmp.Or(
+			cmp.Compare(a.Labels[MasterLabel], b.Labels[MasterLabel]),
+			-cmp.Compare(len(a.Name), len(b.Name)),
+			-cmp.Compare(a.Name, b.Name),
+		)
+	})
+	next := outdated[0]
+	if next.Labels[MasterLabel] == "true" && len(pods) > 1 {
+		return RolloutFailover, next.Name
+	}
+	return RolloutDelete, next.Name
+}
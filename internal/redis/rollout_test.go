@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func rolloutPod(name string, revision string, ready bool, master bool) corev1.Pod {
+	pod := corev1.Pod{}
+	pod.Name = name
+	pod.Labels = map[string]string{appsv1.ControllerRevisionHashLabelKey: revision}
+	if master {
+		pod.Labels[MasterLabel] = "true"
+	}
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}
+	return pod
+}
+
+func TestNextRolloutAction(t *testing.T) {
+	sts := &appsv1.StatefulSet{}
+	sts.Generation = 2
+	sts.Spec.Replicas = ptr.To[int32](3)
+	sts.Status.ObservedGeneration = 2
+	sts.Status.UpdateRevision = "new"
+
+	tests := []struct {
+		name   string
+		pods   []corev1.Pod
+		action RolloutAction
+		pod    string
+	}{{
+		name: "rolled out",
+		pods: []corev1.Pod{
+			rolloutPod("redis-redis-0", "new", true, true),
+			rolloutPod("redis-redis-1", "new", true, false),
+			rolloutPod("redis-redis-2", "new", true, false),
+		},
+		action: RolloutDone,
+	}, {
+		name: "replicas first, highest ordinal first",
+		pods: []corev1.Pod{
+			rolloutPod("redis-redis-0", "old", true, false),
+			rolloutPod("redis-redis-1", "old", true, true),
+			rolloutPod("redis-redis-2", "old", true, false),
+		},
+		action: RolloutDelete,
+		pod:    "redis-redis-2",
+	}, {
+		name: "failed pods first",
+		pods: []corev1.Pod{
+			rolloutPod("redis-redis-0", "old", false, false),
+			rolloutPod("redis-redis-1", "old", true, true),
+			rolloutPod("redis-redis-2", "old", true, false),
+		},
+		action: RolloutDelete,
+		pod:    "redis-redis-0",
+	}, {
+		name: "wait for the replaced pod",
+		pods: []corev1.Pod{
+			rolloutPod("redis-redis-0", "old", true, true),
+			rolloutPod("redis-redis-1", "old", true, false),
+			rolloutPod("redis-redis-2", "new", false, false),
+		},
+		action: RolloutWait,
+	}, {
+		name: "wait for the recreated pod",
+		pods: []corev1.Pod{
+			rolloutPod("redis-redis-0", "old", true, true),
+			rolloutPod("redis-redis-1", "old", true, false),
+		},
+		action: RolloutWait,
+	}, {
+		name: "master last after a failover",
+		pods: []corev1.Pod{
+			rolloutPod("redis-redis-0", "old", true, true),
+			rolloutPod("redis-redis-1", "new", true, false),
+			rolloutPod("redis-redis-2", "new", true, false),
+		},
+		action: RolloutFailover,
+		pod:    "redis-redis-0",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, pod := NextRolloutAction(sts, tt.pods)
+			if action != tt.action || pod != tt.pod {
+				t.Errorf("got %v %q, want %v %q", action, pod, tt.action, tt.pod)
+			}
+		})
+	}
+
+	// A single replica can't hand its role over
+	sts.Spec.Replicas = ptr.To[int32](1)
+	action, pod := NextRolloutAction(sts, []corev1.Pod{rolloutPod("redis-redis-0", "old", true, true)})
+	if action != RolloutDelete || pod != "redis-redis-0" {
+		t.Errorf("single replica: got %v %q", action, pod)
+	}
+
+	// Terminating pods finish first
+	terminating := rolloutPod("redis-redis-0", "old", true, false)
+	terminating.DeletionTimestamp = ptr.To(metav1.Now())
+	if action, _ := NextRolloutAction(sts, []corev1.Pod{terminating}); action != RolloutWait {
+		t.Errorf("terminating pod: got %v", action)
+	}
+
+	// The update revision is stale until the statefulset controller observed the spec
+	sts.Generation = 3
+	if action, _ := NextRolloutAction(sts, []corev1.Pod{rolloutPod("redis-redis-0", "new", true, true)}); action != RolloutWait {
+		t.Errorf("stale revision: got %v", action)
+	}
+}
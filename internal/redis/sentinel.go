@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SentinelSettingsVolumeName - name of the volume holding the sentinel settings
+	SentinelSettingsVolumeName = "sentinel-settings"
+	// SentinelSettingsMountPath - where start_sentinel.sh reads the settings from
+	SentinelSettingsMountPath = "/var/lib/config-data/sentinel"
+	// SentinelSettingsKey - key of the shell variables of the settings in the config map
+	SentinelSettingsKey = "sentinel.env"
+)
+
+// SentinelSettingsConfigMapName returns the name of the config map holding the sentinel
+// settings. It is not part of the config hash, changes get applied with SENTINEL SET.
+func SentinelSettingsConfigMapName(r *redisv1.Redis) string {
+	return r.Name + "-sentinel-settings"
+}
+
+// SentinelQuorum returns the quorum override or the majority of the replicas
+func SentinelQuorum(r *redisv1.Redis) int32 {
+	if r.Spec.Sentinel.Quorum != nil {
+		return *r.Spec.Sentinel.Quorum
+	}
+	return *r.Spec.Replicas/2 + 1
+}
+
+// sentinelSettings returns the per master settings as SENTINEL SET option/value pairs
+func sentinelSettings(r *redisv1.Redis) [][2]string {
+	s := r.Spec.Sentinel
+	return [][2]string{
+		{"down-after-milliseconds", strconv.Itoa(int(cmp.Or(s.DownAfterMilliseconds, redisv1.DefaultSentinelDownAfterMilliseconds)))},
+		{"failover-timeout", strconv.Itoa(int(cmp.Or(s.FailoverTimeout, redisv1.DefaultSentinelFailoverTimeout)))},
+		{"parallel-syncs", strconv.Itoa(int(cmp.Or(s.ParallelSyncs, redisv1.DefaultSentinelParallelSyncs)))},
+		{"quorum", strconv.Itoa(int(SentinelQuorum(r)))},
+	}
+}
+
+// SentinelSettingsEnv returns the shell variables start_sentinel.sh configures a new sentinel with
+func SentinelSettingsEnv(r *redisv1.Redis) string {
+	var b strings.Builder
+	for _, setting := range sentinelSettings(r) {
+		name := "SENTINEL_" + strings.ToUpper(strings.ReplaceAll(setting[0], "-", "_"))
+		fmt.Fprintf(&b, "%s=%s\n", name, setting[1])
+	}
+	return b.String()
+}
+
+// SentinelSetArgs returns the redis-cli arguments that apply the settings to a running sentinel
+func SentinelSetArgs(r *redisv1.Redis) []string {
+	args := []string{"-p", strconv.Itoa(redisv1.SentinelPort),
+		"SENTINEL", "SET", redisv1.SentinelMasterName}
+	for _, setting := range sentinelSettings(r) {
+		args = append(args, setting[0], setting[1])
+	}
+	return args
+}
+
+// SentinelPodMarker identifies the sentinel container and the settings it got with SENTINEL SET.
+// A sentinel that (re)starts reads the settings config map, which the kubelet updates with a
+// delay, so the settings are applied again whenever the marker changed.
+func SentinelPodMarker(pod *corev1.Pod, r *redisv1.Redis) string {
+	return containerMarker(pod, "sentinel", SentinelSettingsEnv(r))
+}
+
+// SentinelSettingsConfigMap returns the config map holding the sentinel settings
+func SentinelSettingsConfigMap(r *redisv1.Redis) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SentinelSettingsConfigMapName(r),
+			Namespace: r.Namespace,
+		},
+		Data: map[string]string{
+			SentinelSettingsKey: SentinelSettingsEnv(r),
+		},
+	}
+}
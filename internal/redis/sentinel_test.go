@@ -0,0 +1,71 @@
+package redis
+
+import (
+	"slices"
+	"testing"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestSentinelSettings(t *testing.T) {
+	r := &redisv1.Redis{}
+	r.Spec.Replicas = ptr.To[int32](3)
+
+	want := "SENTINEL_DOWN_AFTER_MILLISECONDS=10000\n" +
+		"SENTINEL_FAILOVER_TIMEOUT=20000\n" +
+		"SENTINEL_PARALLEL_SYNCS=1\n" +
+		"SENTINEL_QUORUM=2\n"
+	if got := SentinelSettingsEnv(r); got != want {
+		t.Errorf("SentinelSettingsEnv() = %q, want %q", got, want)
+	}
+
+	r.Spec.Sentinel = redisv1.SentinelSection{
+		DownAfterMilliseconds: 5000,
+		FailoverTimeout:       60000,
+		ParallelSyncs:         2,
+		Quorum:                ptr.To[int32](3),
+	}
+	wantArgs := []string{"-p", "26379", "SENTINEL", "SET", "redis",
+		"down-after-milliseconds", "5000",
+		"failover-timeout", "60000",
+		"parallel-syncs", "2",
+		"quorum", "3"}
+	if got := SentinelSetArgs(r); !slices.Equal(got, wantArgs) {
+		t.Errorf("SentinelSetArgs() = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestSentinelQuorum(t *testing.T) {
+	r := &redisv1.Redis{}
+	for replicas, want := range map[int32]int32{1: 1, 2: 2, 3: 2, 5: 3} {
+		r.Spec.Replicas = ptr.To(replicas)
+		if got := SentinelQuorum(r); got != want {
+			t.Errorf("SentinelQuorum() with %d replicas = %d, want %d", replicas, got, want)
+		}
+	}
+}
+
+func TestSentinelPodMarker(t *testing.T) {
+	r := &redisv1.Redis{}
+	r.Spec.Replicas = ptr.To[int32](3)
+	pod := &corev1.Pod{}
+	pod.UID = "uid-1"
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "redis", RestartCount: 1}, {Name: "sentinel"}}
+
+	marker := SentinelPodMarker(pod, r)
+	pod.Status.ContainerStatuses[0].RestartCount = 2
+	if SentinelPodMarker(pod, r) != marker {
+		t.Errorf("SentinelPodMarker() changed after a restart of the redis container")
+	}
+	pod.Status.ContainerStatuses[1].RestartCount = 1
+	if SentinelPodMarker(pod, r) == marker {
+		t.Errorf("SentinelPodMarker() unchanged after a restart of the sentinel, it may have read stale settings")
+	}
+	restarted := SentinelPodMarker(pod, r)
+	r.Spec.Sentinel.Quorum = ptr.To[int32](3)
+	if SentinelPodMarker(pod, r) == restarted {
+		t.Errorf("SentinelPodMarker() unchanged after a change of the settings")
+	}
+}
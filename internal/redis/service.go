@@ -22,7 +22,7 @@ func Service(instance *redisv1.Redis) *corev1.Service {
 		Selector: map[string]string{
 			common.AppSelector:   "redis",
 			common.OwnerSelector: instance.Name,
-			"redis/master":       "true",
+			MasterLabel:          "true",
 		},
 		Port: service.GenericServicePort{
 			Name:     "redis",
@@ -31,6 +31,11 @@ func Service(instance *redisv1.Redis) *corev1.Service {
 		},
 	}
 
+	// The nodes of the cluster mode serve their slots and redirect to the others
+	if instance.Spec.IsClusterMode() {
+		delete(details.Selector, MasterLabel)
+	}
+
 	svc := service.GenericService(details)
 	return svc
 }
@@ -41,6 +46,17 @@ func HeadlessService(instance *redisv1.Redis) *corev1.Service {
 		common.AppSelector:   "redis",
 		common.OwnerSelector: instance.Name,
 	})
+	ports := []corev1.ServicePort{
+		{Name: "redis", Protocol: "TCP", Port: 6379},
+	}
+	if instance.Spec.IsClusterMode() {
+		ports = append(ports, corev1.ServicePort{Name: "cluster-bus", Protocol: "TCP", Port: redisv1.ClusterBusPort})
+	} else {
+		ports = append(ports, corev1.ServicePort{Name: "sentinel", Protocol: "TCP", Port: 26379})
+	}
+	if instance.Spec.Metrics.Enabled {
+		ports = append(ports, metricsServicePorts(instance)...)
+	}
 	details := &service.GenericServiceDetails{
 		Name:      instance.GetName() + "-" + "redis",
 		Namespace: instance.GetNamespace(),
@@ -49,10 +65,7 @@ func HeadlessService(instance *redisv1.Redis) *corev1.Service {
 			common.AppSelector:   "redis",
 			common.OwnerSelector: instance.Name,
 		},
-		Ports: []corev1.ServicePort{
-			{Name: "redis", Protocol: "TCP", Port: 6379},
-			{Name: "sentinel", Protocol: "TCP", Port: 26379},
-		},
+		Ports:                    ports,
 		ClusterIP:                "None",
 		PublishNotReadyAddresses: true,
 	}
@@ -1,8 +1,6 @@
 package redis
 
 import (
-	"strconv"
-
 	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
 	common "github.com/openstack-k8s-operators/lib-common/modules/common"
@@ -13,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 // StatefulSet returns a StatefulSet resource for the Redis CR
@@ -79,6 +78,7 @@ func StatefulSet(
 		Name:  "CONFIG_HASH",
 		Value: configHash,
 	}}
+	commonEnvVars = append(commonEnvVars, authEnvVars(r)...)
 
 	sts := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -87,7 +87,11 @@ func StatefulSet(
 		},
 		Spec: appsv1.StatefulSetSpec{
 			ServiceName: name,
-			Replicas:    r.Spec.Replicas,
+			Replicas:    ptr.To(Replicas(r)),
+			// The controller replaces the pods, the master last after handing its role over
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.OnDeleteStatefulSetStrategyType,
+			},
 			Selector: &metav1.LabelSelector{
 				MatchLabels: ls,
 			},
@@ -123,14 +127,18 @@ func StatefulSet(
 									},
 								},
 							},
+							Lifecycle: &corev1.Lifecycle{
+								PreStop: &corev1.LifecycleHandler{
+									Exec: &corev1.ExecAction{
+										Command: []string{"/var/lib/operator-scripts/redis_prestop.sh", "redis"},
+									},
+								},
+							},
 						}, {
-							Image:   r.Spec.ContainerImage,
-							Command: []string{"/usr/bin/dumb-init", "--", "/var/lib/operator-scripts/start_sentinel.sh"},
-							Name:    "sentinel",
-							Env: append(commonEnvVars, corev1.EnvVar{
-								Name:  "SENTINEL_QUORUM",
-								Value: strconv.Itoa((int(*r.Spec.Replicas) / 2) + 1),
-							}),
+							Image:        r.Spec.ContainerImage,
+							Command:      []string{"/usr/bin/dumb-init", "--", "/var/lib/operator-scripts/start_sentinel.sh"},
+							Name:         "sentinel",
+							Env:          commonEnvVars,
 							Resources:    r.Spec.SentinelResources,
 							VolumeMounts: getSentinelVolumeMounts(r),
 							Ports: []corev1.ContainerPort{{
@@ -139,6 +147,13 @@ func StatefulSet(
 							}},
 							ReadinessProbe: sentinelReadinessProbe,
 							LivenessProbe:  sentinelLivenessProbe,
+							Lifecycle: &corev1.Lifecycle{
+								PreStop: &corev1.LifecycleHandler{
+									Exec: &corev1.ExecAction{
+										Command: []string{"/var/lib/operator-scripts/redis_prestop.sh", "sentinel"},
+									},
+								},
+							},
 						},
 					},
 					Volumes: getVolumes(r),
@@ -147,6 +162,20 @@ func StatefulSet(
 		},
 	}
 
+	if r.Spec.IsClusterMode() {
+		applyClusterMode(sts)
+	}
+
+	if r.Spec.Metrics.Enabled {
+		sts.Spec.Template.Spec.Containers = append(sts.Spec.Template.Spec.Containers, exporterContainers(r)...)
+	}
+
+	if r.Spec.Persistence.Enabled() {
+		sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			dataVolumeClaimTemplate(r),
+		}
+	}
+
 	if r.Spec.NodeSelector != nil {
 		sts.Spec.Template.Spec.NodeSelector = *r.Spec.NodeSelector
 	}
@@ -167,3 +196,22 @@ func StatefulSet(
 	}
 	return sts
 }
+
+// applyClusterMode turns the pods into nodes of a Redis Cluster, which fail over by themselves
+// without sentinels. The cluster job joins the nodes and assigns the slots.
+func applyClusterMode(sts *appsv1.StatefulSet) {
+	containers := []corev1.Container{}
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		if c.Name != "redis" {
+			continue
+		}
+		c.Command = []string{"/usr/bin/dumb-init", "--", "/var/lib/operator-scripts/start_redis_cluster.sh"}
+		c.Ports = append(c.Ports, corev1.ContainerPort{
+			ContainerPort: redisv1.ClusterBusPort,
+			Name:          "cluster-bus",
+		})
+		c.Lifecycle.PreStop.Exec.Command = []string{"/var/lib/operator-scripts/redis_prestop.sh", "cluster"}
+		containers = append(containers, c)
+	}
+	sts.Spec.Template.Spec.Containers = containers
+}
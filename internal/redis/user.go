@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"strings"
+
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// UserSecretName returns the name of the secret holding the credentials of a RedisUser
+func UserSecretName(u *redisv1.RedisUser) string {
+	return redisv1.RedisUserSecretPrefix + u.Name
+}
+
+// PodNames returns the names of the redis pods of the StatefulSet
+func PodNames(r *redisv1.Redis) []string {
+	pods := []string{}
+	for i := int32(0); i < Replicas(r); i++ {
+		pods = append(pods, fmt.Sprintf("%s-redis-%d", r.Name, i))
+	}
+	return pods
+}
+
+// CLICommand returns the command that runs redis-cli with the given arguments in a redis
+// container. common.sh sets up TLS and the password of the default user.
+func CLICommand(args ...string) []string {
+	return append([]string{
+		"/bin/bash", "-c",
+		`. /var/lib/operator-scripts/common.sh && $REDIS_CLI_CMD "$@"`,
+		"redis-cli",
+	}, args...)
+}
+
+// ACLSetUserArgs returns the ACL SETUSER arguments that (re)define the user from scratch, so
+// that applying them again or after a spec change always results in the same permissions.
+// Only the SHA-256 of the password is passed to redis.
+func ACLSetUserArgs(u *redisv1.RedisUser, password string) []string {
+	args := []string{"ACL", "SETUSER", u.GetUsername(), "reset", "on",
+		fmt.Sprintf("#%x", sha256.Sum256([]byte(password)))}
+	for _, key := range u.Spec.Keys {
+		args = append(args, "~"+key)
+	}
+	for _, channel := range u.Spec.Channels {
+		args = append(args, "&"+channel)
+	}
+	return append(args, u.Spec.Commands...)
+}
+
+// UserPodMarker identifies the redis pod and ACL rules a user got set with. ACL users are not
+// persisted, so a new pod or a restart of its redis container drops them, and a change of the
+// arguments needs another SETUSER. Otherwise the user is still set and SETUSER can be skipped.
+func UserPodMarker(pod *corev1.Pod, args []string) string {
+	return containerMarker(pod, "redis", strings.Join(args, "\x00"))
+}
+
+// containerMarker returns the pod UID, the restart count of the container and the hash of
+// the data, it changes whenever the container started again or the data changed
+func containerMarker(pod *corev1.Pod, container string, data string) string {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			restarts = status.RestartCount
+		}
+	}
+	return fmt.Sprintf("%s/%d/%x", pod.UID, restarts, sha256.Sum256([]byte(data)))
+}
+
+// ACLDelUserArgs returns the ACL DELUSER arguments that remove the user
+func ACLDelUserArgs(username string) []string {
+	return []string{"ACL", "DELUSER", username}
+}
+
+// ConnectionURL returns the URL clients use to connect to the redis master as the given user
+func ConnectionURL(r *redisv1.Redis, username, password string) string {
+	return masterURL(r, url.UserPassword(username, password))
+}
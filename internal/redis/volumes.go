@@ -100,6 +100,10 @@ func getVolumes(r *redisv1.Redis) []corev1.Volume {
 							Key:  "start_redis_replication.sh",
 							Path: "start_redis_replication.sh",
 						},
+						{
+							Key:  "start_redis_cluster.sh",
+							Path: "start_redis_cluster.sh",
+						},
 						{
 							Key:  "start_sentinel.sh",
 							Path: "start_sentinel.sh",
@@ -108,6 +112,10 @@ func getVolumes(r *redisv1.Redis) []corev1.Volume {
 							Key:  "redis_probe.sh",
 							Path: "redis_probe.sh",
 						},
+						{
+							Key:  "redis_prestop.sh",
+							Path: "redis_prestop.sh",
+						},
 						{
 							Key:  "check_redis_endpoints.sh",
 							Path: "check_redis_endpoints.sh",
@@ -123,6 +131,24 @@ func getVolumes(r *redisv1.Redis) []corev1.Volume {
 		},
 	}
 
+	vols = append(vols, corev1.Volume{
+		Name: SentinelSettingsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: SentinelSettingsConfigMapName(r),
+				},
+			},
+		},
+	})
+
+	vols = append(vols, getTLSVolumes(r)...)
+
+	return vols
+}
+
+func getTLSVolumes(r *redisv1.Redis) []corev1.Volume {
+	var vols []corev1.Volume
 	if r.Spec.TLS.Enabled() {
 		svc := tls.Service{
 			SecretName: *r.Spec.TLS.SecretName,
@@ -137,7 +163,6 @@ func getVolumes(r *redisv1.Redis) []corev1.Volume {
 			vols = append(vols, caVolume)
 		}
 	}
-
 	return vols
 }
 
@@ -177,6 +202,12 @@ func getRedisVolumeMounts(r *redisv1.Redis) []corev1.VolumeMount {
 		ReadOnly:  true,
 		Name:      "kolla-config",
 	}}
+	if r.Spec.Persistence.Enabled() {
+		vm = append(vm, corev1.VolumeMount{
+			MountPath: DataMountPath,
+			Name:      DataVolumeName,
+		})
+	}
 	vm = append(vm, getTLSVolumeMounts(r)...)
 	return vm
 }
@@ -197,6 +228,10 @@ func getSentinelVolumeMounts(r *redisv1.Redis) []corev1.VolumeMount {
 		MountPath: "/var/lib/kolla/config_files",
 		ReadOnly:  true,
 		Name:      "kolla-config-sentinel",
+	}, {
+		MountPath: SentinelSettingsMountPath,
+		ReadOnly:  true,
+		Name:      SentinelSettingsVolumeName,
 	}}
 	vm = append(vm, getTLSVolumeMounts(r)...)
 	return vm
@@ -225,4 +225,98 @@ var _ = Describe("RabbitMq webhook", func() {
 			Expect(*freshRabbitMq.Spec.QueueType).To(Equal("Quorum"), "QueueType should be preserved from existing CR")
 		})
 	})
+
+	Context("ValidateUpdate method", func() {
+		var oldRabbitMq *rabbitmqv1beta1.RabbitMq
+
+		BeforeEach(func() {
+			oldRabbitMq = &rabbitmqv1beta1.RabbitMq{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-rabbitmq-upgrade",
+					Namespace: "default",
+				},
+				Spec: rabbitmqv1beta1.RabbitMqSpec{
+					ContainerImage: "rabbitmq:3.12",
+				},
+				Status: rabbitmqv1beta1.RabbitMqStatus{
+					Version: "3.12.14",
+				},
+			}
+		})
+
+		It("should allow an upgrade to the next minor version", func() {
+			rabbitmq := oldRabbitMq.DeepCopy()
+			rabbitmq.Spec.ContainerImage = "rabbitmq:3.13"
+			rabbitmq.Spec.TargetVersion = "3.13"
+
+			_, err := rabbitmq.ValidateUpdate(oldRabbitMq)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should refuse an in-place upgrade across a major version from an older minor", func() {
+			rabbitmq := oldRabbitMq.DeepCopy()
+			rabbitmq.Spec.ContainerImage = "rabbitmq:4.0"
+			rabbitmq.Spec.TargetVersion = "4.0"
+
+			_, err := rabbitmq.ValidateUpdate(oldRabbitMq)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.targetVersion"))
+		})
+
+		It("should allow the same upgrade with the BlueGreen strategy", func() {
+			rabbitmq := oldRabbitMq.DeepCopy()
+			rabbitmq.Spec.ContainerImage = "rabbitmq:4.0"
+			rabbitmq.Spec.TargetVersion = "4.0"
+			rabbitmq.Spec.UpgradeStrategy = rabbitmqv1beta1.UpgradeStrategyBlueGreen
+
+			_, err := rabbitmq.ValidateUpdate(oldRabbitMq)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should refuse the BlueGreen strategy without a target version", func() {
+			rabbitmq := oldRabbitMq.DeepCopy()
+			rabbitmq.Spec.ContainerImage = "rabbitmq:4.0"
+			rabbitmq.Spec.UpgradeStrategy = rabbitmqv1beta1.UpgradeStrategyBlueGreen
+
+			_, err := rabbitmq.ValidateUpdate(oldRabbitMq)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("targetVersion is required with the BlueGreen upgrade strategy"))
+		})
+
+		It("should refuse a BlueGreen upgrade to a patch release", func() {
+			rabbitmq := oldRabbitMq.DeepCopy()
+			rabbitmq.Spec.ContainerImage = "rabbitmq:3.12-hotfix"
+			rabbitmq.Spec.TargetVersion = "3.12.15"
+			rabbitmq.Spec.UpgradeStrategy = rabbitmqv1beta1.UpgradeStrategyBlueGreen
+
+			_, err := rabbitmq.ValidateUpdate(oldRabbitMq)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.upgradeStrategy"))
+		})
+
+		It("should refuse a BlueGreen upgrade before the running version is known", func() {
+			oldRabbitMq.Status.Version = ""
+			rabbitmq := oldRabbitMq.DeepCopy()
+			rabbitmq.Spec.ContainerImage = "rabbitmq:4.0"
+			rabbitmq.Spec.TargetVersion = "4.0"
+			rabbitmq.Spec.UpgradeStrategy = rabbitmqv1beta1.UpgradeStrategyBlueGreen
+
+			_, err := rabbitmq.ValidateUpdate(oldRabbitMq)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.upgradeStrategy"))
+		})
+
+		It("should refuse an image change while an upgrade is in progress", func() {
+			oldRabbitMq.Status.Upgrade = &rabbitmqv1beta1.RabbitMqUpgradeStatus{
+				Phase:   rabbitmqv1beta1.UpgradePhaseRollingImage,
+				ToImage: "rabbitmq:3.13",
+			}
+			rabbitmq := oldRabbitMq.DeepCopy()
+			rabbitmq.Spec.ContainerImage = "rabbitmq:3.13-hotfix"
+
+			_, err := rabbitmq.ValidateUpdate(oldRabbitMq)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.containerImage"))
+		})
+	})
 })
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	rabbitmqv1beta1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
+)
+
+var transporturllog = logf.Log.WithName("transporturl-resource")
+
+// SetupTransportURLWebhookWithManager registers the webhook for TransportURL in the manager.
+func SetupTransportURLWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&rabbitmqv1beta1.TransportURL{}).
+		WithValidator(&TransportURLCustomValidator{
+			Client: mgr.GetClient(),
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-rabbitmq-openstack-org-v1beta1-transporturl,mutating=false,failurePolicy=fail,sideEffects=None,groups=rabbitmq.openstack.org,resources=transporturls,verbs=create;update,versions=v1beta1,name=vtransporturl-v1beta1.kb.io,admissionReviewVersions=v1
+
+// TransportURLCustomValidator struct is responsible for validating the TransportURL resource
+// when it is created, updated, or deleted.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type TransportURLCustomValidator struct {
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &TransportURLCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type TransportURL.
+func (v *TransportURLCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	transporturl, ok := obj.(*rabbitmqv1beta1.TransportURL)
+	if !ok {
+		return nil, fmt.Errorf("expected a TransportURL object but got %T", obj)
+	}
+	transporturllog.Info("Validation for TransportURL upon creation", "name", transporturl.GetName())
+
+	return transporturl.ValidateCreate(v.Client)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type TransportURL.
+func (v *TransportURLCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	transporturl, ok := newObj.(*rabbitmqv1beta1.TransportURL)
+	if !ok {
+		return nil, fmt.Errorf("expected a TransportURL object for the newObj but got %T", newObj)
+	}
+	transporturllog.Info("Validation for TransportURL upon update", "name", transporturl.GetName())
+
+	return transporturl.ValidateUpdate(v.Client, oldObj)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type TransportURL.
+func (v *TransportURLCustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	transporturl, ok := obj.(*rabbitmqv1beta1.TransportURL)
+	if !ok {
+		return nil, fmt.Errorf("expected a TransportURL object but got %T", obj)
+	}
+	transporturllog.Info("Validation for TransportURL upon deletion", "name", transporturl.GetName())
+
+	return transporturl.ValidateDelete(v.Client)
+}
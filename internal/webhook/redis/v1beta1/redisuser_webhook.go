@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	redisv1beta1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
+)
+
+var redisuserlog = logf.Log.WithName("redisuser-resource")
+
+// SetupRedisUserWebhookWithManager registers the webhook for RedisUser in the manager.
+func SetupRedisUserWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&redisv1beta1.RedisUser{}).
+		WithValidator(&RedisUserCustomValidator{
+			Client: mgr.GetClient(),
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-redis-openstack-org-v1beta1-redisuser,mutating=false,failurePolicy=fail,sideEffects=None,groups=redis.openstack.org,resources=redisusers,verbs=create;update,versions=v1beta1,name=vredisuser-v1beta1.kb.io,admissionReviewVersions=v1
+
+// RedisUserCustomValidator struct is responsible for validating the RedisUser resource
+// when it is created, updated, or deleted.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type RedisUserCustomValidator struct {
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &RedisUserCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type RedisUser.
+func (v *RedisUserCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	redisuser, ok := obj.(*redisv1beta1.RedisUser)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisUser object but got %T", obj)
+	}
+	redisuserlog.Info("Validation for RedisUser upon creation", "name", redisuser.GetName())
+
+	return redisuser.ValidateCreate(v.Client)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type RedisUser.
+func (v *RedisUserCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	redisuser, ok := newObj.(*redisv1beta1.RedisUser)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisUser object for the newObj but got %T", newObj)
+	}
+	redisuserlog.Info("Validation for RedisUser upon update", "name", redisuser.GetName())
+
+	return redisuser.ValidateUpdate(v.Client, oldObj)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type RedisUser.
+func (v *RedisUserCustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	redisuser, ok := obj.(*redisv1beta1.RedisUser)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisUser object but got %T", obj)
+	}
+	redisuserlog.Info("Validation for RedisUser upon deletion", "name", redisuser.GetName())
+
+	return redisuser.ValidateDelete(v.Client)
+}
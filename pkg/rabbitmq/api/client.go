@@ -68,8 +68,57 @@ type Policy struct {
 	ApplyTo    string                 `json:"apply-to"`
 }
 
+// Overview represents the RabbitMQ cluster overview
+type Overview struct {
+	RabbitMQVersion   string `json:"rabbitmq_version"`
+	ManagementVersion string `json:"management_version"`
+}
+
+// FeatureFlag represents a RabbitMQ feature flag
+type FeatureFlag struct {
+	Name string `json:"name"`
+	// State - enabled, disabled, state_changing or unavailable
+	State string `json:"state"`
+	// Stability - required, stable or experimental
+	Stability string `json:"stability"`
+	Desc      string `json:"desc,omitempty"`
+}
+
+// Queue represents a RabbitMQ queue
+type Queue struct {
+	Name     string `json:"name"`
+	Vhost    string `json:"vhost"`
+	Messages int    `json:"messages"`
+}
+
+// Shovel represents the definition of a RabbitMQ dynamic shovel
+type Shovel struct {
+	SrcURI    string `json:"src-uri"`
+	SrcQueue  string `json:"src-queue"`
+	DestURI   string `json:"dest-uri"`
+	DestQueue string `json:"dest-queue"`
+	// SrcDeleteAfter - "never", or "queue-length" to stop once the messages present at start have moved
+	SrcDeleteAfter string `json:"src-delete-after,omitempty"`
+	AckMode        string `json:"ack-mode,omitempty"`
+}
+
+// Feature flag states and stabilities
+const (
+	FeatureFlagStateEnabled  = "enabled"
+	FeatureFlagStateDisabled = "disabled"
+	FeatureFlagStable        = "stable"
+	FeatureFlagRequired      = "required"
+)
+
 // NewClient creates a new RabbitMQ Management API client
 func NewClient(baseURL, username, password string, tlsEnabled bool, caCert []byte) *Client {
+	return NewClientWithTransport(baseURL, username, password, tlsEnabled, caCert, nil)
+}
+
+// NewClientWithTransport creates a new RabbitMQ Management API client sending its
+// requests through transport, e.g. the in-memory broker of the fake package in tests.
+// A nil transport uses the default transport of NewClient.
+func NewClientWithTransport(baseURL, username, password string, tlsEnabled bool, caCert []byte, transport http.RoundTripper) *Client {
 	httpClient := &http.Client{
 		Timeout: 10 * time.Second,
 	}
@@ -92,6 +141,10 @@ func NewClient(baseURL, username, password string, tlsEnabled bool, caCert []byt
 		}
 	}
 
+	if transport != nil {
+		httpClient.Transport = transport
+	}
+
 	return &Client{
 		baseURL:    baseURL,
 		username:   username,
@@ -150,8 +203,7 @@ func (c *Client) CreateOrUpdateUser(name, password string, tags []string) error
 	}()
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create/update user %s: status %d, body: %s", name, resp.StatusCode, string(body))
+		return newAPIError(resp, "create/update", "user", name, password)
 	}
 
 	return nil
@@ -169,8 +221,7 @@ func (c *Client) DeleteUser(name string) error {
 	}()
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete user %s: status %d, body: %s", name, resp.StatusCode, string(body))
+		return newAPIError(resp, "delete", "user", name)
 	}
 
 	return nil
@@ -192,8 +243,7 @@ func (c *Client) CreateOrUpdateVhost(name string) error {
 	}()
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create/update vhost %s: status %d, body: %s", name, resp.StatusCode, string(body))
+		return newAPIError(resp, "create/update", "vhost", name)
 	}
 
 	return nil
@@ -211,8 +261,7 @@ func (c *Client) DeleteVhost(name string) error {
 	}()
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete vhost %s: status %d, body: %s", name, resp.StatusCode, string(body))
+		return newAPIError(resp, "delete", "vhost", name)
 	}
 
 	return nil
@@ -240,8 +289,7 @@ func (c *Client) SetPermissions(vhost, user, configure, write, read string) erro
 	}()
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to set permissions for user %s on vhost %s: status %d, body: %s", user, vhost, resp.StatusCode, string(body))
+		return newAPIError(resp, "set", "permissions for user", fmt.Sprintf("%s on vhost %s", user, vhost))
 	}
 
 	return nil
@@ -260,8 +308,7 @@ func (c *Client) DeletePermissions(vhost, user string) error {
 	}()
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete permissions for user %s on vhost %s: status %d, body: %s", user, vhost, resp.StatusCode, string(body))
+		return newAPIError(resp, "delete", "permissions for user", fmt.Sprintf("%s on vhost %s", user, vhost))
 	}
 
 	return nil
@@ -291,8 +338,7 @@ func (c *Client) CreateOrUpdatePolicy(vhost, name, pattern string, definition ma
 	}()
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create/update policy %s on vhost %s: status %d, body: %s", name, vhost, resp.StatusCode, string(body))
+		return newAPIError(resp, "create/update", "policy", fmt.Sprintf("%s on vhost %s", name, vhost))
 	}
 
 	return nil
@@ -311,8 +357,138 @@ func (c *Client) DeletePolicy(vhost, name string) error {
 	}()
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete policy %s on vhost %s: status %d, body: %s", name, vhost, resp.StatusCode, string(body))
+		return newAPIError(resp, "delete", "policy", fmt.Sprintf("%s on vhost %s", name, vhost))
+	}
+
+	return nil
+}
+
+// getJSON performs a GET request and decodes the response into out
+func (c *Client) getJSON(path, kind string, out interface{}) error {
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp, "get", kind, "")
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", kind, err)
+	}
+	return nil
+}
+
+// GetOverview returns the RabbitMQ cluster overview
+func (c *Client) GetOverview() (*Overview, error) {
+	overview := &Overview{}
+	if err := c.getJSON("/api/overview", "overview", overview); err != nil {
+		return nil, err
+	}
+	return overview, nil
+}
+
+// ListFeatureFlags returns the RabbitMQ feature flags
+func (c *Client) ListFeatureFlags() ([]FeatureFlag, error) {
+	flags := []FeatureFlag{}
+	if err := c.getJSON("/api/feature-flags", "feature flags", &flags); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// EnableFeatureFlag enables a RabbitMQ feature flag
+func (c *Client) EnableFeatureFlag(name string) error {
+	encodedName := url.PathEscape(name)
+	resp, err := c.doRequest("PUT", fmt.Sprintf("/api/feature-flags/%s/enable", encodedName), map[string]string{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return newAPIError(resp, "enable", "feature flag", name)
+	}
+
+	return nil
+}
+
+// GetDefinitions exports the definitions of the RabbitMQ cluster
+func (c *Client) GetDefinitions() (json.RawMessage, error) {
+	defs := json.RawMessage{}
+	if err := c.getJSON("/api/definitions", "definitions", &defs); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+// ImportDefinitions imports definitions exported by GetDefinitions
+func (c *Client) ImportDefinitions(defs json.RawMessage) error {
+	resp, err := c.doRequest("POST", "/api/definitions", defs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return newAPIError(resp, "import", "definitions", "")
+	}
+
+	return nil
+}
+
+// ListQueues returns the RabbitMQ queues of all vhosts
+func (c *Client) ListQueues() ([]Queue, error) {
+	queues := []Queue{}
+	if err := c.getJSON("/api/queues", "queues", &queues); err != nil {
+		return nil, err
+	}
+	return queues, nil
+}
+
+// CreateOrUpdateShovel creates or updates a RabbitMQ dynamic shovel
+func (c *Client) CreateOrUpdateShovel(vhost, name string, shovel Shovel) error {
+	encodedVhost := url.PathEscape(vhost)
+	encodedName := url.PathEscape(name)
+	body := map[string]interface{}{"value": shovel}
+	resp, err := c.doRequest("PUT", fmt.Sprintf("/api/parameters/shovel/%s/%s", encodedVhost, encodedName), body)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		// The URIs hold the credentials of the clusters
+		return newAPIError(resp, "create/update", "shovel", fmt.Sprintf("%s on vhost %s", name, vhost), shovel.SrcURI, shovel.DestURI)
+	}
+
+	return nil
+}
+
+// DeleteShovel deletes a RabbitMQ dynamic shovel
+func (c *Client) DeleteShovel(vhost, name string) error {
+	encodedVhost := url.PathEscape(vhost)
+	encodedName := url.PathEscape(name)
+	resp, err := c.doRequest("DELETE", fmt.Sprintf("/api/parameters/shovel/%s/%s", encodedVhost, encodedName), nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return newAPIError(resp, "delete", "shovel", fmt.Sprintf("%s on vhost %s", name, vhost))
 	}
 
 	return nil
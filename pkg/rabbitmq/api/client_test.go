@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -213,3 +214,101 @@ func TestDeletePolicy(t *testing.T) {
 		t.Errorf("DeletePolicy failed: %v", err)
 	}
 }
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"bad_request","reason":"vhost_or_user_not_found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "admin", "admin", false, nil)
+	err := client.SetPermissions("nova", "testuser", ".*", ".*", ".*")
+
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Type != "bad_request" || apiErr.Reason != "vhost_or_user_not_found" {
+		t.Errorf("Unexpected APIError: %+v", apiErr)
+	}
+	want := "failed to set permissions for user testuser on vhost nova: status 400, error: bad_request, reason: vhost_or_user_not_found"
+	if err.Error() != want {
+		t.Errorf("Expected error %q, got %q", want, err.Error())
+	}
+}
+
+func TestAPIErrorNotJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("upstream unavailable\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "admin", "admin", false, nil)
+	err := client.DeleteVhost("testvhost")
+
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.Type != "" || apiErr.Reason != "upstream unavailable" {
+		t.Errorf("Unexpected APIError: %+v", apiErr)
+	}
+	if IsNotFound(err) || IsUnauthorized(err) {
+		t.Errorf("Unexpected classification of %v", err)
+	}
+}
+
+func TestAPIErrorRedactsPassword(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"bad_request","reason":"invalid password s3cr3t"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "admin", "admin", false, nil)
+	err := client.CreateOrUpdateUser("testuser", "s3cr3t", nil)
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("Expected password to be redacted, got %q", err.Error())
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		secrets []string
+		want    string
+	}{
+		{
+			name:  "password field",
+			input: `{"name":"nova","password":"s3cr\"et","tags":[]}`,
+			want:  `{"name":"nova","password":"[REDACTED]","tags":[]}`,
+		},
+		{
+			name:  "password hash field",
+			input: `{"password_hash": "abc"}`,
+			want:  `{"password_hash": "[REDACTED]"}`,
+		},
+		{
+			name:    "explicit secret",
+			input:   "failed with s3cr3t",
+			secrets: []string{"s3cr3t", ""},
+			want:    "failed with [REDACTED]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.input, tt.secrets...); got != tt.want {
+				t.Errorf("Redact() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
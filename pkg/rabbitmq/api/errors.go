@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//nolint:revive
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces secrets in error messages
+const redacted = "[REDACTED]"
+
+// passwordFieldRegex matches password fields of JSON documents echoed back by the API
+var passwordFieldRegex = regexp.MustCompile(`("password(?:_hash)?"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// APIError is an error returned by the RabbitMQ Management API. The API
+// answers with a JSON document holding the error type and a reason, e.g.
+// {"error":"bad_request","reason":"vhost_or_user_not_found"}.
+type APIError struct {
+	// Operation - the operation which failed, e.g. "create/update"
+	Operation string
+	// Kind - the kind of the affected resource, e.g. "user"
+	Kind string
+	// Name - the name of the affected resource, e.g. "nova" or "nova on vhost /"
+	Name string
+	// StatusCode - the HTTP status code of the response
+	StatusCode int
+	// Type - the error type of the response, e.g. "bad_request"
+	Type string `json:"error"`
+	// Reason - the reason of the error as reported by RabbitMQ
+	Reason string `json:"reason"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("failed to %s %s %s: status %d", e.Operation, e.Kind, e.Name, e.StatusCode)
+	if e.Name == "" {
+		msg = fmt.Sprintf("failed to %s %s: status %d", e.Operation, e.Kind, e.StatusCode)
+	}
+	if e.Type != "" {
+		msg = fmt.Sprintf("%s, error: %s", msg, e.Type)
+	}
+	if e.Reason != "" {
+		msg = fmt.Sprintf("%s, reason: %s", msg, e.Reason)
+	}
+	return msg
+}
+
+// newAPIError builds an APIError from an unexpected response. The secrets, e.g.
+// the password sent in the request, are redacted from the parsed body.
+func newAPIError(resp *http.Response, operation, kind, name string, secrets ...string) *APIError {
+	apiErr := &APIError{
+		Operation:  operation,
+		Kind:       kind,
+		Name:       name,
+		StatusCode: resp.StatusCode,
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, apiErr); err != nil || (apiErr.Type == "" && apiErr.Reason == "") {
+		// Not a management API error document, e.g. returned by a proxy
+		apiErr.Reason = strings.TrimSpace(string(body))
+	}
+	apiErr.Type = Redact(apiErr.Type, secrets...)
+	apiErr.Reason = Redact(apiErr.Reason, secrets...)
+
+	return apiErr
+}
+
+// Redact removes the secrets and any JSON password field from s
+func Redact(s string, secrets ...string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return passwordFieldRegex.ReplaceAllString(s, `$1"`+redacted+`"`)
+}
+
+// AsAPIError returns the APIError wrapped in err, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsNotFound returns true if err is an APIError with status 404
+func IsNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized returns true if err is an APIError with status 401
+func IsUnauthorized(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusUnauthorized
+}
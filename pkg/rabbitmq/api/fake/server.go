@@ -0,0 +1,1030 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides an in-memory implementation of the RabbitMQ Management
+// HTTP API for tests. It keeps users, vhosts, permissions, policies, limits,
+// feature flags, queues, shovels and definitions in memory, answers with the status codes of a real broker and can
+// inject failures and latency.
+package fake
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRabbitMQVersion is the version reported by /api/overview and /api/definitions
+const DefaultRabbitMQVersion = "3.13.7"
+
+// defaultFeatureFlags are the feature flags of a fresh broker, one stable flag is
+// left disabled like after an upgrade from a previous version
+var defaultFeatureFlags = []FeatureFlag{
+	{Name: "quorum_queue", State: "enabled", Stability: "required"},
+	{Name: "stream_queue", State: "enabled", Stability: "stable"},
+	{Name: "message_containers", State: "disabled", Stability: "stable"},
+	{Name: "khepri_db", State: "disabled", Stability: "experimental"},
+}
+
+// User is a user stored in the fake broker
+type User struct {
+	Name     string   `json:"name"`
+	Password string   `json:"-"`
+	Tags     []string `json:"tags"`
+}
+
+// Permission is a permission of a user on a vhost stored in the fake broker
+type Permission struct {
+	User      string `json:"user"`
+	Vhost     string `json:"vhost"`
+	Configure string `json:"configure"`
+	Write     string `json:"write"`
+	Read      string `json:"read"`
+}
+
+// Policy is a policy stored in the fake broker
+type Policy struct {
+	Vhost      string                 `json:"vhost"`
+	Name       string                 `json:"name"`
+	Pattern    string                 `json:"pattern"`
+	Definition map[string]interface{} `json:"definition"`
+	Priority   int                    `json:"priority"`
+	ApplyTo    string                 `json:"apply-to"`
+}
+
+// FeatureFlag is a feature flag of the fake broker
+type FeatureFlag struct {
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Stability string `json:"stability"`
+}
+
+// Queue is a queue of the fake broker
+type Queue struct {
+	Name     string `json:"name"`
+	Vhost    string `json:"vhost"`
+	Messages int    `json:"messages"`
+}
+
+// Shovel is a dynamic shovel stored in the fake broker
+type Shovel struct {
+	Vhost string                 `json:"vhost"`
+	Name  string                 `json:"name"`
+	Value map[string]interface{} `json:"value"`
+}
+
+// Request is a request received by the fake broker
+type Request struct {
+	Method string
+	// Path is the escaped request path, e.g. /api/permissions/%2F/user
+	Path string
+	Body []byte
+}
+
+// Failure describes an injected failure. Requests matching Method and Path are
+// answered with StatusCode and Body instead of being processed.
+type Failure struct {
+	// Method - HTTP method to match, empty matches any method
+	Method string
+	// Path - prefix of the escaped request path to match, empty matches any path
+	Path string
+	// StatusCode - status code to answer with
+	StatusCode int
+	// Body - response body, defaults to a management API error document
+	Body string
+	// Times - number of requests to fail, 0 fails until ClearFailures is called
+	Times int
+}
+
+type permissionKey struct {
+	vhost string
+	user  string
+}
+
+type policyKey struct {
+	vhost string
+	name  string
+}
+
+// Server is an in-memory RabbitMQ Management API. It implements http.Handler,
+// so it can be served with httptest.NewServer or used through a Transport.
+type Server struct {
+	mu sync.Mutex
+
+	username string
+	password string
+	version  string
+
+	users       map[string]*User
+	vhosts      map[string]bool
+	permissions map[permissionKey]*Permission
+	policies    map[policyKey]*Policy
+	vhostLimits map[string]map[string]int64
+	userLimits  map[string]map[string]int64
+
+	featureFlags map[string]*FeatureFlag
+	queues       map[policyKey]*Queue
+	shovels      map[policyKey]*Shovel
+
+	failures []*Failure
+	latency  time.Duration
+	requests []Request
+}
+
+// NewServer returns a fake broker which accepts the given admin credentials.
+// Like a fresh broker it holds the admin user and the default vhost "/".
+func NewServer(username, password string) *Server {
+	s := &Server{
+		username: username,
+		password: password,
+	}
+	s.Reset()
+	return s
+}
+
+// Reset drops all state, injected failures and recorded requests
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.version = DefaultRabbitMQVersion
+	s.users = map[string]*User{
+		s.username: {Name: s.username, Password: s.password, Tags: []string{"administrator"}},
+	}
+	s.vhosts = map[string]bool{"/": true}
+	s.permissions = map[permissionKey]*Permission{
+		{vhost: "/", user: s.username}: {User: s.username, Vhost: "/", Configure: ".*", Write: ".*", Read: ".*"},
+	}
+	s.policies = map[policyKey]*Policy{}
+	s.vhostLimits = map[string]map[string]int64{}
+	s.userLimits = map[string]map[string]int64{}
+	s.featureFlags = map[string]*FeatureFlag{}
+	for _, f := range defaultFeatureFlags {
+		flag := f
+		s.featureFlags[f.Name] = &flag
+	}
+	s.queues = map[policyKey]*Queue{}
+	s.shovels = map[policyKey]*Shovel{}
+	s.failures = nil
+	s.latency = 0
+	s.requests = nil
+}
+
+// SetVersion sets the RabbitMQ version reported by the fake broker
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// SetLatency delays every response by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// InjectFailure makes matching requests fail. Failures are checked in the
+// order they were injected.
+func (s *Server) InjectFailure(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// ClearFailures removes all injected failures
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = nil
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// GetUser returns the user with the given name
+func (s *Server) GetUser(name string) (User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[name]
+	if !ok {
+		return User{}, false
+	}
+	return *u, true
+}
+
+// HasVhost returns true if the vhost exists
+func (s *Server) HasVhost(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.vhosts[name]
+}
+
+// GetPermissions returns the permissions of user on vhost
+func (s *Server) GetPermissions(vhost, user string) (Permission, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.permissions[permissionKey{vhost: vhost, user: user}]
+	if !ok {
+		return Permission{}, false
+	}
+	return *p, true
+}
+
+// GetPolicy returns the policy with the given name on vhost
+func (s *Server) GetPolicy(vhost, name string) (Policy, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.policies[policyKey{vhost: vhost, name: name}]
+	if !ok {
+		return Policy{}, false
+	}
+	return *p, true
+}
+
+// GetVhostLimits returns the limits set on vhost
+func (s *Server) GetVhostLimits(vhost string) map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyLimits(s.vhostLimits[vhost])
+}
+
+// GetUserLimits returns the limits set on user
+func (s *Server) GetUserLimits(user string) map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyLimits(s.userLimits[user])
+}
+
+// SetFeatureFlag adds or replaces a feature flag
+func (s *Server) SetFeatureFlag(f FeatureFlag) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flag := f
+	s.featureFlags[f.Name] = &flag
+}
+
+// GetFeatureFlag returns a feature flag
+func (s *Server) GetFeatureFlag(name string) (FeatureFlag, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.featureFlags[name]
+	if !ok {
+		return FeatureFlag{}, false
+	}
+	return *f, true
+}
+
+// SetQueue adds or replaces a queue holding the given number of messages
+func (s *Server) SetQueue(vhost, name string, messages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues[policyKey{vhost: vhost, name: name}] = &Queue{Name: name, Vhost: vhost, Messages: messages}
+}
+
+// GetShovel returns a dynamic shovel
+func (s *Server) GetShovel(vhost, name string) (Shovel, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sh, ok := s.shovels[policyKey{vhost: vhost, name: name}]
+	if !ok {
+		return Shovel{}, false
+	}
+	return *sh, true
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.EscapedPath(), Body: body})
+	latency := s.latency
+	failure := s.matchFailure(r.Method, r.URL.EscapedPath())
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if failure != nil {
+		if failure.Body != "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(failure.StatusCode)
+			_, _ = w.Write([]byte(failure.Body))
+			return
+		}
+		writeError(w, failure.StatusCode, strings.ToLower(strings.ReplaceAll(http.StatusText(failure.StatusCode), " ", "_")), "injected failure")
+		return
+	}
+
+	user, pass, ok := r.BasicAuth()
+	if !ok || user != s.username || pass != s.password {
+		writeError(w, http.StatusUnauthorized, "not_authorized", "Login failed")
+		return
+	}
+
+	segments, err := splitPath(r.URL.EscapedPath())
+	if err != nil || len(segments) == 0 || segments[0] != "api" {
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	args := segments[1:]
+	if len(args) == 0 {
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+		return
+	}
+	switch args[0] {
+	case "overview":
+		s.handleOverview(w, r, args[1:])
+	case "users":
+		s.handleUsers(w, r, args[1:], body)
+	case "vhosts":
+		s.handleVhosts(w, r, args[1:])
+	case "permissions":
+		s.handlePermissions(w, r, args[1:], body)
+	case "policies":
+		s.handlePolicies(w, r, args[1:], body)
+	case "vhost-limits":
+		s.handleLimits(w, r, args[1:], body, "vhost", s.vhostLimits, func(name string) bool { return s.vhosts[name] })
+	case "user-limits":
+		s.handleLimits(w, r, args[1:], body, "user", s.userLimits, func(name string) bool { return s.users[name] != nil })
+	case "definitions":
+		s.handleDefinitions(w, r, args[1:], body)
+	case "feature-flags":
+		s.handleFeatureFlags(w, r, args[1:])
+	case "queues":
+		s.handleQueues(w, r, args[1:])
+	case "parameters":
+		s.handleParameters(w, r, args[1:], body)
+	default:
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+	}
+}
+
+// matchFailure returns the first injected failure matching the request, the caller must hold s.mu
+func (s *Server) matchFailure(method, path string) *Failure {
+	for i, f := range s.failures {
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if f.Path != "" && !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) handleOverview(w http.ResponseWriter, r *http.Request, args []string) {
+	if len(args) != 0 {
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"rabbitmq_version":   s.version,
+		"management_version": s.version,
+	})
+}
+
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request, args []string, body []byte) {
+	switch len(args) {
+	case 0:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		users := []User{}
+		for _, name := range sortedKeys(s.users) {
+			users = append(users, *s.users[name])
+		}
+		writeJSON(w, http.StatusOK, users)
+	case 1:
+		name := args[0]
+		switch r.Method {
+		case http.MethodGet:
+			u, ok := s.users[name]
+			if !ok {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			writeJSON(w, http.StatusOK, u)
+		case http.MethodPut:
+			req := struct {
+				Password     *string  `json:"password"`
+				PasswordHash *string  `json:"password_hash"`
+				Tags         []string `json:"tags"`
+			}{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+				return
+			}
+			u, exists := s.users[name]
+			if !exists && req.Password == nil && req.PasswordHash == nil {
+				writeError(w, http.StatusBadRequest, "bad_request", "Mandatory fields missing")
+				return
+			}
+			if !exists {
+				u = &User{Name: name}
+				s.users[name] = u
+			}
+			if req.Password != nil {
+				u.Password = *req.Password
+			}
+			u.Tags = append([]string{}, req.Tags...)
+			writeCreatedOrUpdated(w, exists)
+		case http.MethodDelete:
+			if _, ok := s.users[name]; !ok {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			delete(s.users, name)
+			delete(s.userLimits, name)
+			for k := range s.permissions {
+				if k.user == name {
+					delete(s.permissions, k)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeMethodNotAllowed(w)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+	}
+}
+
+func (s *Server) handleVhosts(w http.ResponseWriter, r *http.Request, args []string) {
+	switch len(args) {
+	case 0:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		vhosts := []map[string]string{}
+		for _, name := range sortedKeys(s.vhosts) {
+			vhosts = append(vhosts, map[string]string{"name": name})
+		}
+		writeJSON(w, http.StatusOK, vhosts)
+	case 1:
+		name := args[0]
+		switch r.Method {
+		case http.MethodGet:
+			if !s.vhosts[name] {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]string{"name": name})
+		case http.MethodPut:
+			exists := s.vhosts[name]
+			s.vhosts[name] = true
+			writeCreatedOrUpdated(w, exists)
+		case http.MethodDelete:
+			if !s.vhosts[name] {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			s.deleteVhost(name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeMethodNotAllowed(w)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+	}
+}
+
+// deleteVhost removes a vhost together with its permissions, policies and limits
+func (s *Server) deleteVhost(name string) {
+	delete(s.vhosts, name)
+	delete(s.vhostLimits, name)
+	for k := range s.permissions {
+		if k.vhost == name {
+			delete(s.permissions, k)
+		}
+	}
+	for k := range s.policies {
+		if k.vhost == name {
+			delete(s.policies, k)
+		}
+	}
+}
+
+func (s *Server) handlePermissions(w http.ResponseWriter, r *http.Request, args []string, body []byte) {
+	switch len(args) {
+	case 0, 1:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		if len(args) == 1 && !s.vhosts[args[0]] {
+			writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+			return
+		}
+		perms := []Permission{}
+		for _, p := range s.sortedPermissions() {
+			if len(args) == 1 && p.Vhost != args[0] {
+				continue
+			}
+			perms = append(perms, p)
+		}
+		writeJSON(w, http.StatusOK, perms)
+	case 2:
+		key := permissionKey{vhost: args[0], user: args[1]}
+		switch r.Method {
+		case http.MethodGet:
+			p, ok := s.permissions[key]
+			if !ok {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			writeJSON(w, http.StatusOK, p)
+		case http.MethodPut:
+			if !s.vhosts[key.vhost] || s.users[key.user] == nil {
+				writeError(w, http.StatusBadRequest, "bad_request", "vhost_or_user_not_found")
+				return
+			}
+			req := struct {
+				Configure *string `json:"configure"`
+				Write     *string `json:"write"`
+				Read      *string `json:"read"`
+			}{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+				return
+			}
+			if req.Configure == nil || req.Write == nil || req.Read == nil {
+				writeError(w, http.StatusBadRequest, "bad_request", "Mandatory fields missing")
+				return
+			}
+			_, exists := s.permissions[key]
+			s.permissions[key] = &Permission{
+				User:      key.user,
+				Vhost:     key.vhost,
+				Configure: *req.Configure,
+				Write:     *req.Write,
+				Read:      *req.Read,
+			}
+			writeCreatedOrUpdated(w, exists)
+		case http.MethodDelete:
+			if _, ok := s.permissions[key]; !ok {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			delete(s.permissions, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeMethodNotAllowed(w)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+	}
+}
+
+func (s *Server) handlePolicies(w http.ResponseWriter, r *http.Request, args []string, body []byte) {
+	switch len(args) {
+	case 0, 1:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		if len(args) == 1 && !s.vhosts[args[0]] {
+			writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+			return
+		}
+		policies := []Policy{}
+		for _, p := range s.sortedPolicies() {
+			if len(args) == 1 && p.Vhost != args[0] {
+				continue
+			}
+			policies = append(policies, p)
+		}
+		writeJSON(w, http.StatusOK, policies)
+	case 2:
+		key := policyKey{vhost: args[0], name: args[1]}
+		switch r.Method {
+		case http.MethodGet:
+			p, ok := s.policies[key]
+			if !ok {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			writeJSON(w, http.StatusOK, p)
+		case http.MethodPut:
+			if !s.vhosts[key.vhost] {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			p := &Policy{}
+			if err := json.Unmarshal(body, p); err != nil {
+				writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+				return
+			}
+			if p.Pattern == "" || p.Definition == nil {
+				writeError(w, http.StatusBadRequest, "bad_request", "Mandatory fields missing")
+				return
+			}
+			if p.ApplyTo == "" {
+				p.ApplyTo = "all"
+			}
+			p.Vhost = key.vhost
+			p.Name = key.name
+			_, exists := s.policies[key]
+			s.policies[key] = p
+			writeCreatedOrUpdated(w, exists)
+		case http.MethodDelete:
+			if _, ok := s.policies[key]; !ok {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			delete(s.policies, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeMethodNotAllowed(w)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+	}
+}
+
+// handleLimits serves /api/vhost-limits and /api/user-limits. kind is "vhost" or
+// "user", exists reports whether the vhost or user a limit gets set on exists.
+func (s *Server) handleLimits(w http.ResponseWriter, r *http.Request, args []string, body []byte, kind string, limits map[string]map[string]int64, exists func(string) bool) {
+	switch len(args) {
+	case 0, 1:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		if len(args) == 1 && !exists(args[0]) {
+			writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+			return
+		}
+		result := []map[string]interface{}{}
+		for _, name := range sortedKeys(limits) {
+			if len(args) == 1 && name != args[0] {
+				continue
+			}
+			result = append(result, map[string]interface{}{kind: name, "value": copyLimits(limits[name])})
+		}
+		writeJSON(w, http.StatusOK, result)
+	case 2:
+		name, limit := args[0], args[1]
+		if !exists(name) {
+			writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			req := struct {
+				Value *int64 `json:"value"`
+			}{}
+			if err := json.Unmarshal(body, &req); err != nil || req.Value == nil {
+				writeError(w, http.StatusBadRequest, "bad_request", "Mandatory fields missing")
+				return
+			}
+			if limits[name] == nil {
+				limits[name] = map[string]int64{}
+			}
+			_, existed := limits[name][limit]
+			limits[name][limit] = *req.Value
+			writeCreatedOrUpdated(w, existed)
+		case http.MethodDelete:
+			if _, ok := limits[name][limit]; !ok {
+				writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+				return
+			}
+			delete(limits[name], limit)
+			if len(limits[name]) == 0 {
+				delete(limits, name)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeMethodNotAllowed(w)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+	}
+}
+
+func (s *Server) handleFeatureFlags(w http.ResponseWriter, r *http.Request, args []string) {
+	switch len(args) {
+	case 0:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		flags := []FeatureFlag{}
+		for _, name := range sortedKeys(s.featureFlags) {
+			flags = append(flags, *s.featureFlags[name])
+		}
+		writeJSON(w, http.StatusOK, flags)
+	case 2:
+		if args[1] != "enable" {
+			writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+			return
+		}
+		if r.Method != http.MethodPut {
+			writeMethodNotAllowed(w)
+			return
+		}
+		f, ok := s.featureFlags[args[0]]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "bad_request", "unsupported feature flag")
+			return
+		}
+		f.State = "enabled"
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+	}
+}
+
+func (s *Server) handleQueues(w http.ResponseWriter, r *http.Request, args []string) {
+	if len(args) > 1 {
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+	if len(args) == 1 && !s.vhosts[args[0]] {
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+		return
+	}
+	queues := []Queue{}
+	for _, q := range s.queues {
+		if len(args) == 1 && q.Vhost != args[0] {
+			continue
+		}
+		queues = append(queues, *q)
+	}
+	sort.Slice(queues, func(i, j int) bool {
+		if queues[i].Vhost != queues[j].Vhost {
+			return queues[i].Vhost < queues[j].Vhost
+		}
+		return queues[i].Name < queues[j].Name
+	})
+	writeJSON(w, http.StatusOK, queues)
+}
+
+// handleParameters serves /api/parameters/shovel, the only component the fake broker knows
+func (s *Server) handleParameters(w http.ResponseWriter, r *http.Request, args []string, body []byte) {
+	if len(args) != 3 || args[0] != "shovel" {
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+		return
+	}
+	key := policyKey{vhost: args[1], name: args[2]}
+	switch r.Method {
+	case http.MethodGet:
+		sh, ok := s.shovels[key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, sh)
+	case http.MethodPut:
+		if !s.vhosts[key.vhost] {
+			writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+			return
+		}
+		sh := &Shovel{}
+		if err := json.Unmarshal(body, sh); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+			return
+		}
+		if sh.Value == nil || sh.Value["src-uri"] == nil || sh.Value["dest-uri"] == nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "Mandatory fields missing")
+			return
+		}
+		sh.Vhost = key.vhost
+		sh.Name = key.name
+		_, exists := s.shovels[key]
+		s.shovels[key] = sh
+		writeCreatedOrUpdated(w, exists)
+	case http.MethodDelete:
+		if _, ok := s.shovels[key]; !ok {
+			writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+			return
+		}
+		delete(s.shovels, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+// definitionUser is a user as exported by /api/definitions
+type definitionUser struct {
+	Name         string   `json:"name"`
+	Password     string   `json:"password,omitempty"`
+	PasswordHash string   `json:"password_hash,omitempty"`
+	Tags         []string `json:"tags"`
+}
+
+// definitions is the document served and accepted by /api/definitions
+type definitions struct {
+	RabbitVersion string           `json:"rabbit_version,omitempty"`
+	Users         []definitionUser `json:"users"`
+	Vhosts        []struct {
+		Name string `json:"name"`
+	} `json:"vhosts"`
+	Permissions []Permission `json:"permissions"`
+	Policies    []Policy     `json:"policies"`
+}
+
+func (s *Server) handleDefinitions(w http.ResponseWriter, r *http.Request, args []string, body []byte) {
+	if len(args) != 0 {
+		writeError(w, http.StatusNotFound, "Object Not Found", "Not Found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		defs := definitions{RabbitVersion: s.version, Users: []definitionUser{}, Permissions: s.sortedPermissions(), Policies: s.sortedPolicies()}
+		for _, name := range sortedKeys(s.users) {
+			u := s.users[name]
+			defs.Users = append(defs.Users, definitionUser{Name: u.Name, PasswordHash: hashPassword(u.Password), Tags: u.Tags})
+		}
+		for _, name := range sortedKeys(s.vhosts) {
+			defs.Vhosts = append(defs.Vhosts, struct {
+				Name string `json:"name"`
+			}{Name: name})
+		}
+		writeJSON(w, http.StatusOK, defs)
+	case http.MethodPost:
+		defs := definitions{}
+		if err := json.Unmarshal(body, &defs); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+			return
+		}
+		for _, v := range defs.Vhosts {
+			s.vhosts[v.Name] = true
+		}
+		for _, u := range defs.Users {
+			user := &User{Name: u.Name, Password: u.Password, Tags: u.Tags}
+			if existing, ok := s.users[u.Name]; ok && u.Password == "" {
+				user.Password = existing.Password
+			}
+			s.users[u.Name] = user
+		}
+		for _, p := range defs.Permissions {
+			if !s.vhosts[p.Vhost] || s.users[p.User] == nil {
+				writeError(w, http.StatusBadRequest, "bad_request", "vhost_or_user_not_found")
+				return
+			}
+			perm := p
+			s.permissions[permissionKey{vhost: p.Vhost, user: p.User}] = &perm
+		}
+		for _, p := range defs.Policies {
+			if !s.vhosts[p.Vhost] {
+				writeError(w, http.StatusBadRequest, "bad_request", "vhost_not_found")
+				return
+			}
+			policy := p
+			if policy.ApplyTo == "" {
+				policy.ApplyTo = "all"
+			}
+			s.policies[policyKey{vhost: p.Vhost, name: p.Name}] = &policy
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) sortedPermissions() []Permission {
+	perms := []Permission{}
+	for _, p := range s.permissions {
+		perms = append(perms, *p)
+	}
+	sort.Slice(perms, func(i, j int) bool {
+		if perms[i].Vhost != perms[j].Vhost {
+			return perms[i].Vhost < perms[j].Vhost
+		}
+		return perms[i].User < perms[j].User
+	})
+	return perms
+}
+
+func (s *Server) sortedPolicies() []Policy {
+	policies := []Policy{}
+	for _, p := range s.policies {
+		policies = append(policies, *p)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].Vhost != policies[j].Vhost {
+			return policies[i].Vhost < policies[j].Vhost
+		}
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+// splitPath splits an escaped path into unescaped segments, so that an
+// escaped vhost "%2F" stays a single segment
+func splitPath(escapedPath string) ([]string, error) {
+	segments := []string{}
+	for _, seg := range strings.Split(strings.Trim(escapedPath, "/"), "/") {
+		if seg == "" {
+			continue
+		}
+		unescaped, err := url.PathUnescape(seg)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, unescaped)
+	}
+	return segments, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func copyLimits(limits map[string]int64) map[string]int64 {
+	result := map[string]int64{}
+	for k, v := range limits {
+		result[k] = v
+	}
+	return result
+}
+
+func hashPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with an error document as returned by the management API
+func writeError(w http.ResponseWriter, status int, errorType, reason string) {
+	writeJSON(w, status, map[string]string{"error": errorType, "reason": reason})
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", "Method Not Allowed")
+}
+
+// writeCreatedOrUpdated answers a PUT with 201 for new and 204 for updated objects
+func writeCreatedOrUpdated(w http.ResponseWriter, existed bool) {
+	if existed {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Transport is an http.RoundTripper which serves requests from in-memory
+// brokers without opening network connections. Each host gets its own Server,
+// so clusters in different namespaces do not share state.
+type Transport struct {
+	mu       sync.Mutex
+	username string
+	password string
+	servers  map[string]*Server
+}
+
+// NewTransport returns a Transport whose brokers accept the given admin credentials
+func NewTransport(username, password string) *Transport {
+	return &Transport{
+		username: username,
+		password: password,
+		servers:  map[string]*Server{},
+	}
+}
+
+// Server returns the broker serving host, host must not include the port
+func (t *Transport) Server(host string) *Server {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.servers[host]
+	if !ok {
+		s = NewServer(t.username, t.password)
+		t.servers[host] = s
+	}
+	return s
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		req.Body = http.NoBody
+	}
+	rec := httptest.NewRecorder()
+	t.Server(req.URL.Hostname()).ServeHTTP(rec, req)
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
@@ -0,0 +1,49 @@
+#!/bin/bash
+
+# Takes a backup of the Redis cluster and reports it in the termination message
+
+. /var/lib/operator-scripts/common.sh
+
+set -o pipefail
+
+BACKUP_DIR=/var/lib/redis-backup
+
+# Prefer a replica, the snapshot forks the redis server it is taken from
+replicas=$(timeout ${TIMEOUT} $REDIS_CLI_CMD -h ${SVC_FQDN} -p 26379 --raw sentinel replicas redis)
+source=$(echo "$replicas" | awk '/^ip$/ {getline; ip=$0} /^flags$/ {getline; if ($0 == "slave") {print ip; exit}}')
+if [ -z "${source}" ]; then
+    source=$(timeout ${TIMEOUT} $REDIS_CLI_CMD -h ${SVC_FQDN} -p 26379 --raw sentinel get-master-addr-by-name redis | head -1)
+fi
+if [ -z "${source}" ]; then
+    log_error "The sentinels do not know a redis server to take the backup from"
+    exit 1
+fi
+
+timestamp=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+location="${BACKUP_NAME}/$(date -u -d "${timestamp}" +%Y%m%d%H%M%S).rdb"
+mkdir -p "${BACKUP_DIR}/${BACKUP_NAME}"
+
+# redis-cli --rdb makes the server fork a snapshot the same way as BGSAVE and streams it
+log "Taking a snapshot of ${source}"
+if ! $REDIS_CLI_CMD -h "${source}" -p 6379 --rdb "${BACKUP_DIR}/${location}.tmp"; then
+    log_error "Could not take a snapshot of ${source}"
+    rm -f "${BACKUP_DIR}/${location}.tmp"
+    exit 1
+fi
+mv "${BACKUP_DIR}/${location}.tmp" "${BACKUP_DIR}/${location}"
+
+size=$(stat -c %s "${BACKUP_DIR}/${location}")
+checksum=$(sha256sum "${BACKUP_DIR}/${location}" | awk '{print $1}')
+
+if [ -n "${S3_ENDPOINT}" ]; then
+    log "Uploading ${location} to ${S3_ENDPOINT}/${S3_BUCKET}"
+    if ! s3_curl -T "${BACKUP_DIR}/${location}" "$(s3_url "${location}")"; then
+        log_error "Could not upload ${location}"
+        exit 1
+    fi
+    rm -f "${BACKUP_DIR}/${location}"
+fi
+
+log "Backup ${location} taken (${size} bytes, sha256 ${checksum})"
+printf '{"location":"%s","size":%s,"checksum":"%s","time":"%s"}' \
+    "${location}" "${size}" "${checksum}" "${timestamp}" > /dev/termination-log
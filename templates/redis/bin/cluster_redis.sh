@@ -0,0 +1,257 @@
+#!/bin/bash
+
+. /var/lib/operator-scripts/common.sh
+
+# Creates the Redis Cluster on the nodes of CLUSTER_NODES, or reshapes a running cluster to
+# CLUSTER_SHARDS masters with CLUSTER_REPLICAS_PER_SHARD replicas each: slots no node serves get
+# assigned, new nodes join, the slots of masters that leave or get demoted are moved to the
+# others and the nodes of CLUSTER_REMOVED_NODES leave the cluster. Every step starts from the current state of the
+# cluster, so that a failed job can simply be retried.
+
+set -o pipefail
+
+NODES=(${CLUSTER_NODES})
+REMOVED=(${CLUSTER_REMOVED_NODES})
+SHARDS=${CLUSTER_SHARDS}
+REPLICAS_PER_SHARD=${CLUSTER_REPLICAS_PER_SHARD}
+ENTRY=${NODES[0]}
+declare -A ID
+
+function cli() {
+    local host="$1"
+    shift
+    timeout ${TIMEOUT} $REDIS_CLI_CMD -h "${host}" "$@" | tr -d '\r'
+}
+
+# The cluster commands of redis-cli take the addresses of the nodes. The nodes keep them in
+# nodes.conf, start_redis_cluster.sh refreshes them from the announced FQDNs when pods move.
+function addr() {
+    local ip
+    ip=$(getent hosts "$1" | awk '{print $1; exit}')
+    if [ -z "${ip}" ]; then
+        log_error "Could not resolve ${1}"
+        return 1
+    fi
+    echo "${ip}:6379"
+}
+
+function cluster_cli() {
+    local command="$1"
+    shift
+    $REDIS_CLI_CMD --cluster "${command}" "$@"
+}
+
+function known_nodes() {
+    cli "$1" cluster info | awk -F: '/^cluster_known_nodes:/ {print $2}'
+}
+
+# Prints "<id> <role> <master-id> <slot-count>" for every node the entry node knows
+function nodes_table() {
+    cli "${ENTRY}" cluster nodes | awk '{
+        role = ($3 ~ /master/) ? "master" : "replica"
+        slots = 0
+        for (i = 9; i <= NF; i++) {
+            if ($i ~ /^\[/) continue
+            n = split($i, r, "-")
+            slots += (n == 2) ? r[2] - r[1] + 1 : 1
+        }
+        print $1, role, $4, slots
+    }'
+}
+
+# Prints the given field of the node with the given ID from the nodes table
+function node_field() {
+    nodes_table | awk -v id="$1" -v f="$2" '$1 == id {print $f}'
+}
+
+function is_removed() {
+    local id="$1"
+    for node in "${REMOVED[@]}"; do
+        [ "${ID[$node]}" = "${id}" ] && return 0
+    done
+    return 1
+}
+
+function wait_for_role() {
+    local node="$1"
+    local role="$2"
+    for i in $(seq 30); do
+        [ "$(cli "${node}" role | head -1)" = "${role}" ] && return 0
+        sleep 1
+    done
+    log_error "${node} did not become ${role}"
+    return 1
+}
+
+function wait_for_consistency() {
+    local entry
+    entry=$(addr "${ENTRY}") || return 1
+    for i in $(seq 30); do
+        cluster_cli check "${entry}" >/dev/null 2>&1 && return 0
+        sleep 2
+    done
+    log_error "The nodes do not agree on the configuration of the cluster"
+    cluster_cli check "${entry}"
+    return 1
+}
+
+# Prints the kept masters serving slots, the kept replicas of each as "<count> <id>"
+function replica_counts() {
+    local table="$1"
+    for node in "${NODES[@]}"; do
+        local id=${ID[$node]}
+        echo "${table}" | awk -v id="${id}" '$1 == id && $2 == "master" && $4 > 0 {print $1}'
+    done | while read -r master; do
+        local count=0
+        for node in "${NODES[@]}"; do
+            echo "${table}" | grep -q "^${ID[$node]} replica ${master} " && count=$((count + 1))
+        done
+        echo "${count} ${master}"
+    done | sort -n
+}
+
+# 1. Everything the nodes report about each other is keyed by the node IDs
+for node in "${NODES[@]}" "${REMOVED[@]}"; do
+    ID[$node]=$(cli "${node}" cluster myid)
+    if [ -z "${ID[$node]}" ]; then
+        log_error "Node ${node} is not reachable"
+        exit 1
+    fi
+done
+
+# 2. A new cluster: no node knows another one yet
+new=1
+for node in "${NODES[@]}"; do
+    if [ "$(known_nodes "${node}")" != "1" ]; then
+        new=
+        ENTRY=${node}
+        break
+    fi
+done
+if [ -n "${new}" ]; then
+    addrs=()
+    for node in "${NODES[@]}"; do
+        address=$(addr "${node}") || exit 1
+        addrs+=("${address}")
+    done
+    log "Creating a Redis cluster with ${SHARDS} shards and ${REPLICAS_PER_SHARD} replicas per shard"
+    cluster_cli create "${addrs[@]}" --cluster-replicas ${REPLICAS_PER_SHARD} --cluster-yes || exit 1
+    wait_for_consistency || exit 1
+    log "Created the Redis cluster"
+    exit 0
+fi
+entry=$(addr "${ENTRY}") || exit 1
+
+# 3. Slots no node serves, after an interrupted resharding or the loss of a master without
+# replica, get assigned again. The other steps need a cluster that serves all slots.
+if ! cli "${ENTRY}" cluster info | grep -q "^cluster_slots_assigned:16384$"; then
+    log "Assigning the slots the cluster does not serve"
+    cluster_cli fix "${entry}" --cluster-yes || exit 1
+    wait_for_consistency || exit 1
+fi
+
+# 4. New nodes join as empty masters
+for node in "${NODES[@]}"; do
+    if [ "$(known_nodes "${node}")" = "1" ]; then
+        log "Adding ${node} to the cluster"
+        address=$(addr "${node}") || exit 1
+        cluster_cli add-node "${address}" "${entry}" || exit 1
+    fi
+done
+wait_for_consistency || exit 1
+
+# 5. Kept replicas take over the masters that leave
+for node in "${NODES[@]}"; do
+    id=${ID[$node]}
+    if [ "$(node_field "${id}" 2)" = "replica" ] && is_removed "$(node_field "${id}" 3)"; then
+        log "Promoting ${node}, its master leaves the cluster"
+        cli "${node}" cluster failover
+        wait_for_role "${node}" master || exit 1
+    fi
+done
+
+# 6. The kept nodes need SHARDS masters: demote the smallest masters or promote replicas of
+# the masters with the most replicas, which restart as empty masters
+table=$(nodes_table)
+kept_masters=()
+for node in "${NODES[@]}"; do
+    master=$(echo "${table}" | awk -v id="${ID[$node]}" '$1 == id && $2 == "master" {print $4, $1}')
+    [ -n "${master}" ] && kept_masters+=("${master}")
+done
+demoted=()
+if [ ${#kept_masters[@]} -gt ${SHARDS} ]; then
+    for id in $(printf '%s\n' "${kept_masters[@]}" | sort -n | head -$((${#kept_masters[@]} - SHARDS)) | awk '{print $2}'); do
+        log "Demoting master ${id} to a replica"
+        demoted+=("${id}")
+    done
+fi
+for ((promote = SHARDS - ${#kept_masters[@]}; promote > 0; promote--)); do
+    master=$(replica_counts "$(nodes_table)" | tail -1 | awk '{print $2}')
+    for node in "${NODES[@]}"; do
+        if [ "$(node_field "${ID[$node]}" 3)" = "${master}" ]; then
+            log "Promoting replica ${node} to a new master"
+            cli "${node}" cluster reset soft
+            address=$(addr "${node}") || exit 1
+            cluster_cli add-node "${address}" "${entry}" || exit 1
+            wait_for_consistency || exit 1
+            break
+        fi
+    done
+done
+
+# 7. Distribute the slots evenly over the masters that stay, the others get emptied
+weights=()
+table=$(nodes_table)
+for id in "${demoted[@]}"; do
+    weights+=("${id}=0")
+done
+for node in "${REMOVED[@]}"; do
+    if echo "${table}" | grep -q "^${ID[$node]} master "; then
+        weights+=("${ID[$node]}=0")
+    fi
+done
+log "Distributing the slots over ${SHARDS} shards"
+if [ ${#weights[@]} -gt 0 ]; then
+    cluster_cli rebalance "${entry}" --cluster-use-empty-masters --cluster-yes --cluster-weight "${weights[@]}" || exit 1
+else
+    cluster_cli rebalance "${entry}" --cluster-use-empty-masters --cluster-yes || exit 1
+fi
+wait_for_consistency || exit 1
+
+# 8. Every master gets REPLICAS_PER_SHARD replicas, from the demoted masters first and then
+# from the masters with more replicas
+for id in "${demoted[@]}"; do
+    master=$(replica_counts "$(nodes_table)" | head -1 | awk '{print $2}')
+    for node in "${NODES[@]}"; do
+        if [ "${ID[$node]}" = "${id}" ]; then
+            log "Making ${node} a replica of ${master}"
+            cli "${node}" cluster replicate "${master}"
+            wait_for_role "${node}" slave || exit 1
+        fi
+    done
+done
+for i in $(seq ${#NODES[@]}); do
+    counts=$(replica_counts "$(nodes_table)")
+    read -r low_count low <<<"$(echo "${counts}" | head -1)"
+    read -r high_count high <<<"$(echo "${counts}" | tail -1)"
+    if [ "${low_count}" -ge "${REPLICAS_PER_SHARD}" ] || [ "${high_count}" -le "${REPLICAS_PER_SHARD}" ]; then
+        break
+    fi
+    for node in "${NODES[@]}"; do
+        if [ "$(node_field "${ID[$node]}" 3)" = "${high}" ]; then
+            log "Moving replica ${node} from ${high} to ${low}"
+            cli "${node}" cluster replicate "${low}"
+            sleep 1
+            break
+        fi
+    done
+done
+
+# 9. The removed nodes leave the cluster
+for node in "${REMOVED[@]}"; do
+    log "Removing ${node} from the cluster"
+    cluster_cli del-node "${entry}" "${ID[$node]}" || exit 1
+done
+wait_for_consistency || exit 1
+
+log "The Redis cluster has ${SHARDS} shards with ${REPLICAS_PER_SHARD} replicas per shard"
@@ -23,6 +23,11 @@ else
     SENTINEL_CONFIG=/var/lib/redis/sentinel.conf
 fi
 
+# redis-cli authenticates as the default user when a password is required
+if [ -n "${REDIS_PASSWORD}" ]; then
+    export REDISCLI_AUTH="${REDIS_PASSWORD}"
+fi
+
 function log() {
     echo "$(date +%F_%H_%M_%S) $*"
 }
@@ -40,6 +45,54 @@ function generate_configs() {
         log "Generating config file from template $PWD/${cfg}"
         sed -e "s/{ POD_FQDN }/${POD_FQDN}/g" -e "s/{ POD_IP }/${POD_IP}/g" "${cfg}" > "/var/lib/config-data/generated/${cfg%.in}"
     done
+    # The password is not part of the config map, it comes from the auth secret
+    if [ -n "${REDIS_PASSWORD}" ]; then
+        log "Requiring password authentication"
+        printf 'requirepass %s\nmasterauth %s\n' "${REDIS_PASSWORD}" "${REDIS_PASSWORD}" >> /var/lib/config-data/generated/var/lib/redis/redis.conf
+        printf 'requirepass %s\n' "${REDIS_PASSWORD}" >> /var/lib/config-data/generated/var/lib/redis/sentinel.conf
+    fi
+}
+
+# Prints the sentinel directives to monitor the given master, the settings come from
+# the sentinel settings config map sourced by start_sentinel.sh
+function sentinel_monitor_config() {
+    local master="$1"
+    echo "sentinel monitor redis ${master} 6379 ${SENTINEL_QUORUM}"
+    echo "sentinel down-after-milliseconds redis ${SENTINEL_DOWN_AFTER_MILLISECONDS}"
+    echo "sentinel failover-timeout redis ${SENTINEL_FAILOVER_TIMEOUT}"
+    echo "sentinel parallel-syncs redis ${SENTINEL_PARALLEL_SYNCS}"
+    if [ -n "${REDIS_PASSWORD}" ]; then
+        echo "sentinel auth-pass redis ${REDIS_PASSWORD}"
+    fi
+}
+
+# Runs curl with the requests signed for the S3 compatible backup target
+function s3_curl() {
+    curl --fail --silent --show-error \
+        --aws-sigv4 "aws:amz:${S3_REGION}:s3" \
+        --user "${AWS_ACCESS_KEY_ID}:${AWS_SECRET_ACCESS_KEY}" "$@"
+}
+
+# Prints the path-style URL of an object in the S3 backup bucket
+function s3_url() {
+    echo "${S3_ENDPOINT}/${S3_BUCKET}/$1"
+}
+
+# Copies the backup to restore to the given file and verifies its checksum
+function restore_backup() {
+    local dest="$1"
+    if [ -n "${S3_ENDPOINT}" ]; then
+        s3_curl -o "${dest}.tmp" "$(s3_url "${RESTORE_LOCATION}")" || return 1
+    else
+        cp "/var/lib/redis-backup/${RESTORE_LOCATION}" "${dest}.tmp" || return 1
+    fi
+    checksum=$(sha256sum "${dest}.tmp" | awk '{print $1}')
+    if [ "${checksum}" != "${RESTORE_CHECKSUM}" ]; then
+        log_error "Checksum mismatch of ${RESTORE_LOCATION}: ${checksum}, expected ${RESTORE_CHECKSUM}"
+        rm -f "${dest}.tmp"
+        return 1
+    fi
+    mv "${dest}.tmp" "${dest}"
 }
 
 function is_bootstrap_pod() {
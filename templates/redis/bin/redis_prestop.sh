@@ -0,0 +1,71 @@
+#!/bin/bash
+
+. /var/lib/operator-scripts/common.sh
+
+# Hands the master role over to a replica before the pod terminates, so that clients
+# don't wait for the sentinels to detect the master as down. The hook of the redis
+# container requests the failover, the hook of the sentinel container keeps the
+# sentinel running until the handover is over. In cluster mode a replica of the
+# shard takes the master role over with a manual cluster failover.
+HANDOVER_TIMEOUT=${HANDOVER_TIMEOUT:-20}
+
+function is_master() {
+    timeout ${TIMEOUT} $REDIS_CLI_CMD info replication | tr -d '\r' | grep -q '^role:master$'
+}
+
+function wait_for_handover() {
+    for i in $(seq ${HANDOVER_TIMEOUT}); do
+        if ! is_master; then
+            return 0
+        fi
+        sleep 1
+    done
+    return 1
+}
+
+case "$1" in
+    redis)
+        if ! is_master; then
+            exit 0
+        fi
+        output=$(timeout ${TIMEOUT} $REDIS_CLI_CMD -p 26379 sentinel failover redis)
+        if [ "${output}" != "OK" ]; then
+            log_error "Requesting a failover failed, terminating as master: ${output}"
+            exit 0
+        fi
+        log "Requested a failover before terminating"
+        if wait_for_handover; then
+            log "Handed the master role over to $(timeout ${TIMEOUT} $REDIS_CLI_CMD -p 26379 sentinel get-master-addr-by-name redis | head -1)"
+        else
+            log_error "No new master after ${HANDOVER_TIMEOUT}s, terminating as master"
+        fi
+        ;;
+    sentinel)
+        wait_for_handover || true
+        ;;
+    cluster)
+        if ! is_master; then
+            exit 0
+        fi
+        myid=$(timeout ${TIMEOUT} $REDIS_CLI_CMD cluster myid | tr -d '\r')
+        replica=$(timeout ${TIMEOUT} $REDIS_CLI_CMD cluster replicas "${myid}" | tr -d '\r' | awk '$3 !~ /fail/ {split($2, a, ":"); print a[1]; exit}')
+        if [ -z "${replica}" ]; then
+            log "No replica to hand the master role over to"
+            exit 0
+        fi
+        output=$(timeout ${TIMEOUT} $REDIS_CLI_CMD -h "${replica}" cluster failover | tr -d '\r')
+        if [ "${output}" != "OK" ]; then
+            log_error "Requesting a failover failed, terminating as master: ${output}"
+            exit 0
+        fi
+        log "Requested a cluster failover to ${replica} before terminating"
+        if wait_for_handover; then
+            log "Handed the master role over to ${replica}"
+        else
+            log_error "No new master after ${HANDOVER_TIMEOUT}s, terminating as master"
+        fi
+        ;;
+    *)
+        echo "Invalid pre-stop option '$1'"
+        exit 1;;
+esac
@@ -1,8 +1,10 @@
 #!/bin/bash
-set -eux
 
+# sourced before enabling tracing to keep the password out of the probe output
 . /var/lib/operator-scripts/common.sh
 
+set -eux
+
 case "$1" in
     readiness)
         # ready if we're the master or if we're a slave connected to the current master
@@ -0,0 +1,64 @@
+#!/bin/bash
+
+. /var/lib/operator-scripts/common.sh
+
+NODES_CONF=/var/lib/redis/nodes.conf
+
+# Prints the current IP of a pod from the headless service
+function resolve() {
+    getent hosts "$1" | awk '{print $1; exit}'
+}
+
+# The nodes know their peers by the IPs in nodes.conf, which are gone when the pods got
+# rescheduled. Every node announces its FQDN as hostname, so the addresses get refreshed from
+# the headless service before the node tries to reach its peers.
+function refresh_peer_addresses() {
+    local fields address host ip
+    while read -r line; do
+        fields=(${line})
+        address=${fields[1]}
+        host=${address#*,}
+        if [ "${fields[0]}" != "vars" ] && [ "${host}" != "${address}" ]; then
+            ip=$(resolve "${host}")
+            if [ -n "${ip}" ]; then
+                line="${fields[0]} ${ip}:${address#*:} ${line#* * }"
+            fi
+        fi
+        echo "${line}"
+    done < ${NODES_CONF} > ${NODES_CONF}.new
+    mv ${NODES_CONF}.new ${NODES_CONF}
+}
+
+# Peers that were not resolvable yet, or that got a new IP while this node runs, are met at
+# their current address. The handshake with a known node ID updates its address.
+function meet_peers() {
+    local host ip
+    while true; do
+        sleep 10
+        timeout ${TIMEOUT} $REDIS_CLI_CMD cluster nodes 2>/dev/null | tr -d '\r' |
+            awk '$3 !~ /myself/ && ($3 ~ /fail/ || $8 == "disconnected") {print $2}' |
+            while read -r address; do
+                host=${address#*,}
+                [ "${host}" != "${address}" ] || continue
+                ip=$(resolve "${host}")
+                if [ -n "${ip}" ] && [ "${ip}" != "${address%%:*}" ]; then
+                    log "Meeting ${host} at its new address ${ip}"
+                    timeout ${TIMEOUT} $REDIS_CLI_CMD cluster meet "${ip}" 6379 >/dev/null
+                fi
+            done
+    done
+}
+
+generate_configs
+sudo -E kolla_set_configs
+
+# The node keeps its ID, its peers and its slots in nodes.conf on the data volume. A new
+# node starts empty, the cluster job joins it and assigns its slots or its master.
+if [ -f ${NODES_CONF} ]; then
+    log "Rejoining the Redis cluster as ${POD_FQDN}"
+    refresh_peer_addresses
+else
+    log "Starting a new Redis cluster node ${POD_FQDN}"
+fi
+meet_peers &
+exec redis-server $REDIS_CONFIG --protected-mode no
@@ -16,9 +16,20 @@ fi
 
 # 2. else bootstrap a new cluster (assume we should be the first redis pod)
 if is_bootstrap_pod $POD_NAME; then
+    extra_args=""
+    if [ -n "${RESTORE_LOCATION}" ]; then
+        log "Seeding the new Redis cluster with the backup ${RESTORE_LOCATION}"
+        if ! restore_backup /var/lib/redis/dump.rdb; then
+            log_error "Could not restore the backup ${RESTORE_LOCATION}"
+            exit 1
+        fi
+        # An AOF takes precedence over the RDB when loading, it gets written again once the
+        # restore finished and the pods restart without it
+        extra_args="--appendonly no"
+    fi
     log "Bootstrapping a new Redis cluster from ${POD_NAME}"
     set_pod_label $POD_NAME redis~1master
-    exec redis-server $REDIS_CONFIG --protected-mode no
+    exec redis-server $REDIS_CONFIG --protected-mode no $extra_args
 fi
 
 # 3. else this is an error, exit and let the pod restart and try again
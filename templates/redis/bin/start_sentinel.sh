@@ -1,6 +1,7 @@
 #!/bin/bash
 
 . /var/lib/operator-scripts/common.sh
+. /var/lib/config-data/sentinel/sentinel.env
 
 generate_configs
 sudo -E kolla_set_configs
@@ -11,7 +12,7 @@ if [ $? -eq 0 ]; then
     master=$(echo "$output" | awk '/^ip$/ {getline; print $0; exit}')
     # TODO skip if no master was found
     log "Connecting to the existing sentinel cluster (master: $master)"
-    echo "sentinel monitor redis ${master} 6379 ${SENTINEL_QUORUM}" >> $SENTINEL_CONFIG
+    sentinel_monitor_config ${master} >> $SENTINEL_CONFIG
     exec redis-sentinel $SENTINEL_CONFIG
 fi
 
@@ -19,7 +20,7 @@ fi
 # (assume we should be the first redis pod)
 if is_bootstrap_pod $POD_NAME; then
     log "Bootstrapping a new sentinel cluster"
-    echo "sentinel monitor redis ${POD_FQDN} 6379 ${SENTINEL_QUORUM}" >> $SENTINEL_CONFIG
+    sentinel_monitor_config ${POD_FQDN} >> $SENTINEL_CONFIG
     exec redis-sentinel $SENTINEL_CONFIG
 fi
 
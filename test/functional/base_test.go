@@ -41,7 +41,9 @@ import (
 	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
 	networkv1 "github.com/openstack-k8s-operators/infra-operator/apis/network/v1beta1"
 	rabbitmqv1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
+	rabbitmqapifake "github.com/openstack-k8s-operators/infra-operator/pkg/rabbitmq/api/fake"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 )
@@ -244,6 +246,12 @@ func DeleteRabbitMQCluster(name types.NamespacedName) {
 	}, th.Timeout, th.Interval).Should(Succeed())
 }
 
+// GetRabbitMQAPI returns the in-memory management API of the clusters simulated
+// in the current test namespace, see CreateOrUpdateRabbitMQClusterSecret
+func GetRabbitMQAPI() *rabbitmqapifake.Server {
+	return rabbitmqAPI.Server(fmt.Sprintf("host.%s.svc", namespace))
+}
+
 func CreateOrUpdateRabbitMQClusterSecret(name types.NamespacedName, mq *rabbitmqclusterv2.RabbitmqCluster) {
 	Eventually(func(g Gomega) {
 		secret := &corev1.Secret{
@@ -380,6 +388,54 @@ func SimulateRabbitMQVhostReady(name types.NamespacedName) {
 	th.Logger.Info("Simulated RabbitMQVhost ready", "on", name)
 }
 
+// SimulateRabbitMQPerPodServicesReady function assigns a LoadBalancer IP to
+// each per-pod service of the RabbitMq object.
+//
+// Example usage:
+//
+//	SimulateRabbitMQPerPodServicesReady(types.NamespacedName{Name: "rabbitmq", Namespace: "test-namespace"}, 3)
+func SimulateRabbitMQPerPodServicesReady(name types.NamespacedName, replicas int) {
+	for i := 0; i < replicas; i++ {
+		svcName := types.NamespacedName{
+			Name:      fmt.Sprintf("%s-server-%d", name.Name, i),
+			Namespace: name.Namespace,
+		}
+		Eventually(func(g Gomega) {
+			svc := &corev1.Service{}
+			g.Expect(k8sClient.Get(ctx, svcName, svc)).Should(Succeed())
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{
+				{IP: fmt.Sprintf("192.0.2.%d", 11+i)},
+			}
+			g.Expect(k8sClient.Status().Update(ctx, svc)).Should(Succeed())
+		}, th.Timeout, th.Interval).Should(Succeed())
+	}
+	th.Logger.Info("Simulated RabbitMq per-pod services ready", "on", name)
+}
+
+// SimulateMemcachedPerPodServicesReady function assigns a LoadBalancer IP to
+// each per-pod service of the Memcached object.
+//
+// Example usage:
+//
+//	SimulateMemcachedPerPodServicesReady(types.NamespacedName{Name: "memcached", Namespace: "test-namespace"}, 3)
+func SimulateMemcachedPerPodServicesReady(name types.NamespacedName, replicas int) {
+	for i := 0; i < replicas; i++ {
+		svcName := types.NamespacedName{
+			Name:      fmt.Sprintf("%s-%d", name.Name, i),
+			Namespace: name.Namespace,
+		}
+		Eventually(func(g Gomega) {
+			svc := &corev1.Service{}
+			g.Expect(k8sClient.Get(ctx, svcName, svc)).Should(Succeed())
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{
+				{IP: fmt.Sprintf("192.0.2.%d", 21+i)},
+			}
+			g.Expect(k8sClient.Status().Update(ctx, svc)).Should(Succeed())
+		}, th.Timeout, th.Interval).Should(Succeed())
+	}
+	th.Logger.Info("Simulated Memcached per-pod services ready", "on", name)
+}
+
 func GetDNSMasq(name types.NamespacedName) *networkv1.DNSMasq {
 	instance := &networkv1.DNSMasq{}
 	Eventually(func(g Gomega) {
@@ -1201,3 +1257,24 @@ func RabbitMQPolicyConditionGetter(name types.NamespacedName) condition.Conditio
 	instance := GetRabbitMQPolicy(name)
 	return instance.Status.Conditions
 }
+
+func CreateRedisUser(name types.NamespacedName, spec map[string]any) client.Object {
+	raw := map[string]any{
+		"apiVersion": "redis.openstack.org/v1beta1",
+		"kind":       "RedisUser",
+		"metadata": map[string]any{
+			"name":      name.Name,
+			"namespace": name.Namespace,
+		},
+		"spec": spec,
+	}
+	return th.CreateUnstructured(raw)
+}
+
+func GetRedisUser(name types.NamespacedName) *redisv1.RedisUser {
+	instance := &redisv1.RedisUser{}
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(ctx, name, instance)).Should(Succeed())
+	}, timeout, interval).Should(Succeed())
+	return instance
+}
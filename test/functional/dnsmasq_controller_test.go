@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"    //revive:disable:dot-imports
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
@@ -387,6 +388,40 @@ var _ = Describe("DNSMasq controller", func() {
 		})
 	})
 
+	When("A DNSMasq is created with multiple replicas", func() {
+		BeforeEach(func() {
+			spec := GetDefaultDNSMasqSpec()
+			spec["replicas"] = 2
+			instance := CreateDNSMasq(namespace, spec)
+			dnsMasqName = types.NamespacedName{
+				Name:      instance.GetName(),
+				Namespace: namespace,
+			}
+			deploymentName = types.NamespacedName{
+				Namespace: namespace,
+				Name:      "dnsmasq-" + dnsMasqName.Name,
+			}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("creates a PodDisruptionBudget for the deployment", func() {
+			th.SimulateLoadBalancerServiceIP(deploymentName)
+
+			Eventually(func(g Gomega) {
+				pdb := &policyv1.PodDisruptionBudget{}
+				g.Expect(k8sClient.Get(ctx, deploymentName, pdb)).Should(Succeed())
+				g.Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+				g.Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"service": "dnsmasq"}))
+			}, timeout, interval).Should(Succeed())
+			th.ExpectCondition(
+				dnsMasqName,
+				ConditionGetterFunc(DNSMasqConditionGetter),
+				condition.PDBReadyCondition,
+				corev1.ConditionTrue,
+			)
+		})
+	})
+
 	When("A DNSMasq is created with nodeSelector", func() {
 		BeforeEach(func() {
 			spec := GetDefaultDNSMasqSpec()
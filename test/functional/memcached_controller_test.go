@@ -17,6 +17,7 @@ limitations under the License.
 package functional_test
 
 import (
+	"encoding/json"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2" //revive:disable:dot-imports
@@ -24,12 +25,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	//revive:disable-next-line:dot-imports
+	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
+	networkv1 "github.com/openstack-k8s-operators/infra-operator/apis/network/v1beta1"
 	topologyv1 "github.com/openstack-k8s-operators/infra-operator/apis/topology/v1beta1"
+	"github.com/openstack-k8s-operators/infra-operator/internal/memcached"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -53,6 +62,24 @@ var _ = Describe("Memcached Controller", func() {
 				GetMemcached(memcachedName)
 			}, timeout, interval).Should(Succeed())
 		})
+
+		It("publishes the client configuration", func() {
+			secretName := types.NamespacedName{Name: memcachedName.Name + "-client-config", Namespace: namespace}
+			Eventually(func(g Gomega) {
+				instance := GetMemcached(memcachedName)
+				g.Expect(instance.GetMemcachedClientConfigSecret()).To(Equal(secretName.Name))
+
+				secret := th.GetSecret(secretName)
+				g.Expect(string(secret.Data["oslo-cache.conf"])).To(ContainSubstring("backend = dogpile.cache.memcached\n"))
+				g.Expect(string(secret.Data["oslo-cache.conf"])).To(ContainSubstring(
+					"memcache_servers = " + instance.GetMemcachedServerListWithInetString() + "\n"))
+
+				config := memcachedv1.MemcachedClientConfig{}
+				g.Expect(json.Unmarshal(secret.Data["memcached.json"], &config)).To(Succeed())
+				g.Expect(config).To(Equal(instance.GetMemcachedClientConfig()))
+				g.Expect(config.ServersWithInet).To(HaveLen(1))
+			}, timeout, interval).Should(Succeed())
+		})
 	})
 
 	When("Deployment rollout is progressing", func() {
@@ -118,6 +145,708 @@ var _ = Describe("Memcached Controller", func() {
 		})
 	})
 
+	When("a Memcached gets created with SASL authentication", func() {
+		BeforeEach(func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["auth"] = map[string]any{
+				"enabled":  true,
+				"username": "keystone",
+			}
+			memcached := CreateMemcachedConfig(namespace, spec)
+			memcachedName.Name = memcached.GetName()
+			memcachedName.Namespace = memcached.GetNamespace()
+			DeferCleanup(th.DeleteInstance, memcached)
+		})
+
+		It("generates the credentials and reports the secret in the status", func() {
+			secretName := types.NamespacedName{Name: memcachedName.Name + "-auth", Namespace: namespace}
+			Eventually(func(g Gomega) {
+				secret := th.GetSecret(secretName)
+				g.Expect(secret.Data).To(HaveKeyWithValue("username", []byte("keystone")))
+				g.Expect(secret.Data["password"]).To(HaveLen(32))
+				g.Expect(string(secret.Data["memcached-sasl-db"])).To(
+					Equal(fmt.Sprintf("keystone:%s\n", secret.Data["password"])))
+
+				instance := GetMemcached(memcachedName)
+				g.Expect(instance.Status.AuthSecret).To(Equal(secretName.Name))
+				g.Expect(instance.GetMemcachedAuthEnabled()).To(BeTrue())
+				g.Expect(instance.CreateAuthVolume().Secret.SecretName).To(Equal(secretName.Name))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("includes the credentials in the client configuration", func() {
+			Eventually(func(g Gomega) {
+				password := th.GetSecret(types.NamespacedName{Name: memcachedName.Name + "-auth", Namespace: namespace}).Data["password"]
+				g.Expect(password).ToNot(BeEmpty())
+
+				secret := th.GetSecret(types.NamespacedName{Name: memcachedName.Name + "-client-config", Namespace: namespace})
+				conf := string(secret.Data["oslo-cache.conf"])
+				g.Expect(conf).To(ContainSubstring("backend = oslo_cache.memcache_pool\n"))
+				g.Expect(conf).To(ContainSubstring("memcache_sasl_enabled = true\n"))
+				g.Expect(conf).To(ContainSubstring("memcache_username = keystone\n"))
+				g.Expect(conf).To(ContainSubstring("memcache_password = " + string(password) + "\n"))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("turns on SASL in the memcached config", func() {
+			Eventually(func(g Gomega) {
+				cm := th.GetConfigMap(types.NamespacedName{Name: memcachedName.Name + "-config-data", Namespace: namespace})
+				g.Expect(cm.Data["memcached"]).To(ContainSubstring("-S -B binary"))
+				g.Expect(cm.Data["memcached"]).To(ContainSubstring("MEMCACHED_SASL_PWDB=/etc/sasl2/memcached-sasl-db"))
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				ss := th.GetStatefulSet(memcachedName)
+				volumeNames := []string{}
+				for _, v := range ss.Spec.Template.Spec.Volumes {
+					volumeNames = append(volumeNames, v.Name)
+				}
+				g.Expect(volumeNames).To(ContainElement("sasl"))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("keeps the password when the username changes", func() {
+			secretName := types.NamespacedName{Name: memcachedName.Name + "-auth", Namespace: namespace}
+			var password []byte
+			Eventually(func(g Gomega) {
+				password = th.GetSecret(secretName).Data["password"]
+				g.Expect(password).ToNot(BeEmpty())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				instance := GetMemcached(memcachedName)
+				instance.Spec.Auth.Username = "nova"
+				g.Expect(k8sClient.Update(ctx, instance)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				secret := th.GetSecret(secretName)
+				g.Expect(secret.Data).To(HaveKeyWithValue("username", []byte("nova")))
+				g.Expect(secret.Data["password"]).To(Equal(password))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("a Memcached gets created with metrics enabled", func() {
+		BeforeEach(func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["exporterImage"] = "quay.io/prometheus/memcached-exporter:test"
+			spec["metrics"] = map[string]any{
+				"enabled": true,
+			}
+			memcached := CreateMemcachedConfig(namespace, spec)
+			memcachedName.Name = memcached.GetName()
+			memcachedName.Namespace = memcached.GetNamespace()
+			DeferCleanup(th.DeleteInstance, memcached)
+		})
+
+		It("adds the exporter sidecar and the metrics port", func() {
+			Eventually(func(g Gomega) {
+				ss := th.GetStatefulSet(memcachedName)
+				containers := ss.Spec.Template.Spec.Containers
+				g.Expect(containers).To(HaveLen(2))
+				g.Expect(containers[1].Name).To(Equal("memcached-exporter"))
+				g.Expect(containers[1].Image).To(Equal("quay.io/prometheus/memcached-exporter:test"))
+				g.Expect(containers[1].Args).To(ContainElement("--memcached.address=127.0.0.1:11211"))
+
+				svc := th.GetService(memcachedName)
+				portNames := []string{}
+				for _, p := range svc.Spec.Ports {
+					portNames = append(portNames, p.Name)
+				}
+				g.Expect(portNames).To(ContainElement("metrics"))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("summarizes the exporter stats in the status", func() {
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+
+			Eventually(func(g Gomega) {
+				instance := GetMemcached(memcachedName)
+				g.Expect(instance.Status.Metrics).ToNot(BeNil())
+				g.Expect(instance.Status.Metrics.HitRatio).To(Equal("90.0%"))
+				g.Expect(instance.Status.Metrics.Evictions).To(Equal(int64(2)))
+				g.Expect(instance.Status.Metrics.CurrentConnections).To(Equal(int64(5)))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("a Memcached gets created with metrics and SASL authentication", func() {
+		It("gets blocked by the webhook and fail", func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["metrics"] = map[string]any{"enabled": true}
+			spec["auth"] = map[string]any{"enabled": true}
+
+			raw := map[string]any{
+				"apiVersion": "memcached.openstack.org/v1beta1",
+				"kind":       "Memcached",
+				"metadata": map[string]any{
+					"name":      "memcached-metrics-auth",
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("metrics are not supported together with SASL authentication"))
+		})
+	})
+
+	When("a Memcached gets created with per-pod LoadBalancer services", func() {
+		BeforeEach(func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["replicas"] = 2
+			lbService := map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]any{
+						"metallb.universe.tf/address-pool": "internalapi",
+					},
+				},
+				"spec": map[string]any{
+					"type": "LoadBalancer",
+				},
+			}
+			spec["podOverride"] = map[string]any{
+				"services": []any{lbService, lbService},
+			}
+			memcached := CreateMemcachedConfig(namespace, spec)
+			memcachedName.Name = memcached.GetName()
+			memcachedName.Namespace = memcached.GetNamespace()
+			DeferCleanup(th.DeleteInstance, memcached)
+		})
+
+		It("creates a service per pod registered with dnsmasq", func() {
+			for i := 0; i < 2; i++ {
+				svc := th.GetService(types.NamespacedName{
+					Name:      fmt.Sprintf("%s-%d", memcachedName.Name, i),
+					Namespace: namespace,
+				})
+				Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+				Expect(svc.Spec.Selector).To(HaveKeyWithValue(
+					"statefulset.kubernetes.io/pod-name", fmt.Sprintf("%s-%d", memcachedName.Name, i)))
+				Expect(svc.Annotations).To(HaveKey(networkv1.AnnotationHostnameKey))
+			}
+		})
+
+		It("uses the external IPs in the server lists", func() {
+			SimulateMemcachedPerPodServicesReady(memcachedName, 2)
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+
+			Eventually(func(g Gomega) {
+				instance := GetMemcached(memcachedName)
+				g.Expect(instance.Status.ServerList).To(Equal([]string{
+					"192.0.2.21:11211", "192.0.2.22:11211"}))
+				g.Expect(instance.Status.ServerListWithInet).To(Equal([]string{
+					"inet:192.0.2.21:11211", "inet:192.0.2.22:11211"}))
+				g.Expect(instance.Status.ServiceHostnames).To(HaveLen(2))
+			}, timeout, interval).Should(Succeed())
+
+			th.ExpectCondition(
+				memcachedName,
+				ConditionGetterFunc(MemcachedConditionGetter),
+				condition.ReadyCondition,
+				corev1.ConditionTrue,
+			)
+		})
+
+		It("keeps the service of a removed pod until the pod is gone", func() {
+			SimulateMemcachedPerPodServicesReady(memcachedName, 2)
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+			Eventually(func(g Gomega) {
+				g.Expect(GetMemcached(memcachedName).Status.ServiceHostnames).To(HaveLen(2))
+			}, timeout, interval).Should(Succeed())
+
+			// the StatefulSet controller does not run in the test env
+			podName := types.NamespacedName{Name: fmt.Sprintf("%s-1", memcachedName.Name), Namespace: namespace}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: podName.Name, Namespace: podName.Namespace},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "memcached", Image: "memcached"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				mc := GetMemcached(memcachedName)
+				mc.Spec.Replicas = ptr.To[int32](1)
+				mc.Spec.PodOverride.Services = mc.Spec.PodOverride.Services[:1]
+				g.Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(GetMemcached(memcachedName).Status.ServerList).To(Equal([]string{"192.0.2.21:11211"}))
+			}, timeout, interval).Should(Succeed())
+			Consistently(func(g Gomega) {
+				svc := &corev1.Service{}
+				g.Expect(k8sClient.Get(ctx, podName, svc)).Should(Succeed())
+				g.Expect(GetMemcached(memcachedName).Status.ServiceHostnames).To(HaveLen(2))
+			}, "2s", interval).Should(Succeed())
+
+			Expect(k8sClient.Delete(ctx, pod)).Should(Succeed())
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+
+			Eventually(func(g Gomega) {
+				svc := &corev1.Service{}
+				err := k8sClient.Get(ctx, podName, svc)
+				g.Expect(k8s_errors.IsNotFound(err)).To(BeTrue())
+				g.Expect(GetMemcached(memcachedName).Status.ServiceHostnames).To(HaveLen(1))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("a Memcached gets created with multiple replicas", func() {
+		BeforeEach(func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["replicas"] = 3
+			memcached := CreateMemcachedConfig(namespace, spec)
+			memcachedName.Name = memcached.GetName()
+			memcachedName.Namespace = memcached.GetNamespace()
+			DeferCleanup(th.DeleteInstance, memcached)
+		})
+
+		It("creates a PodDisruptionBudget derived from the replicas", func() {
+			Eventually(func(g Gomega) {
+				pdb := &policyv1.PodDisruptionBudget{}
+				g.Expect(k8sClient.Get(ctx, memcachedName, pdb)).Should(Succeed())
+				g.Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+				g.Expect(pdb.Spec.Selector.MatchLabels).To(HaveKeyWithValue("cr", memcachedName.Name))
+			}, timeout, interval).Should(Succeed())
+			th.ExpectCondition(
+				memcachedName,
+				ConditionGetterFunc(MemcachedConditionGetter),
+				condition.PDBReadyCondition,
+				corev1.ConditionTrue,
+			)
+		})
+
+		It("deletes the PodDisruptionBudget when it gets disabled", func() {
+			Eventually(func(g Gomega) {
+				pdb := &policyv1.PodDisruptionBudget{}
+				g.Expect(k8sClient.Get(ctx, memcachedName, pdb)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				mc := GetMemcached(memcachedName)
+				mc.Spec.PodDisruptionBudget.Enabled = ptr.To(false)
+				g.Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				pdb := &policyv1.PodDisruptionBudget{}
+				err := k8sClient.Get(ctx, memcachedName, pdb)
+				g.Expect(k8s_errors.IsNotFound(err)).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("rejects a maxUnavailable covering all replicas", func() {
+			Eventually(func(g Gomega) {
+				mc := GetMemcached(memcachedName)
+				maxUnavailable := intstr.FromInt32(3)
+				mc.Spec.PodDisruptionBudget.MaxUnavailable = &maxUnavailable
+				err := k8sClient.Update(ctx, mc)
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("spec.podDisruptionBudget.maxUnavailable"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("a Memcached gets created with tunables and extstore", func() {
+		BeforeEach(func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["threads"] = 8
+			spec["itemSizeMax"] = "2Mi"
+			spec["verbosity"] = 1
+			spec["eviction"] = map[string]any{
+				"disabled":   true,
+				"lruCrawler": false,
+			}
+			spec["extstore"] = map[string]any{
+				"storageRequest": "10Gi",
+				"storageClass":   "local-storage",
+			}
+			memcached := CreateMemcachedConfig(namespace, spec)
+			memcachedName.Name = memcached.GetName()
+			memcachedName.Namespace = memcached.GetNamespace()
+			DeferCleanup(th.DeleteInstance, memcached)
+		})
+
+		It("passes the options to memcached", func() {
+			Eventually(func(g Gomega) {
+				cm := th.GetConfigMap(types.NamespacedName{Name: memcachedName.Name + "-config-data", Namespace: namespace})
+				g.Expect(cm.Data["memcached"]).To(ContainSubstring(
+					"-t 8 -I 2097152 -M -v -o no_lru_crawler,ext_path=/var/lib/memcached/extstore/extstore:9216M"))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("requests a PVC per pod for extstore", func() {
+			Eventually(func(g Gomega) {
+				ss := th.GetStatefulSet(memcachedName)
+				g.Expect(ss.Spec.VolumeClaimTemplates).To(HaveLen(1))
+				pvc := ss.Spec.VolumeClaimTemplates[0]
+				g.Expect(pvc.Name).To(Equal("extstore"))
+				g.Expect(*pvc.Spec.StorageClassName).To(Equal("local-storage"))
+				g.Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("10Gi"))
+
+				mounts := []string{}
+				for _, m := range ss.Spec.Template.Spec.Containers[0].VolumeMounts {
+					mounts = append(mounts, m.MountPath)
+				}
+				g.Expect(mounts).To(ContainElement("/var/lib/memcached/extstore"))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("rejects changing extstore", func() {
+			Eventually(func(g Gomega) {
+				mc := GetMemcached(memcachedName)
+				mc.Spec.Extstore.StorageRequest = "20Gi"
+				err := k8sClient.Update(ctx, mc)
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("extstore can not be changed"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("a Memcached gets created with out of range tunables", func() {
+		It("gets blocked by the webhook and fail", func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["threads"] = 0
+			spec["itemSizeMax"] = "2Gi"
+			spec["verbosity"] = 4
+
+			raw := map[string]any{
+				"apiVersion": "memcached.openstack.org/v1beta1",
+				"kind":       "Memcached",
+				"metadata": map[string]any{
+					"name":      "memcached-tunables",
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.threads"))
+			Expect(err.Error()).To(ContainSubstring("spec.itemSizeMax"))
+			Expect(err.Error()).To(ContainSubstring("spec.verbosity"))
+		})
+	})
+
+	When("a Memcached with the Staged resize strategy gets resized", func() {
+		BeforeEach(func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["replicas"] = 2
+			spec["resize"] = map[string]any{
+				"strategy":           "Staged",
+				"warmupSeconds":      3600,
+				"gracePeriodSeconds": 3600,
+			}
+			memcached := CreateMemcachedConfig(namespace, spec)
+			memcachedName.Name = memcached.GetName()
+			memcachedName.Namespace = memcached.GetNamespace()
+			DeferCleanup(th.DeleteInstance, memcached)
+
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+			Eventually(func(g Gomega) {
+				g.Expect(GetMemcached(memcachedName).Status.ServerList).To(HaveLen(2))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("lists new servers only after they warmed up", func() {
+			Eventually(func(g Gomega) {
+				mc := GetMemcached(memcachedName)
+				mc.Spec.Replicas = ptr.To[int32](3)
+				g.Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(*th.GetStatefulSet(memcachedName).Spec.Replicas).To(Equal(int32(3)))
+			}, timeout, interval).Should(Succeed())
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+
+			Eventually(func(g Gomega) {
+				instance := GetMemcached(memcachedName)
+				g.Expect(instance.Status.Resize).ToNot(BeNil())
+				g.Expect(instance.Status.Resize.Phase).To(Equal(memcachedv1.ResizePhaseWarmingUp))
+				g.Expect(instance.Status.Resize.Started).ToNot(BeNil())
+			}, timeout, interval).Should(Succeed())
+			Consistently(func(g Gomega) {
+				g.Expect(GetMemcached(memcachedName).Status.ServerList).To(HaveLen(2))
+			}, "2s", interval).Should(Succeed())
+			th.ExpectConditionWithDetails(
+				memcachedName,
+				ConditionGetterFunc(MemcachedConditionGetter),
+				memcachedv1.MemcachedResizeReadyCondition,
+				corev1.ConditionFalse,
+				condition.RequestedReason,
+				"Resizing from 2 to 3 replicas, warming up new servers",
+			)
+		})
+
+		It("unlists removed servers before deleting their pods", func() {
+			Eventually(func(g Gomega) {
+				mc := GetMemcached(memcachedName)
+				mc.Spec.Replicas = ptr.To[int32](1)
+				g.Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				instance := GetMemcached(memcachedName)
+				g.Expect(instance.Status.ServerList).To(HaveLen(1))
+				g.Expect(instance.Status.Resize).ToNot(BeNil())
+				g.Expect(instance.Status.Resize.Phase).To(Equal(memcachedv1.ResizePhaseDraining))
+			}, timeout, interval).Should(Succeed())
+			Consistently(func(g Gomega) {
+				g.Expect(*th.GetStatefulSet(memcachedName).Spec.Replicas).To(Equal(int32(2)))
+			}, "2s", interval).Should(Succeed())
+			th.ExpectConditionWithDetails(
+				memcachedName,
+				ConditionGetterFunc(MemcachedConditionGetter),
+				memcachedv1.MemcachedResizeReadyCondition,
+				corev1.ConditionFalse,
+				condition.RequestedReason,
+				"Resizing from 2 to 1 replicas, draining removed servers",
+			)
+		})
+	})
+
+	When("a Memcached gets created with autoscaling", func() {
+		BeforeEach(func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["replicas"] = 2
+			spec["exporterImage"] = "quay.io/prometheus/memcached-exporter:test"
+			spec["metrics"] = map[string]any{
+				"enabled": true,
+			}
+			spec["autoscaling"] = map[string]any{
+				"minReplicas": 2,
+				"maxReplicas": 4,
+			}
+			instance := CreateMemcachedConfig(namespace, spec)
+			memcachedName.Name = instance.GetName()
+			memcachedName.Namespace = instance.GetNamespace()
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("adds a replica on memory pressure and lists it", func() {
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+
+			Eventually(func(g Gomega) {
+				instance := GetMemcached(memcachedName)
+				g.Expect(instance.Status.Autoscaling).ToNot(BeNil())
+				g.Expect(instance.Status.Autoscaling.MemoryUtilization).To(Equal(int32(93)))
+				g.Expect(instance.Status.Autoscaling.DesiredReplicas).To(Equal(int32(3)))
+				g.Expect(instance.Status.Autoscaling.LastScaleTime).ToNot(BeNil())
+				g.Expect(*th.GetStatefulSet(memcachedName).Spec.Replicas).To(Equal(int32(3)))
+			}, timeout, interval).Should(Succeed())
+
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+			Eventually(func(g Gomega) {
+				g.Expect(GetMemcached(memcachedName).Status.ServerList).To(HaveLen(3))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("rejects maxReplicas below minReplicas", func() {
+			Eventually(func(g Gomega) {
+				mc := GetMemcached(memcachedName)
+				mc.Spec.Autoscaling.MaxReplicas = 1
+				err := k8sClient.Update(ctx, mc)
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("spec.autoscaling.maxReplicas"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("a Memcached gets created with autoscaling but without metrics", func() {
+		It("gets rejected", func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["autoscaling"] = map[string]any{
+				"maxReplicas": 3,
+			}
+			raw := map[string]any{
+				"apiVersion": "memcached.openstack.org/v1beta1",
+				"kind":       "Memcached",
+				"metadata": map[string]any{
+					"name":      "memcached-autoscaling",
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("metrics have to be enabled for autoscaling"))
+		})
+	})
+
+	When("a Memcached gets created with pools", func() {
+		var poolName types.NamespacedName
+
+		BeforeEach(func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["cacheSize"] = 1024
+			spec["pools"] = []any{
+				map[string]any{
+					"name":      "sessions",
+					"replicas":  2,
+					"cacheSize": 128,
+				},
+			}
+			instance := CreateMemcachedConfig(namespace, spec)
+			memcachedName.Name = instance.GetName()
+			memcachedName.Namespace = instance.GetNamespace()
+			poolName = types.NamespacedName{Name: memcachedName.Name + "-sessions", Namespace: namespace}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("creates a StatefulSet with its own config per pool", func() {
+			Eventually(func(g Gomega) {
+				ss := th.GetStatefulSet(poolName)
+				g.Expect(*ss.Spec.Replicas).To(Equal(int32(2)))
+				g.Expect(ss.Spec.ServiceName).To(Equal(poolName.Name))
+				g.Expect(ss.Spec.Template.Spec.ServiceAccountName).To(Equal("memcached-" + memcachedName.Name))
+
+				cm := th.GetConfigMap(types.NamespacedName{Name: poolName.Name + "-config-data", Namespace: namespace})
+				g.Expect(cm.Data["memcached"]).To(ContainSubstring(`CACHESIZE="128"`))
+				cm = th.GetConfigMap(types.NamespacedName{Name: memcachedName.Name + "-config-data", Namespace: namespace})
+				g.Expect(cm.Data["memcached"]).To(ContainSubstring(`CACHESIZE="1024"`))
+
+				g.Expect(th.GetService(poolName).Spec.ClusterIP).To(Equal("None"))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("creates a PodDisruptionBudget per pool", func() {
+			Eventually(func(g Gomega) {
+				pdb := &policyv1.PodDisruptionBudget{}
+				g.Expect(k8sClient.Get(ctx, poolName, pdb)).Should(Succeed())
+				g.Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+				g.Expect(pdb.Spec.Selector.MatchLabels).To(HaveKeyWithValue("cr", poolName.Name))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("reports the server lists of the pools", func() {
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+			th.SimulateStatefulSetReplicaReady(poolName)
+
+			Eventually(func(g Gomega) {
+				instance := GetMemcached(memcachedName)
+				g.Expect(instance.Status.ServerList).To(HaveLen(1))
+				g.Expect(instance.Status.Pools).To(HaveLen(1))
+				g.Expect(instance.Status.Pools[0].ReadyCount).To(Equal(int32(2)))
+				g.Expect(instance.GetMemcachedPoolServerListString("sessions")).To(Equal(fmt.Sprintf(
+					"%[1]s-0.%[1]s.%[2]s.svc:11211,%[1]s-1.%[1]s.%[2]s.svc:11211", poolName.Name, namespace)))
+			}, timeout, interval).Should(Succeed())
+			th.ExpectCondition(
+				memcachedName,
+				ConditionGetterFunc(MemcachedConditionGetter),
+				condition.DeploymentReadyCondition,
+				corev1.ConditionTrue,
+			)
+		})
+
+		It("deletes the StatefulSet of a removed pool", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(GetMemcached(memcachedName).Status.Pools).To(HaveLen(1))
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				mc := GetMemcached(memcachedName)
+				mc.Spec.Pools = nil
+				g.Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			th.AssertStatefulSetDoesNotExist(poolName)
+			Eventually(func(g Gomega) {
+				g.Expect(GetMemcached(memcachedName).Status.Pools).To(BeEmpty())
+				err := k8sClient.Get(ctx, poolName, &policyv1.PodDisruptionBudget{})
+				g.Expect(k8s_errors.IsNotFound(err)).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("a Memcached gets created with TLS and its certificate gets renewed", func() {
+		var certName types.NamespacedName
+
+		BeforeEach(func() {
+			certName = types.NamespacedName{Namespace: namespace, Name: "memcached-tls-reload"}
+			DeferCleanup(k8sClient.Delete, ctx, CreateCertSecret(certName))
+
+			spec := GetDefaultMemcachedSpec()
+			spec["replicas"] = 2
+			spec["tls"] = map[string]any{
+				"secretName": certName.Name,
+			}
+			instance := CreateMemcachedConfig(namespace, spec)
+			memcachedName.Name = instance.GetName()
+			memcachedName.Namespace = instance.GetNamespace()
+			DeferCleanup(th.DeleteInstance, instance)
+
+			th.SimulateStatefulSetReplicaReady(memcachedName)
+			Eventually(func(g Gomega) {
+				g.Expect(GetMemcached(memcachedName).Status.Hash).To(HaveKey(memcached.CertHashName))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("mounts the whole certificate secret for reloads", func() {
+			container := th.GetStatefulSet(memcachedName).Spec.Template.Spec.Containers[0]
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "memcached-tls-certs",
+				MountPath: memcached.CertReloadMountDir,
+				ReadOnly:  true,
+			}))
+		})
+
+		It("reloads the certificate in the pods without rolling them", func() {
+			configHash := GetEnvVarValue(
+				th.GetStatefulSet(memcachedName).Spec.Template.Spec.Containers[0].Env, "CONFIG_HASH", "")
+			Expect(configHash).ToNot(BeEmpty())
+
+			secret := th.GetSecret(certName)
+			secret.Data["tls.crt"] = append(secret.Data["tls.crt"], '\n')
+			Expect(k8sClient.Update(ctx, &secret)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(GetMemcached(memcachedName).Status.LastCertReload).ToNot(BeNil())
+			}, timeout, interval).Should(Succeed())
+			for i := 0; i < 2; i++ {
+				_, reloaded := certReloads.Load(fmt.Sprintf("%s-%d", memcachedName.Name, i))
+				Expect(reloaded).To(BeTrue())
+			}
+			Expect(GetEnvVarValue(
+				th.GetStatefulSet(memcachedName).Spec.Template.Spec.Containers[0].Env, "CONFIG_HASH", "")).To(Equal(configHash))
+		})
+	})
+
+	When("a Memcached gets created with less per-pod services than replicas", func() {
+		It("gets blocked by the webhook and fail", func() {
+			spec := GetDefaultMemcachedSpec()
+			spec["replicas"] = 2
+			spec["podOverride"] = map[string]any{
+				"services": []any{
+					map[string]any{"spec": map[string]any{"type": "LoadBalancer"}},
+				},
+			}
+
+			raw := map[string]any{
+				"apiVersion": "memcached.openstack.org/v1beta1",
+				"kind":       "Memcached",
+				"metadata": map[string]any{
+					"name":      "memcached-pod-override",
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.podOverride.services"))
+		})
+	})
+
 	When("a default Memcached gets created with topologyRef", func() {
 		var topologyRef, topologyRefAlt *topologyv1.TopoRef
 		BeforeEach(func() {
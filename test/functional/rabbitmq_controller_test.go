@@ -23,15 +23,20 @@ import (
 
 	. "github.com/onsi/ginkgo/v2" //revive:disable:dot-imports
 	. "github.com/onsi/gomega"    //revive:disable:dot-imports
+	networkv1 "github.com/openstack-k8s-operators/infra-operator/apis/network/v1beta1"
 	rabbitmqv1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
+	rabbitmqapifake "github.com/openstack-k8s-operators/infra-operator/pkg/rabbitmq/api/fake"
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 
 	//revive:disable-next-line:dot-imports
 
 	//. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -113,6 +118,40 @@ var _ = Describe("RabbitMQ Controller", func() {
 		})
 	})
 
+	When("a RabbitMQ with multiple replicas gets created", func() {
+		BeforeEach(func() {
+			spec := GetDefaultRabbitMQSpec()
+			spec["replicas"] = 5
+			rabbitmq := CreateRabbitMQ(rabbitmqName, spec)
+			DeferCleanup(th.DeleteInstance, rabbitmq)
+		})
+
+		It("should create a PodDisruptionBudget with maxUnavailable 1", func() {
+			SimulateRabbitMQClusterReady(rabbitmqName)
+			Eventually(func(g Gomega) {
+				pdb := &policyv1.PodDisruptionBudget{}
+				g.Expect(k8sClient.Get(ctx, rabbitmqName, pdb)).Should(Succeed())
+				g.Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("should use the configured maxUnavailable", func() {
+			Eventually(func(g Gomega) {
+				instance := GetRabbitMQ(rabbitmqName)
+				maxUnavailable := intstr.FromInt32(2)
+				instance.Spec.PodDisruptionBudget.MaxUnavailable = &maxUnavailable
+				g.Expect(k8sClient.Update(ctx, instance)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+			SimulateRabbitMQClusterReady(rabbitmqName)
+
+			Eventually(func(g Gomega) {
+				pdb := &policyv1.PodDisruptionBudget{}
+				g.Expect(k8sClient.Get(ctx, rabbitmqName, pdb)).Should(Succeed())
+				g.Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(2))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
 	When("RabbitMQ gets created with TLS enabled", func() {
 		var certSecret *corev1.Secret
 		BeforeEach(func() {
@@ -535,6 +574,46 @@ var _ = Describe("RabbitMQ Controller", func() {
 				}
 			}, timeout, interval).Should(Succeed())
 		})
+
+		It("should register the per-pod service hostnames with dnsmasq", func() {
+			SimulateRabbitMQClusterReady(rabbitmqName)
+			SimulateRabbitMQPerPodServicesReady(rabbitmqName, 3)
+
+			Eventually(func(g Gomega) {
+				for i := 0; i < 3; i++ {
+					svcName := types.NamespacedName{
+						Name:      fmt.Sprintf("%s-server-%d", rabbitmqDefaultName, i),
+						Namespace: namespace,
+					}
+					svc := &corev1.Service{}
+					g.Expect(k8sClient.Get(ctx, svcName, svc)).Should(Succeed())
+					g.Expect(svc.Annotations).To(HaveKeyWithValue(
+						networkv1.AnnotationHostnameKey, fmt.Sprintf("%s.%s.svc", svcName.Name, namespace)))
+				}
+			}, timeout, interval).Should(Succeed())
+
+			// No DNSMasq in the namespace, nothing to wait for
+			Eventually(func(g Gomega) {
+				rabbitmq := GetRabbitMQ(rabbitmqName)
+				g.Expect(rabbitmq.Status.ServiceHostnames).To(HaveLen(3))
+				g.Expect(rabbitmq.Status.Conditions.IsTrue(rabbitmqv1.RabbitMqServiceDNSReadyCondition)).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("should wait for a DNSMasq to become ready", func() {
+			// The DNSMasq deployment never gets ready in the test env
+			DeferCleanup(th.DeleteInstance, CreateDNSMasq(namespace, GetDefaultDNSMasqSpec()))
+			SimulateRabbitMQClusterReady(rabbitmqName)
+			SimulateRabbitMQPerPodServicesReady(rabbitmqName, 3)
+
+			Eventually(func(g Gomega) {
+				rabbitmq := GetRabbitMQ(rabbitmqName)
+				cond := rabbitmq.Status.Conditions.Get(rabbitmqv1.RabbitMqServiceDNSReadyCondition)
+				g.Expect(cond).ToNot(BeNil())
+				g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+				g.Expect(cond.Message).To(Equal(fmt.Sprintf(rabbitmqv1.RabbitMqServiceDNSReadyNoDNSMasqMessage, namespace)))
+			}, timeout, interval).Should(Succeed())
+		})
 	})
 
 	When("RabbitMQ per-pod services with wrong number of service overrides", func() {
@@ -700,4 +779,108 @@ var _ = Describe("RabbitMQ Controller", func() {
 			}, timeout, interval).Should(Succeed())
 		})
 	})
+
+	When("the container image of a running RabbitMQ changes", func() {
+		BeforeEach(func() {
+			spec := GetDefaultRabbitMQSpec()
+			spec["containerImage"] = "quay.io/rabbitmq:3.13"
+			rabbitmq := CreateRabbitMQ(rabbitmqName, spec)
+			DeferCleanup(th.DeleteInstance, rabbitmq)
+			SimulateRabbitMQClusterReady(rabbitmqName)
+
+			Eventually(func(g Gomega) {
+				g.Expect(GetRabbitMQ(rabbitmqName).Status.Version).To(Equal(rabbitmqapifake.DefaultRabbitMQVersion))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("should enable the stable feature flags before rolling the new image", func() {
+			Eventually(func(g Gomega) {
+				instance := GetRabbitMQ(rabbitmqName)
+				instance.Spec.ContainerImage = "quay.io/rabbitmq:4.0"
+				instance.Spec.TargetVersion = "4.0"
+				g.Expect(k8sClient.Update(ctx, instance)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				flag, found := GetRabbitMQAPI().GetFeatureFlag("message_containers")
+				g.Expect(found).To(BeTrue())
+				g.Expect(flag.State).To(Equal("enabled"))
+				// experimental flags are left alone
+				flag, _ = GetRabbitMQAPI().GetFeatureFlag("khepri_db")
+				g.Expect(flag.State).To(Equal("disabled"))
+
+				g.Expect(GetRabbitMQCluster(rabbitmqName).Spec.Image).To(Equal("quay.io/rabbitmq:4.0"))
+
+				instance := GetRabbitMQ(rabbitmqName)
+				g.Expect(instance.Status.Upgrade).ToNot(BeNil())
+				g.Expect(instance.Status.Upgrade.Strategy).To(Equal(rabbitmqv1.UpgradeStrategyInPlace))
+				g.Expect(instance.Status.Upgrade.Phase).To(Equal(rabbitmqv1.UpgradePhaseRollingImage))
+				g.Expect(instance.Status.Upgrade.FromImage).To(Equal("quay.io/rabbitmq:3.13"))
+				g.Expect(instance.Status.Upgrade.FromVersion).To(Equal(rabbitmqapifake.DefaultRabbitMQVersion))
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				instance := GetRabbitMQ(rabbitmqName)
+				upgradeCondition := instance.Status.Conditions.Get(rabbitmqv1.RabbitMqUpgradeReadyCondition)
+				g.Expect(upgradeCondition).ToNot(BeNil())
+				g.Expect(upgradeCondition.Status).To(Equal(corev1.ConditionFalse))
+			}, timeout, interval).Should(Succeed())
+
+			GetRabbitMQAPI().SetVersion("4.0.5")
+			SimulateRabbitMQClusterReady(rabbitmqName)
+
+			Eventually(func(g Gomega) {
+				instance := GetRabbitMQ(rabbitmqName)
+				g.Expect(instance.Status.Version).To(Equal("4.0.5"))
+				g.Expect(instance.Status.Upgrade.Phase).To(Equal(rabbitmqv1.UpgradePhaseCompleted))
+				g.Expect(instance.Status.Upgrade.ToVersion).To(Equal("4.0.5"))
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				instance := GetRabbitMQ(rabbitmqName)
+				upgradeCondition := instance.Status.Conditions.Get(rabbitmqv1.RabbitMqUpgradeReadyCondition)
+				g.Expect(upgradeCondition).ToNot(BeNil())
+				g.Expect(upgradeCondition.Status).To(Equal(corev1.ConditionTrue))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("should move to a green cluster for a BlueGreen upgrade", func() {
+			Eventually(func(g Gomega) {
+				instance := GetRabbitMQ(rabbitmqName)
+				instance.Spec.ContainerImage = "quay.io/rabbitmq:4.0"
+				instance.Spec.TargetVersion = "4.0"
+				instance.Spec.UpgradeStrategy = rabbitmqv1.UpgradeStrategyBlueGreen
+				g.Expect(k8sClient.Update(ctx, instance)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			greenName := types.NamespacedName{Name: rabbitmqName.Name + rabbitmqv1.GreenClusterSuffix, Namespace: namespace}
+			Eventually(func(g Gomega) {
+				green := GetRabbitMQCluster(greenName)
+				g.Expect(green.Spec.Image).To(Equal("quay.io/rabbitmq:4.0"))
+				g.Expect(green.Spec.Rabbitmq.AdditionalPlugins).To(ContainElement(rabbitmqclusterv2.Plugin("rabbitmq_shovel")))
+				g.Expect(GetRabbitMQCluster(rabbitmqName).Spec.Image).To(Equal("quay.io/rabbitmq:3.13"))
+			}, timeout, interval).Should(Succeed())
+
+			GetRabbitMQAPI().SetQueue("/", "notifications", 3)
+			SimulateRabbitMQClusterReady(greenName)
+
+			Eventually(func(g Gomega) {
+				instance := GetRabbitMQ(rabbitmqName)
+				g.Expect(instance.Status.Upgrade).ToNot(BeNil())
+				g.Expect(instance.Status.Upgrade.Phase).To(Equal(rabbitmqv1.UpgradePhaseMigratingToGreen))
+				g.Expect(instance.GetActiveClusterName()).To(Equal(greenName.Name))
+
+				shovel, found := GetRabbitMQAPI().GetShovel("/", "upgrade-notifications")
+				g.Expect(found).To(BeTrue())
+				g.Expect(shovel.Value).To(HaveKeyWithValue("src-queue", "notifications"))
+			}, timeout, interval).Should(Succeed())
+
+			// Once the blue cluster is drained it gets recreated with the new image
+			GetRabbitMQAPI().SetQueue("/", "notifications", 0)
+			Eventually(func(g Gomega) {
+				g.Expect(GetRabbitMQ(rabbitmqName).Status.Upgrade.Phase).To(Equal(rabbitmqv1.UpgradePhaseRecreatingBlue))
+				g.Expect(GetRabbitMQCluster(rabbitmqName).Spec.Image).To(Equal("quay.io/rabbitmq:4.0"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
 })
@@ -69,6 +69,43 @@ var _ = Describe("RabbitMQPolicy controller", func() {
 		})
 	})
 
+	When("a RabbitMQPolicy is created on a ready cluster", func() {
+		BeforeEach(func() {
+			spec := map[string]any{
+				"rabbitmqClusterName": rabbitmqClusterName.Name,
+				"pattern":             "^notifications\\.",
+				"definition": map[string]interface{}{
+					"max-length": 10000,
+				},
+				"priority": 5,
+			}
+			policy := CreateRabbitMQPolicy(policyName, spec)
+			DeferCleanup(th.DeleteInstance, policy)
+		})
+
+		It("should create the policy in RabbitMQ", func() {
+			Eventually(func(g Gomega) {
+				policy, exists := GetRabbitMQAPI().GetPolicy("/", policyName.Name)
+				g.Expect(exists).To(BeTrue())
+				g.Expect(policy.Pattern).To(Equal("^notifications\\."))
+				g.Expect(policy.Priority).To(Equal(5))
+				g.Expect(policy.Definition).To(HaveKeyWithValue("max-length", BeNumerically("==", 10000)))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("should delete the policy from RabbitMQ when deleted", func() {
+			Eventually(func(g Gomega) {
+				_, exists := GetRabbitMQAPI().GetPolicy("/", policyName.Name)
+				g.Expect(exists).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+
+			th.DeleteInstance(GetRabbitMQPolicy(policyName))
+
+			_, exists := GetRabbitMQAPI().GetPolicy("/", policyName.Name)
+			Expect(exists).To(BeFalse())
+		})
+	})
+
 	When("a RabbitMQPolicy with custom settings is created", func() {
 		BeforeEach(func() {
 			spec := map[string]any{
@@ -20,7 +20,9 @@ import (
 	. "github.com/onsi/ginkgo/v2" //nolint:revive
 	. "github.com/onsi/gomega"    //nolint:revive
 	rabbitmqv1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
+	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers" //nolint:revive
 	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -81,6 +83,57 @@ var _ = Describe("RabbitMQUser controller", func() {
 		})
 	})
 
+	When("a RabbitMQUser is created on a ready cluster", func() {
+		BeforeEach(func() {
+			spec := map[string]any{
+				"rabbitmqClusterName": rabbitmqClusterName.Name,
+				"vhostRef":            vhostName.Name,
+				"username":            "nova",
+				"permissions": map[string]any{
+					"configure": "^nova.*",
+					"write":     ".*",
+					"read":      ".*",
+				},
+			}
+			user := CreateRabbitMQUser(userName, spec)
+			DeferCleanup(th.DeleteInstance, user)
+		})
+
+		It("should create the user and its permissions in RabbitMQ", func() {
+			th.ExpectCondition(
+				userName,
+				ConditionGetterFunc(RabbitMQUserConditionGetter),
+				rabbitmqv1.RabbitMQUserReadyCondition,
+				corev1.ConditionTrue,
+			)
+
+			secret := th.GetSecret(types.NamespacedName{Name: GetRabbitMQUser(userName).Status.SecretName, Namespace: namespace})
+			apiUser, exists := GetRabbitMQAPI().GetUser("nova")
+			Expect(exists).To(BeTrue())
+			Expect(apiUser.Password).To(Equal(string(secret.Data["password"])))
+
+			perm, exists := GetRabbitMQAPI().GetPermissions("test", "nova")
+			Expect(exists).To(BeTrue())
+			Expect(perm.Configure).To(Equal("^nova.*"))
+			Expect(perm.Write).To(Equal(".*"))
+			Expect(perm.Read).To(Equal(".*"))
+		})
+
+		It("should delete the user from RabbitMQ when deleted", func() {
+			Eventually(func(g Gomega) {
+				_, exists := GetRabbitMQAPI().GetUser("nova")
+				g.Expect(exists).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+
+			th.DeleteInstance(GetRabbitMQUser(userName))
+
+			_, exists := GetRabbitMQAPI().GetUser("nova")
+			Expect(exists).To(BeFalse())
+			_, exists = GetRabbitMQAPI().GetPermissions("test", "nova")
+			Expect(exists).To(BeFalse())
+		})
+	})
+
 	When("a RabbitMQUser with custom username is created", func() {
 		BeforeEach(func() {
 			spec := map[string]any{
@@ -17,11 +17,19 @@ limitations under the License.
 package functional_test
 
 import (
+	"fmt"
+	"net/http"
+
 	. "github.com/onsi/ginkgo/v2" //nolint:revive
 	. "github.com/onsi/gomega"    //nolint:revive
 	rabbitmqv1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
+	rabbitmqapifake "github.com/openstack-k8s-operators/infra-operator/pkg/rabbitmq/api/fake"
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers" //nolint:revive
 	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("RabbitMQVhost controller", func() {
@@ -72,6 +80,102 @@ var _ = Describe("RabbitMQVhost controller", func() {
 		})
 	})
 
+	When("a RabbitMQVhost is created on a ready cluster", func() {
+		BeforeEach(func() {
+			spec := map[string]any{
+				"rabbitmqClusterName": rabbitmqClusterName.Name,
+				"name":                "test",
+			}
+			vhost := CreateRabbitMQVhost(vhostName, spec)
+			DeferCleanup(th.DeleteInstance, vhost)
+		})
+
+		It("should create the vhost in RabbitMQ", func() {
+			th.ExpectCondition(
+				vhostName,
+				ConditionGetterFunc(RabbitMQVhostConditionGetter),
+				rabbitmqv1.RabbitMQVhostReadyCondition,
+				corev1.ConditionTrue,
+			)
+			Expect(GetRabbitMQAPI().HasVhost("test")).To(BeTrue())
+		})
+
+		It("should delete the vhost from RabbitMQ when deleted", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(GetRabbitMQAPI().HasVhost("test")).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+
+			th.DeleteInstance(GetRabbitMQVhost(vhostName))
+
+			Expect(GetRabbitMQAPI().HasVhost("test")).To(BeFalse())
+		})
+	})
+
+	When("the RabbitMQ management API fails", func() {
+		BeforeEach(func() {
+			GetRabbitMQAPI().InjectFailure(rabbitmqapifake.Failure{
+				Method:     "PUT",
+				Path:       "/api/vhosts/",
+				StatusCode: http.StatusServiceUnavailable,
+			})
+			DeferCleanup(GetRabbitMQAPI().ClearFailures)
+
+			spec := map[string]any{
+				"rabbitmqClusterName": rabbitmqClusterName.Name,
+				"name":                "test",
+			}
+			vhost := CreateRabbitMQVhost(vhostName, spec)
+			DeferCleanup(th.DeleteInstance, vhost)
+		})
+
+		It("should report the reason in the condition and an event", func() {
+			th.ExpectConditionWithDetails(
+				vhostName,
+				ConditionGetterFunc(RabbitMQVhostConditionGetter),
+				rabbitmqv1.RabbitMQVhostReadyCondition,
+				corev1.ConditionFalse,
+				condition.ErrorReason,
+				fmt.Sprintf(rabbitmqv1.RabbitMQVhostReadyErrorMessage,
+					"failed to create/update vhost test: status 503, error: service_unavailable, reason: injected failure"),
+			)
+
+			Eventually(func(g Gomega) {
+				events := &corev1.EventList{}
+				g.Expect(k8sClient.List(ctx, events, client.InNamespace(namespace))).Should(Succeed())
+				found := false
+				for _, event := range events.Items {
+					if event.InvolvedObject.Name == vhostName.Name &&
+						event.Type == corev1.EventTypeWarning &&
+						event.Reason == "RabbitMQAPIError" {
+						found = true
+						g.Expect(event.Message).To(ContainSubstring("vhost test"))
+					}
+				}
+				g.Expect(found).To(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("should report the error and recover once the API is available", func() {
+			th.ExpectCondition(
+				vhostName,
+				ConditionGetterFunc(RabbitMQVhostConditionGetter),
+				rabbitmqv1.RabbitMQVhostReadyCondition,
+				corev1.ConditionFalse,
+			)
+			Expect(GetRabbitMQAPI().HasVhost("test")).To(BeFalse())
+
+			GetRabbitMQAPI().ClearFailures()
+
+			th.ExpectCondition(
+				vhostName,
+				ConditionGetterFunc(RabbitMQVhostConditionGetter),
+				rabbitmqv1.RabbitMQVhostReadyCondition,
+				corev1.ConditionTrue,
+			)
+			Expect(GetRabbitMQAPI().HasVhost("test")).To(BeTrue())
+		})
+	})
+
 	When("a RabbitMQVhost with default name is created", func() {
 		BeforeEach(func() {
 			spec := map[string]any{
@@ -0,0 +1,73 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package functional_test
+
+import (
+	. "github.com/onsi/ginkgo/v2" //revive:disable:dot-imports
+	. "github.com/onsi/gomega"    //revive:disable:dot-imports
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("RedisUser webhook", func() {
+	var userName types.NamespacedName
+
+	// createRedisUser creates a RedisUser through the webhook and returns its error
+	createRedisUser := func(name string, spec map[string]any) error {
+		raw := map[string]any{
+			"apiVersion": "redis.openstack.org/v1beta1",
+			"kind":       "RedisUser",
+			"metadata": map[string]any{
+				"name":      name,
+				"namespace": namespace,
+			},
+			"spec": spec,
+		}
+		_, err := controllerutil.CreateOrPatch(
+			th.Ctx, th.K8sClient, &unstructured.Unstructured{Object: raw}, func() error { return nil })
+		return err
+	}
+
+	When("a RedisUser exists", func() {
+		BeforeEach(func() {
+			userName = types.NamespacedName{Name: "nova", Namespace: namespace}
+			DeferCleanup(th.DeleteInstance, CreateRedisUser(userName, map[string]any{
+				"redisName": "redis",
+			}))
+		})
+
+		It("rejects another user of the same redis with the same username", func() {
+			err := createRedisUser("nova-copy", map[string]any{
+				"redisName": "redis",
+				"username":  "nova",
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("username \"nova\" already exists on redis \"redis\""))
+		})
+
+		It("accepts the same username on another redis", func() {
+			err := createRedisUser("nova-other", map[string]any{
+				"redisName": "redis-other",
+				"username":  "nova",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(th.DeleteInstance, GetRedisUser(types.NamespacedName{Name: "nova-other", Namespace: namespace}))
+		})
+	})
+})
@@ -19,6 +19,7 @@ package functional_test
 import (
 	"context"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -26,6 +27,7 @@ import (
 	. "github.com/onsi/ginkgo/v2" //revive:disable:dot-imports
 	. "github.com/onsi/gomega"    //revive:disable:dot-imports
 	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -45,15 +47,19 @@ import (
 	memcachedv1 "github.com/openstack-k8s-operators/infra-operator/apis/memcached/v1beta1"
 	networkv1 "github.com/openstack-k8s-operators/infra-operator/apis/network/v1beta1"
 	rabbitmqv1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
+	redisv1 "github.com/openstack-k8s-operators/infra-operator/apis/redis/v1beta1"
 	rabbitmqclusterv2 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 
 	memcached_ctrl "github.com/openstack-k8s-operators/infra-operator/internal/controller/memcached"
 	network_ctrl "github.com/openstack-k8s-operators/infra-operator/internal/controller/network"
 	rabbitmq_ctrl "github.com/openstack-k8s-operators/infra-operator/internal/controller/rabbitmq"
+	"github.com/openstack-k8s-operators/infra-operator/internal/memcached"
+	rabbitmqapifake "github.com/openstack-k8s-operators/infra-operator/pkg/rabbitmq/api/fake"
 
 	webhookmemcachedv1beta1 "github.com/openstack-k8s-operators/infra-operator/internal/webhook/memcached/v1beta1"
 	webhooknetworkv1beta1 "github.com/openstack-k8s-operators/infra-operator/internal/webhook/network/v1beta1"
 	webhookrabbitmqv1beta1 "github.com/openstack-k8s-operators/infra-operator/internal/webhook/rabbitmq/v1beta1"
+	webhookredisv1beta1 "github.com/openstack-k8s-operators/infra-operator/internal/webhook/redis/v1beta1"
 
 	ocp_configv1 "github.com/openshift/api/config/v1"
 	infra_test "github.com/openstack-k8s-operators/infra-operator/apis/test/helpers"
@@ -74,6 +80,10 @@ var (
 	logger    logr.Logger
 	namespace string
 	th        *infra_test.TestHelper
+	// rabbitmqAPI serves the RabbitMQ management API requests of the controllers
+	rabbitmqAPI *rabbitmqapifake.Transport
+	// certReloads records the pods asked to reload their TLS certificates
+	certReloads sync.Map
 )
 
 func TestAPIs(t *testing.T) {
@@ -144,6 +154,8 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	err = memcachedv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = redisv1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 	err = k8s_networkv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 	err = frrk8sv1.AddToScheme(scheme.Scheme)
@@ -188,6 +200,10 @@ var _ = BeforeSuite(func() {
 	kclient, err := kubernetes.NewForConfig(cfg)
 	Expect(err).ToNot(HaveOccurred(), "failed to create kclient")
 
+	// route the management API requests of the rabbitmq controllers to
+	// in-memory brokers using the credentials of the simulated clusters
+	rabbitmqAPI = rabbitmqapifake.NewTransport("user", "12345678")
+
 	dynClient, err = dynamic.NewForConfig(cfg)
 	Expect(err).NotTo(HaveOccurred())
 	Expect(dynClient).NotTo(BeNil())
@@ -210,6 +226,10 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	err = webhookrabbitmqv1beta1.SetupRabbitMQVhostWebhookWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
+	err = webhookrabbitmqv1beta1.SetupTransportURLWebhookWithManager(k8sManager)
+	Expect(err).NotTo(HaveOccurred())
+	err = webhookredisv1beta1.SetupRedisUserWebhookWithManager(k8sManager)
+	Expect(err).NotTo(HaveOccurred())
 
 	err = (&network_ctrl.DNSMasqReconciler{
 		Client:  k8sManager.GetClient(),
@@ -257,34 +277,49 @@ var _ = BeforeSuite(func() {
 		Client:  k8sManager.GetClient(),
 		Scheme:  k8sManager.GetScheme(),
 		Kclient: kclient,
+		Scraper: func(_ context.Context, _ string) (memcached.ExporterStats, error) {
+			return memcached.ExporterStats{GetHits: 9, GetMisses: 1, Evictions: 2, CurrentConnections: 5, MaxConnections: 8192,
+				CurrentBytes: 60 << 20, LimitBytes: 64 << 20}, nil
+		},
+		CertReloader: func(_ context.Context, pod types.NamespacedName, _ []string) error {
+			certReloads.Store(pod.Name, true)
+			return nil
+		},
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&rabbitmq_ctrl.Reconciler{
-		Client:  k8sManager.GetClient(),
-		Scheme:  k8sManager.GetScheme(),
-		Kclient: kclient,
+		Client:    k8sManager.GetClient(),
+		Scheme:    k8sManager.GetScheme(),
+		Kclient:   kclient,
+		Transport: rabbitmqAPI,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&rabbitmq_ctrl.RabbitMQVhostReconciler{
-		Client:  k8sManager.GetClient(),
-		Scheme:  k8sManager.GetScheme(),
-		Kclient: kclient,
+		Client:    k8sManager.GetClient(),
+		Scheme:    k8sManager.GetScheme(),
+		Kclient:   kclient,
+		Recorder:  k8sManager.GetEventRecorderFor("rabbitmqvhost-controller"),
+		Transport: rabbitmqAPI,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&rabbitmq_ctrl.RabbitMQUserReconciler{
-		Client:  k8sManager.GetClient(),
-		Scheme:  k8sManager.GetScheme(),
-		Kclient: kclient,
+		Client:    k8sManager.GetClient(),
+		Scheme:    k8sManager.GetScheme(),
+		Kclient:   kclient,
+		Recorder:  k8sManager.GetEventRecorderFor("rabbitmquser-controller"),
+		Transport: rabbitmqAPI,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&rabbitmq_ctrl.RabbitMQPolicyReconciler{
-		Client:  k8sManager.GetClient(),
-		Scheme:  k8sManager.GetScheme(),
-		Kclient: kclient,
+		Client:    k8sManager.GetClient(),
+		Scheme:    k8sManager.GetScheme(),
+		Kclient:   kclient,
+		Recorder:  k8sManager.GetEventRecorderFor("rabbitmqpolicy-controller"),
+		Transport: rabbitmqAPI,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -26,8 +26,10 @@ import (
 	//revive:disable-next-line:dot-imports
 	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	rabbitmqv1 "github.com/openstack-k8s-operators/infra-operator/apis/rabbitmq/v1beta1"
 )
@@ -248,6 +250,7 @@ var _ = Describe("TransportURL controller", func() {
 
 		It("should create a secret with multi-host transport URL", func() {
 			SimulateRabbitMQClusterReady(rabbitmqName)
+			SimulateRabbitMQPerPodServicesReady(rabbitmqName, 3)
 
 			// Simulate RabbitMq CR being ready with ServiceHostnames
 			Eventually(func(g Gomega) {
@@ -284,6 +287,28 @@ var _ = Describe("TransportURL controller", func() {
 				corev1.ConditionTrue,
 			)
 		})
+
+		It("should wait for the per-pod service hostnames to resolve", func() {
+			SimulateRabbitMQClusterReady(rabbitmqName)
+
+			// The per-pod services never get a LoadBalancer IP, so their
+			// hostnames cannot resolve
+			Eventually(func(g Gomega) {
+				rabbitmq := GetRabbitMQ(rabbitmqName)
+				g.Expect(rabbitmq.Status.ServiceHostnames).To(HaveLen(3))
+				g.Expect(rabbitmq.Status.Conditions.IsTrue(rabbitmqv1.RabbitMqServiceDNSReadyCondition)).To(BeFalse())
+			}, timeout, interval).Should(Succeed())
+
+			th.ExpectConditionWithDetails(
+				transportURLName,
+				ConditionGetterFunc(TransportURLConditionGetter),
+				rabbitmqv1.TransportURLReadyCondition,
+				corev1.ConditionFalse,
+				condition.RequestedReason,
+				fmt.Sprintf(rabbitmqv1.TransportURLWaitingServiceDNSMessage, rabbitmqName.Name),
+			)
+			th.AssertSecretDoesNotExist(transportURLSecretName)
+		})
 	})
 
 	When("a TransportURL gets created with RabbitMQ using per-pod services and custom vhost", func() {
@@ -369,6 +394,7 @@ var _ = Describe("TransportURL controller", func() {
 			}, timeout, interval).Should(Succeed())
 			// Now simulate it as ready
 			SimulateRabbitMQVhostReady(vhostCRName)
+			SimulateRabbitMQPerPodServicesReady(rabbitmqName, 3)
 
 			// Simulate RabbitMq CR being ready with ServiceHostnames
 			Eventually(func(g Gomega) {
@@ -445,4 +471,160 @@ var _ = Describe("TransportURL controller", func() {
 			Expect(tr.Status.RabbitmqVhost).To(Or(Equal("nova"), Equal("/nova")))
 		})
 	})
+
+	When("a TransportURL in another namespace references a RabbitMq without allowedNamespaces", func() {
+		var tenantTransportURLName types.NamespacedName
+
+		BeforeEach(func() {
+			tenantNS := th.CreateNamespace("tenant-" + namespace)
+			DeferCleanup(th.DeleteNamespace, tenantNS.Name)
+			tenantTransportURLName = types.NamespacedName{
+				Name:      "foo",
+				Namespace: tenantNS.Name,
+			}
+
+			CreateRabbitMQCluster(rabbitmqClusterName, GetDefaultRabbitMQClusterSpec(false))
+			DeferCleanup(DeleteRabbitMQCluster, rabbitmqClusterName)
+			DeferCleanup(th.DeleteInstance, CreateRabbitMQ(rabbitmqClusterName, GetDefaultRabbitMQSpec()))
+
+			spec := map[string]any{
+				"rabbitmqClusterName":      rabbitmqClusterName.Name,
+				"rabbitmqClusterNamespace": namespace,
+				"username":                 "nova-user",
+			}
+			DeferCleanup(th.DeleteInstance, CreateTransportURL(tenantTransportURLName, spec))
+		})
+
+		It("should refuse to bind to the cluster", func() {
+			SimulateRabbitMQClusterReady(rabbitmqClusterName)
+
+			th.ExpectConditionWithDetails(
+				tenantTransportURLName,
+				ConditionGetterFunc(TransportURLConditionGetter),
+				rabbitmqv1.TransportURLReadyCondition,
+				corev1.ConditionFalse,
+				condition.ErrorReason,
+				fmt.Sprintf(rabbitmqv1.RabbitMqNamespaceNotAllowedMessage,
+					tenantTransportURLName.Namespace, namespace, rabbitmqClusterName.Name),
+			)
+
+			userList := &rabbitmqv1.RabbitMQUserList{}
+			Expect(k8sClient.List(ctx, userList, client.InNamespace(namespace))).Should(Succeed())
+			Expect(userList.Items).To(BeEmpty())
+		})
+	})
+
+	When("a TransportURL in another namespace does not name its own user", func() {
+		var tenantNamespace string
+
+		BeforeEach(func() {
+			tenantNS := th.CreateNamespace("tenant-" + namespace)
+			DeferCleanup(th.DeleteNamespace, tenantNS.Name)
+			tenantNamespace = tenantNS.Name
+		})
+
+		createTenantTransportURL := func(spec map[string]any) error {
+			raw := map[string]any{
+				"apiVersion": "rabbitmq.openstack.org/v1beta1",
+				"kind":       "TransportURL",
+				"metadata": map[string]any{
+					"name":      "foo",
+					"namespace": tenantNamespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				th.Ctx, th.K8sClient, unstructuredObj, func() error { return nil })
+			return err
+		}
+
+		It("gets blocked by the webhook without a username", func() {
+			err := createTenantTransportURL(map[string]any{
+				"rabbitmqClusterName":      rabbitmqClusterName.Name,
+				"rabbitmqClusterNamespace": namespace,
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("username is required together with rabbitmqClusterNamespace"))
+		})
+
+		It("gets blocked by the webhook with a userRef", func() {
+			err := createTenantTransportURL(map[string]any{
+				"rabbitmqClusterName":      rabbitmqClusterName.Name,
+				"rabbitmqClusterNamespace": namespace,
+				"userRef":                  "other-tenant-user",
+				"username":                 "nova-user",
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("userRef is not supported together with rabbitmqClusterNamespace"))
+		})
+	})
+
+	When("a TransportURL in an allowed namespace references a RabbitMq", func() {
+		var tenantTransportURLName types.NamespacedName
+		var clusterUserName types.NamespacedName
+
+		BeforeEach(func() {
+			tenantNS := th.CreateNamespace("tenant-" + namespace)
+			DeferCleanup(th.DeleteNamespace, tenantNS.Name)
+			tenantTransportURLName = types.NamespacedName{
+				Name:      "foo",
+				Namespace: tenantNS.Name,
+			}
+			clusterUserName = types.NamespacedName{
+				Name:      fmt.Sprintf("%s-%s-nova-user-user", tenantNS.Name, tenantTransportURLName.Name),
+				Namespace: namespace,
+			}
+
+			CreateRabbitMQCluster(rabbitmqClusterName, GetDefaultRabbitMQClusterSpec(false))
+			DeferCleanup(DeleteRabbitMQCluster, rabbitmqClusterName)
+			rabbitmqSpec := GetDefaultRabbitMQSpec()
+			rabbitmqSpec["allowedNamespaces"] = []string{tenantNS.Name}
+			DeferCleanup(th.DeleteInstance, CreateRabbitMQ(rabbitmqClusterName, rabbitmqSpec))
+
+			spec := map[string]any{
+				"rabbitmqClusterName":      rabbitmqClusterName.Name,
+				"rabbitmqClusterNamespace": namespace,
+				"username":                 "nova-user",
+			}
+			DeferCleanup(th.DeleteInstance, CreateTransportURL(tenantTransportURLName, spec))
+		})
+
+		It("should create the user in the cluster namespace and mirror its credentials", func() {
+			SimulateRabbitMQClusterReady(rabbitmqClusterName)
+
+			user := GetRabbitMQUser(clusterUserName)
+			Expect(user.Labels).To(HaveKeyWithValue(rabbitmqv1.TransportURLNameLabel, tenantTransportURLName.Name))
+			Expect(user.Labels).To(HaveKeyWithValue(rabbitmqv1.TransportURLNamespaceLabel, tenantTransportURLName.Namespace))
+			Expect(user.OwnerReferences).To(BeEmpty())
+
+			var userPassword string
+			Eventually(func(g Gomega) {
+				user := GetRabbitMQUser(clusterUserName)
+				g.Expect(user.Status.SecretName).ToNot(BeEmpty())
+				userSecret := th.GetSecret(types.NamespacedName{Name: user.Status.SecretName, Namespace: namespace})
+				userPassword = string(userSecret.Data["password"])
+				g.Expect(userPassword).ToNot(BeEmpty())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				tr := th.GetTransportURL(tenantTransportURLName)
+				g.Expect(tr.Status.UserSecretName).To(Equal("rabbitmq-transport-url-foo-user"))
+
+				mirror := th.GetSecret(types.NamespacedName{Name: tr.Status.UserSecretName, Namespace: tenantTransportURLName.Namespace})
+				g.Expect(mirror.Data).To(HaveKeyWithValue("username", []byte("nova-user")))
+				g.Expect(mirror.Data).To(HaveKeyWithValue("password", []byte(userPassword)))
+
+				s := th.GetSecret(types.NamespacedName{Name: "rabbitmq-transport-url-foo", Namespace: tenantTransportURLName.Namespace})
+				g.Expect(string(s.Data["transport_url"])).To(ContainSubstring(fmt.Sprintf("nova-user:%s@", userPassword)))
+			}, timeout, interval).Should(Succeed())
+
+			th.ExpectCondition(
+				tenantTransportURLName,
+				ConditionGetterFunc(TransportURLConditionGetter),
+				rabbitmqv1.TransportURLReadyCondition,
+				corev1.ConditionTrue,
+			)
+		})
+	})
 })